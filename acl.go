@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pelletier/go-toml"
+)
+
+// ACLRule grants users and groups access to filespec groups and commands. The
+// special value "*" matches any user, group, filespec group or command.
+type ACLRule struct {
+	Users    []string
+	Groups   []string
+	Files    []string
+	Commands []string
+}
+
+// ACL maps authenticated identities to the filespec groups and commands that
+// they can use. A user has the union of the access granted by all rules that
+// match them. A nil ACL allows everything.
+type ACL struct {
+	// Additional group memberships, on top of the groups that come with the
+	// identity (e.g. from a reverse proxy or a client certificate).
+	Groups map[string][]string
+	Rules  []ACLRule
+}
+
+// Check if a rule applies to an identity.
+func (acl *ACL) matches(rule ACLRule, identity *Identity) bool {
+	if identity == nil {
+		return false
+	}
+
+	if slices.Contains(rule.Users, "*") || slices.Contains(rule.Users, identity.User) {
+		return true
+	}
+
+	for _, group := range rule.Groups {
+		if group == "*" || slices.Contains(identity.Groups, group) || slices.Contains(acl.Groups[group], identity.User) {
+			return true
+		}
+	}
+	return false
+}
+
+func (acl *ACL) allows(identity *Identity, item string, field func(ACLRule) []string) bool {
+	if acl == nil {
+		return true
+	}
+
+	for _, rule := range acl.Rules {
+		values := field(rule)
+		if (slices.Contains(values, "*") || slices.Contains(values, item)) && acl.matches(rule, identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowFileGroup checks if an identity can see the files in a filespec group.
+// Files that are not in a group are in the "__default__" group.
+func (acl *ACL) AllowFileGroup(identity *Identity, group string) bool {
+	return acl.allows(identity, group, func(rule ACLRule) []string { return rule.Files })
+}
+
+// AllowCommand checks if an identity can run a command from allow-commands.
+func (acl *ACL) AllowCommand(identity *Identity, command string) bool {
+	return acl.allows(identity, command, func(rule ACLRule) []string { return rule.Commands })
+}
+
+// Return the allowed commands that an identity can run.
+func allowedCommands(identity *Identity) []string {
+	config := currentConfig()
+	var res []string
+	for _, name := range config.AllowCommandNames {
+		if config.ACL.AllowCommand(identity, name) {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// Parse the [groups] table and the [[acl]] array of tables of a config file.
+// Access control is disabled if there are no [[acl]] tables. For example:
+//
+//	[groups]
+//	ops = ["alice", "bob"]
+//
+//	[[acl]]
+//	groups = ["ops"]
+//	files = ["*"]
+//	commands = ["tail", "grep"]
+func parseACLConfig(cfg *toml.Tree, commands map[string]CommandSpec) (*ACL, error) {
+	acl := &ACL{Groups: make(map[string][]string)}
+
+	if cfg.Has("groups") {
+		table, ok := cfg.Get("groups").(*toml.Tree)
+		if !ok {
+			return nil, fmt.Errorf("groups at line %d: expected a table", cfg.GetPosition("groups").Line)
+		}
+		if err := mapstructure.Decode(table.ToMap(), &acl.Groups); err != nil {
+			return nil, fmt.Errorf("[groups] at line %d: %s", table.Position().Line, err)
+		}
+	}
+
+	switch tables := cfg.Get("acl").(type) {
+	case nil:
+		return nil, nil
+	case []*toml.Tree:
+		for n, table := range tables {
+			rule := ACLRule{}
+			decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{ErrorUnused: true, Result: &rule})
+			err := decoder.Decode(table.ToMap())
+			if err == nil {
+				err = rule.validate(commands)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("[[acl]] entry %d at line %d: %s", n+1, table.Position().Line, err)
+			}
+			acl.Rules = append(acl.Rules, rule)
+		}
+	default:
+		return nil, fmt.Errorf("acl at line %d: expected an array of tables", cfg.GetPosition("acl").Line)
+	}
+
+	return acl, nil
+}
+
+func (rule ACLRule) validate(commands map[string]CommandSpec) error {
+	if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+		return fmt.Errorf("a rule needs users or groups")
+	}
+	for _, name := range rule.Commands {
+		if _, ok := commands[name]; !ok && name != "*" {
+			return fmt.Errorf("unknown command %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+)
+
+func TestACL(t *testing.T) {
+	commands := map[string]CommandSpec{"tail": {}, "grep": {}}
+	cfg, _ := toml.Load(`
+	[groups]
+	ops = ["alice"]
+
+	[[acl]]
+	groups = ["ops"]
+	files = ["*"]
+	commands = ["*"]
+
+	[[acl]]
+	users = ["*"]
+	files = ["a"]
+	commands = ["tail"]
+	`)
+
+	acl, err := parseACLConfig(cfg, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice := &Identity{User: "alice"}
+	bob := &Identity{User: "bob"}
+	carol := &Identity{User: "carol", Groups: []string{"ops"}}
+
+	tests := []struct {
+		identity *Identity
+		group    string
+		command  string
+		files    bool
+		commands bool
+	}{
+		{alice, "b", "grep", true, true},
+		{carol, "b", "grep", true, true},
+		{bob, "a", "tail", true, true},
+		{bob, "b", "grep", false, false},
+		{nil, "a", "tail", false, false},
+	}
+	for n, test := range tests {
+		if acl.AllowFileGroup(test.identity, test.group) != test.files {
+			t.Fatalf("test %d: expected %t for group %s", n, test.files, test.group)
+		}
+		if acl.AllowCommand(test.identity, test.command) != test.commands {
+			t.Fatalf("test %d: expected %t for command %s", n, test.commands, test.command)
+		}
+	}
+
+	// The listing and the file allowlist are filtered by group.
+	setConfig(&Config{ACL: acl})
+	defer setConfig(&Config{})
+
+	spec1, _ := parseFileSpec("group=a,testdata/ex1/var/log/1.log")
+	spec2, _ := parseFileSpec("group=b,testdata/ex1/var/log/2.log")
+	listing := createListing([]FileSpec{spec1, spec2})
+	if lst := listing.visible(bob); len(lst) != 1 || lst["a"] == nil {
+		t.Fatalf("unexpected listing: %v", lst)
+	}
+	if !listing.allowed("testdata/ex1/var/log/1.log", bob) || listing.allowed("testdata/ex1/var/log/2.log", bob) {
+		t.Fatal()
+	}
+	if !listing.allowed("testdata/ex1/var/log/2.log", alice) {
+		t.Fatal()
+	}
+
+	// A nil ACL allows everything.
+	if !(*ACL)(nil).AllowCommand(nil, "grep") {
+		t.Fatal()
+	}
+	if acl, err := parseACLConfig(&toml.Tree{}, commands); acl != nil || err != nil {
+		t.Fatal()
+	}
+
+	errors := map[string]string{
+		"[[acl]]\nfiles = ['*']":                   "[[acl]] entry 1 at line 1: a rule needs users or groups",
+		"[[acl]]\nusers = ['*']\ncommands = ['x']": `[[acl]] entry 1 at line 1: unknown command "x"`,
+		"groups = 1\n[[acl]]\nusers = ['*']":       "groups at line 1: expected a table",
+	}
+	for content, prefix := range errors {
+		cfg, _ := toml.Load(content)
+		if _, err := parseACLConfig(cfg, commands); err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("%q: %v does not start with %q", content, err, prefix)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// admission counts the websocket sessions and running commands, in total and
+// per client address, and rejects new ones over the limits in config.
+type admission struct {
+	mu       sync.Mutex
+	sessions map[string]int
+	commands map[string]int
+
+	totalSessions int
+	totalCommands int
+}
+
+var admissions = newAdmission()
+
+func newAdmission() *admission {
+	return &admission{sessions: make(map[string]int), commands: make(map[string]int)}
+}
+
+// Admit a session of a client, which must be released when it ends.
+func (a *admission) admitSession(client string) error {
+	config := currentConfig()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if config.MaxSessions > 0 && a.totalSessions >= config.MaxSessions {
+		return fmt.Errorf("too many sessions (at most %d)", config.MaxSessions)
+	}
+	if config.MaxSessionsPerClient > 0 && a.sessions[client] >= config.MaxSessionsPerClient {
+		return fmt.Errorf("too many sessions from %s (at most %d)", client, config.MaxSessionsPerClient)
+	}
+	a.totalSessions++
+	a.sessions[client]++
+	return nil
+}
+
+func (a *admission) releaseSession(client string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.totalSessions--
+	if a.sessions[client]--; a.sessions[client] <= 0 {
+		delete(a.sessions, client)
+	}
+}
+
+// Admit n commands of a client, which must be released when they finish.
+func (a *admission) admitCommands(client string, n int) error {
+	config := currentConfig()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if config.MaxCommands > 0 && a.totalCommands+n > config.MaxCommands {
+		return fmt.Errorf("too many running commands (at most %d)", config.MaxCommands)
+	}
+	if config.MaxCommandsPerClient > 0 && a.commands[client]+n > config.MaxCommandsPerClient {
+		return fmt.Errorf("too many running commands from %s (at most %d)", client, config.MaxCommandsPerClient)
+	}
+	a.totalCommands += n
+	a.commands[client] += n
+	return nil
+}
+
+func (a *admission) releaseCommands(client string, n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.totalCommands -= n
+	if a.commands[client] -= n; a.commands[client] <= 0 {
+		delete(a.commands, client)
+	}
+}
+
+// The address that the admission limits of a request count against. All
+// connections through a unix socket share one address.
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
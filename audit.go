@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pelletier/go-toml"
+)
+
+// AuditEvent is a line of the audit log. Commands are logged when they start
+// and when they end, downloads when they finish.
+type AuditEvent struct {
+	Time   time.Time `json:"time"`
+	Event  string    `json:"event"`
+	Remote string    `json:"remote"`
+	User   string    `json:"user,omitempty"`
+
+	// The command that was run, the expanded arguments of each of its
+	// processes and the file that it was run for (or files, if the output of
+	// several files was merged).
+	Command string     `json:"command,omitempty"`
+	Argv    [][]string `json:"argv,omitempty"`
+	Path    string     `json:"path,omitempty"`
+	Paths   []string   `json:"paths,omitempty"`
+
+	// How long the command ran, in seconds, and how its processes exited.
+	Duration float64         `json:"duration,omitempty"`
+	Status   []processStatus `json:"status,omitempty"`
+
+	// The response code and size of a download.
+	Code  int   `json:"code,omitempty"`
+	Bytes int64 `json:"bytes,omitempty"`
+}
+
+// Events of the audit log.
+const (
+	auditCommandStarted = "command-started"
+	auditCommandEnded   = "command-ended"
+	auditDownload       = "download"
+)
+
+// AuditSpec is the [audit] table of the config file.
+type AuditSpec struct {
+	File       string
+	MaxSize    string `mapstructure:"max-size"`
+	MaxBackups int    `mapstructure:"max-backups"`
+
+	maxSize uint64
+}
+
+// The defaults of the [audit] table.
+const (
+	defaultAuditMaxSize    = "100M"
+	defaultAuditMaxBackups = 5
+)
+
+// Parse the [audit] table of the config file. The audit log is disabled if
+// there is no [audit] table.
+func parseAuditConfig(cfg *toml.Tree) (*AuditSpec, error) {
+	table, ok := cfg.Get("audit").(*toml.Tree)
+	if !ok {
+		if cfg.Has("audit") {
+			return nil, fmt.Errorf("audit at line %d: expected a table", cfg.GetPosition("audit").Line)
+		}
+		return nil, nil
+	}
+
+	spec := &AuditSpec{MaxSize: defaultAuditMaxSize, MaxBackups: defaultAuditMaxBackups}
+	decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{ErrorUnused: true, Result: spec})
+	err := decoder.Decode(table.ToMap())
+	if err == nil && spec.File == "" {
+		err = fmt.Errorf("missing file")
+	}
+	if err == nil {
+		if spec.maxSize, err = parseSize(spec.MaxSize); err != nil {
+			err = fmt.Errorf("invalid max-size %q", spec.MaxSize)
+		}
+	}
+	if err == nil && spec.MaxBackups < 0 {
+		err = fmt.Errorf("max-backups cannot be negative")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[audit] at line %d: %s", table.Position().Line, err)
+	}
+	return spec, nil
+}
+
+// The audit log, or nil if it is disabled.
+var auditLog *auditLogger
+
+// auditLogger writes audit events as JSON lines.
+type auditLogger struct {
+	file *rotatingFile
+}
+
+func openAuditLog(spec *AuditSpec) (*auditLogger, error) {
+	file, err := openRotatingFile(spec.File, int64(spec.maxSize), spec.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLogger{file: file}, nil
+}
+
+// Write an event to the audit log, if it is enabled.
+func (a *auditLogger) log(event AuditEvent) {
+	if a == nil {
+		return
+	}
+	event.Time = time.Now()
+	data, _ := json.Marshal(event)
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		slog.Error("Cannot write audit log", "error", err)
+	}
+}
+
+// The user name of an identity, which is empty if authentication is disabled.
+func identityUser(identity *Identity) string {
+	if identity == nil {
+		return ""
+	}
+	return identity.User
+}
+
+// --------------------------------------------------------------------------
+
+// rotatingFile is a file that is rotated once it grows beyond a size: the
+// file is renamed to path.1, path.1 to path.2 and so on, keeping up to
+// maxBackups old files.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write p, rotating the file first if p would not fit. Writes are never split
+// across files.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	f.file.Close()
+
+	if f.maxBackups == 0 {
+		os.Remove(f.path)
+	} else {
+		for n := f.maxBackups - 1; n > 0; n-- {
+			os.Rename(fmt.Sprintf("%s.%d", f.path, n), fmt.Sprintf("%s.%d", f.path, n+1))
+		}
+		if err := os.Rename(f.path, f.path+".1"); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+)
+
+func TestParseAuditConfig(t *testing.T) {
+	cfg, _ := toml.Load("[audit]\nfile = 'audit.log'\nmax-size = '1K'")
+	spec, err := parseAuditConfig(cfg)
+	if err != nil || spec.File != "audit.log" || spec.maxSize != 1024 || spec.MaxBackups != defaultAuditMaxBackups {
+		t.Fatal(spec, err)
+	}
+
+	cfg, _ = toml.Load("")
+	if spec, err := parseAuditConfig(cfg); spec != nil || err != nil {
+		t.Fatal(spec, err)
+	}
+
+	errors := map[string]string{
+		"audit = 1":                             "audit at line 1: expected a table",
+		"[audit]\nmax-size = '1K'":              "[audit] at line 1: missing file",
+		"[audit]\nfile = 'a'\nmax-size = 'x'":   `[audit] at line 1: invalid max-size "x"`,
+		"[audit]\nfile = 'a'\nmax-backups = -1": "[audit] at line 1: max-backups cannot be negative",
+		"\n[audit]\nfile = 'a'\nrotate = true":  "[audit] at line 2: ",
+	}
+	for content, prefix := range errors {
+		cfg, _ := toml.Load(content)
+		if _, err := parseAuditConfig(cfg); err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("%q: %v does not start with %q", content, err, prefix)
+		}
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	f, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "ffffffffffff\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expect := map[string]string{
+		path:        "ffffffffffff\n",
+		path + ".1": "eeee\n",
+		path + ".2": "cccc\ndddd\n",
+	}
+	for name, content := range expect {
+		if data, _ := os.ReadFile(name); string(data) != content {
+			t.Fatalf("%s: %q != %q", name, data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatal("too many backups")
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := openAuditLog(&AuditSpec{File: path, maxSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog = logger
+	t.Cleanup(func() {
+		auditLog = nil
+		logger.file.Close()
+	})
+
+	server := startTestServer(t, map[string]CommandSpec{
+		"audit-cat": {Action: []string{"cat", "$path"}},
+	}, func(config *Config) {
+		config.AllowDownload = true
+	})
+
+	client := dialWS(t, server)
+	client.send(`{"v": 1, "type": "stream", "id": "1", "payload": {"command": "audit-cat", "entry": {"path": "testdata/ex1/var/log/1.log"}}}`)
+	client.recvUntil("1", "ended")
+
+	res, err := http.Get(server.URL + "/files/?path=testdata/ex1/var/log/1.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	var events []AuditEvent
+	eventually(t, "audit events", func() bool {
+		data, _ := os.ReadFile(path)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if len(lines) < 3 {
+			return false
+		}
+		events = make([]AuditEvent, len(lines))
+		for n, line := range lines {
+			if err := json.Unmarshal([]byte(line), &events[n]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return true
+	})
+
+	started, ended, download := events[0], events[1], events[2]
+	if started.Event != auditCommandStarted || started.Command != "audit-cat" || started.Path != "testdata/ex1/var/log/1.log" ||
+		len(started.Argv) != 1 || started.Argv[0][1] != "testdata/ex1/var/log/1.log" || started.Remote == "" {
+		t.Fatalf("%+v", started)
+	}
+	if ended.Event != auditCommandEnded || len(ended.Status) != 1 || ended.Status[0].Code != 0 || ended.Duration <= 0 {
+		t.Fatalf("%+v", ended)
+	}
+	if download.Event != auditDownload || download.Code != http.StatusOK || download.Bytes == 0 || download.Path == "" {
+		t.Fatalf("%+v", download)
+	}
+}
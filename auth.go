@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pelletier/go-toml"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Identity is an authenticated user. Subject is the distinguished name of the
+// client certificate, if the user was identified by one.
+type Identity struct {
+	User    string
+	Groups  []string
+	Subject string
+	Method  string
+}
+
+// Authenticator establishes the identity of the user making a request.
+type Authenticator interface {
+	// Authenticate returns the identity of the user making the request, or
+	// nil if the request does not carry valid credentials for this method.
+	Authenticate(r *http.Request) *Identity
+
+	// Challenge returns the value of the WWW-Authenticate header that is sent
+	// with 401 responses, or an empty string.
+	Challenge() string
+}
+
+type identityKey struct{}
+
+// Return the identity of an authenticated request, or nil if authentication
+// is disabled.
+func requestIdentity(r *http.Request) *Identity {
+	identity, _ := r.Context().Value(identityKey{}).(*Identity)
+	return identity
+}
+
+// Wrap a handler so that only requests that pass one of the authenticators
+// reach it. The identity of the user is stored in the request context. This
+// applies to the sockjs handshake and transports as much as to any other route.
+func authHandler(authenticators []Authenticator, h http.Handler) http.Handler {
+	if len(authenticators) == 0 {
+		return h
+	}
+
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		for _, auth := range authenticators {
+			if identity := auth.Authenticate(r); identity != nil {
+				ctx := context.WithValue(r.Context(), identityKey{}, identity)
+				ctx = context.WithValue(ctx, loggerKey{}, requestLogger(r).With("user", identity.User))
+				h.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+		}
+
+		for _, auth := range authenticators {
+			if challenge := auth.Challenge(); challenge != "" {
+				w.Header().Add("WWW-Authenticate", challenge)
+			}
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// --------------------------------------------------------------------------
+
+// basicAuth authenticates users with HTTP basic auth against bcrypt hashes.
+type basicAuth struct {
+	realm string
+	users map[string][]byte
+
+	// Verifying a bcrypt hash is deliberately slow, so successful logins are
+	// remembered for a while. Browsers send the credentials with every request.
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]time.Time
+}
+
+const basicAuthCacheTTL = 5 * time.Minute
+
+// A hash that is compared against when the user does not exist, so that
+// unknown and known users take the same time to reject.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("tailon"), bcrypt.DefaultCost)
+	return hash
+})
+
+func newBasicAuth(realm string, users map[string]string, htpasswd string) (*basicAuth, error) {
+	auth := &basicAuth{
+		realm: realm,
+		users: make(map[string][]byte),
+		cache: make(map[[sha256.Size]byte]time.Time),
+	}
+
+	if htpasswd != "" {
+		fileUsers, err := readHtpasswd(htpasswd)
+		if err != nil {
+			return nil, err
+		}
+		for user, hash := range fileUsers {
+			users[user] = hash
+		}
+	}
+
+	for user, hash := range users {
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("user %q: only bcrypt password hashes are supported", user)
+		}
+		auth.users[user] = []byte(hash)
+	}
+
+	if len(auth.users) == 0 {
+		return nil, fmt.Errorf("no users defined")
+	}
+	return auth, nil
+}
+
+// Read a htpasswd file with "user:hash" lines, as created by "htpasswd -B".
+func readHtpasswd(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	users := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected user:hash", path, lineno)
+		}
+		users[user] = hash
+	}
+	return users, scanner.Err()
+}
+
+func (a *basicAuth) Authenticate(r *http.Request) *Identity {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		return nil
+	}
+
+	key := sha256.Sum256([]byte(user + ":" + password))
+	a.mu.Lock()
+	expires, cached := a.cache[key]
+	a.mu.Unlock()
+	if cached && time.Now().Before(expires) {
+		return &Identity{User: user, Method: "basic"}
+	}
+
+	hash, known := a.users[user]
+	if !known {
+		bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return nil
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil {
+		return nil
+	}
+
+	a.mu.Lock()
+	now := time.Now()
+	for k, expires := range a.cache {
+		if now.After(expires) {
+			delete(a.cache, k)
+		}
+	}
+	a.cache[key] = now.Add(basicAuthCacheTTL)
+	a.mu.Unlock()
+
+	return &Identity{User: user, Method: "basic"}
+}
+
+func (a *basicAuth) Challenge() string {
+	return fmt.Sprintf("Basic realm=%q, charset=\"UTF-8\"", a.realm)
+}
+
+// tokenAuth authenticates clients that send a static "Authorization: Bearer"
+// token. The name of the token is used as the user name.
+type tokenAuth struct {
+	tokens map[string]string
+}
+
+func (a *tokenAuth) Authenticate(r *http.Request) *Identity {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil
+	}
+
+	var identity *Identity
+	for name, expected := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			identity = &Identity{User: name, Method: "token"}
+		}
+	}
+	return identity
+}
+
+func (a *tokenAuth) Challenge() string {
+	return "Bearer"
+}
+
+// proxyAuth trusts the user name set by a reverse proxy in a header, such as
+// X-Forwarded-User. The header is only trusted if the request comes from one
+// of the trusted networks or through a unix socket.
+type proxyAuth struct {
+	header       string
+	groupsHeader string
+	trusted      []*net.IPNet
+}
+
+func (a *proxyAuth) Authenticate(r *http.Request) *Identity {
+	user := r.Header.Get(a.header)
+	if user == "" || !a.isTrusted(r.RemoteAddr) {
+		return nil
+	}
+
+	identity := &Identity{User: user, Method: "proxy"}
+	if a.groupsHeader != "" {
+		for _, group := range strings.Split(r.Header.Get(a.groupsHeader), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				identity.Groups = append(identity.Groups, group)
+			}
+		}
+	}
+	return identity
+}
+
+func (a *proxyAuth) isTrusted(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		// Requests that arrive through a unix socket have no remote address.
+		return true
+	}
+
+	for _, network := range a.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *proxyAuth) Challenge() string {
+	return ""
+}
+
+// --------------------------------------------------------------------------
+
+// authConfig is the [auth] table of the config file.
+type authConfig struct {
+	Basic *struct {
+		Realm    string
+		Htpasswd string
+		Users    map[string]string
+	}
+	Token *struct {
+		Tokens map[string]string
+	}
+	Proxy *struct {
+		Header       string
+		GroupsHeader string `mapstructure:"groups-header"`
+		Trusted      []string
+	}
+	ClientCert *struct{} `mapstructure:"client-cert"`
+}
+
+// Create the authenticators that are configured in the [auth] table. Requests
+// are accepted if any of them succeeds. Authentication is disabled if there is
+// no [auth] table.
+func parseAuthConfig(cfg *toml.Tree) ([]Authenticator, error) {
+	table, ok := cfg.Get("auth").(*toml.Tree)
+	if !ok {
+		if cfg.Has("auth") {
+			return nil, fmt.Errorf("auth: expected a table")
+		}
+		return nil, nil
+	}
+
+	var auth authConfig
+	decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{ErrorUnused: true, Result: &auth})
+	if err := decoder.Decode(table.ToMap()); err != nil {
+		return nil, fmt.Errorf("[auth] at line %d: %s", table.Position().Line, err)
+	}
+
+	var authenticators []Authenticator
+
+	if auth.Basic != nil {
+		realm := auth.Basic.Realm
+		if realm == "" {
+			realm = "tailon"
+		}
+		users := auth.Basic.Users
+		if users == nil {
+			users = make(map[string]string)
+		}
+		basic, err := newBasicAuth(realm, users, auth.Basic.Htpasswd)
+		if err != nil {
+			return nil, fmt.Errorf("[auth.basic]: %s", err)
+		}
+		authenticators = append(authenticators, basic)
+	}
+
+	if auth.Token != nil {
+		if len(auth.Token.Tokens) == 0 {
+			return nil, fmt.Errorf("[auth.token]: no tokens defined")
+		}
+		for name, token := range auth.Token.Tokens {
+			if len(token) < 16 {
+				return nil, fmt.Errorf("[auth.token]: token %q is shorter than 16 characters", name)
+			}
+		}
+		authenticators = append(authenticators, &tokenAuth{tokens: auth.Token.Tokens})
+	}
+
+	if auth.Proxy != nil {
+		proxy := &proxyAuth{
+			header:       auth.Proxy.Header,
+			groupsHeader: auth.Proxy.GroupsHeader,
+		}
+		if proxy.header == "" {
+			proxy.header = "X-Forwarded-User"
+		}
+
+		trusted := auth.Proxy.Trusted
+		if trusted == nil {
+			trusted = []string{"127.0.0.1/32", "::1/128"}
+		}
+		for _, cidr := range trusted {
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("[auth.proxy]: %s", err)
+			}
+			proxy.trusted = append(proxy.trusted, network)
+		}
+		authenticators = append(authenticators, proxy)
+	}
+
+	if auth.ClientCert != nil {
+		authenticators = append(authenticators, &clientCertAuth{})
+	}
+
+	return authenticators, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthHandler(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	htpasswd := filepath.Join(t.TempDir(), "htpasswd")
+	os.WriteFile(htpasswd, []byte("# comment\nbob:"+string(hash)+"\n"), 0644)
+
+	cfg, _ := toml.Load(`
+	[auth.basic]
+	htpasswd = "` + htpasswd + `"
+	users = { alice = "` + string(hash) + `" }
+
+	[auth.token]
+	tokens = { ci = "0123456789abcdef" }
+
+	[auth.proxy]
+	groups-header = "X-Forwarded-Groups"
+	trusted = ["10.0.0.0/8"]
+	`)
+
+	authenticators, err := parseAuthConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := authHandler(authenticators, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := requestIdentity(r)
+		w.Write([]byte(identity.Method + ":" + identity.User + ":" + strings.Join(identity.Groups, "+")))
+	}))
+
+	tests := []struct {
+		setup  func(r *http.Request)
+		expect string
+	}{
+		{func(r *http.Request) { r.SetBasicAuth("alice", "secret") }, "basic:alice:"},
+		{func(r *http.Request) { r.SetBasicAuth("bob", "secret") }, "basic:bob:"},
+		{func(r *http.Request) { r.SetBasicAuth("alice", "wrong") }, "unauthorized"},
+		{func(r *http.Request) { r.SetBasicAuth("eve", "secret") }, "unauthorized"},
+		{func(r *http.Request) { r.Header.Set("Authorization", "Bearer 0123456789abcdef") }, "token:ci:"},
+		{func(r *http.Request) { r.Header.Set("Authorization", "Bearer 0123456789abcdeX") }, "unauthorized"},
+		{func(r *http.Request) {
+			r.RemoteAddr = "10.1.2.3:4567"
+			r.Header.Set("X-Forwarded-User", "carol")
+			r.Header.Set("X-Forwarded-Groups", "ops, dev")
+		}, "proxy:carol:ops+dev"},
+		{func(r *http.Request) {
+			r.RemoteAddr = "192.168.1.1:4567"
+			r.Header.Set("X-Forwarded-User", "carol")
+		}, "unauthorized"},
+		{func(r *http.Request) {}, "unauthorized"},
+	}
+
+	for n, test := range tests {
+		req := httptest.NewRequest("GET", "/ws/info", nil)
+		test.setup(req)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if res := strings.TrimSpace(rec.Body.String()); res != test.expect {
+			t.Fatalf("test %d: %q != %q", n, res, test.expect)
+		}
+		if test.expect == "unauthorized" && len(rec.Header().Values("WWW-Authenticate")) != 2 {
+			t.Fatalf("test %d: missing challenges: %v", n, rec.Header())
+		}
+	}
+
+	errors := map[string]string{
+		"[auth.basic]\nusers = { a = 'plain' }":  `[auth.basic]: user "a": only bcrypt password hashes are supported`,
+		"[auth.token]\ntokens = { a = 'short' }": `[auth.token]: token "a" is shorter than 16 characters`,
+		"[auth.proxy]\ntrusted = ['x']":          "[auth.proxy]: invalid CIDR address: x",
+		"[auth.digest]\nrealm = 'a'":             "[auth] at line 1",
+	}
+	for content, prefix := range errors {
+		cfg, _ := toml.Load(content)
+		if _, err := parseAuthConfig(cfg); err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("%q: %v does not start with %q", content, err, prefix)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// checkResult collects the problems that "tailon check" finds in a config.
+type checkResult struct {
+	errors   []string
+	warnings []string
+}
+
+func (r *checkResult) errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *checkResult) warnf(format string, args ...any) {
+	r.warnings = append(r.warnings, fmt.Sprintf(format, args...))
+}
+
+// Check a config without serving it: load the certificates of the listeners,
+// resolve the binaries of the commands that can be run and validate their
+// placeholders and stdin chains, and expand the filespecs. What would be
+// served is written to w, followed by the problems that were found. Return
+// false if there were errors.
+func checkConfig(config *Config, w io.Writer) bool {
+	result := &checkResult{warnings: slices.Clone(config.warnings)}
+
+	fmt.Fprintln(w, "Listeners:")
+	for _, spec := range config.listenSpecs() {
+		if !spec.isTLS() {
+			fmt.Fprintf(w, "  %s\n", spec.Addr)
+			continue
+		}
+		fmt.Fprintf(w, "  %s (tls)\n", spec.Addr)
+		if err := (&tlsReloader{spec: spec}).reload(); err != nil {
+			result.errorf("listener %s: %s", spec.Addr, err)
+		}
+	}
+
+	fmt.Fprintln(w, "Commands:")
+	checked := make(map[string]bool)
+	for _, name := range config.AllowCommandNames {
+		chain, err := commandChain(config.CommandSpecs, name)
+		if err != nil {
+			result.errorf("command %q: %s", name, err)
+			continue
+		}
+		var actions []string
+		for _, link := range chain {
+			actions = append(actions, strings.Join(config.CommandSpecs[link].Action, " "))
+			if !checked[link] {
+				checked[link] = true
+				checkCommand(config.CommandSpecs, link, result)
+			}
+		}
+		fmt.Fprintf(w, "  %s: %s\n", name, strings.Join(actions, " | "))
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.CommandSpecs)) {
+		if !checked[name] {
+			result.warnf("command %q is not in allow-commands and cannot be run", name)
+		}
+	}
+
+	fmt.Fprintln(w, "Files:")
+	listing := createListing(config.FileSpecs)
+	for _, group := range slices.Sorted(maps.Keys(listing.groups)) {
+		fmt.Fprintf(w, "  %s:\n", group)
+		for _, entry := range listing.groups[group] {
+			switch {
+			case !entry.Exists:
+				fmt.Fprintf(w, "    %s (missing)\n", entry.Path)
+			case entry.Alias != "" && entry.Alias != entry.Path:
+				fmt.Fprintf(w, "    %s (as %s)\n", entry.Path, entry.Alias)
+			default:
+				fmt.Fprintf(w, "    %s\n", entry.Path)
+			}
+		}
+	}
+	if len(config.FileSpecs) == 0 {
+		result.errorf("no files specified")
+	}
+	for _, spec := range config.FileSpecs {
+		if spec.Type != "file" && len(createListing([]FileSpec{spec}).files) == 0 {
+			result.warnf("%s %s matches no files", spec.Type, spec.Path)
+		}
+	}
+
+	for _, warning := range result.warnings {
+		fmt.Fprintln(w, "warning:", warning)
+	}
+	for _, err := range result.errors {
+		fmt.Fprintln(w, "error:", err)
+	}
+	return len(result.errors) == 0
+}
+
+// Shells, whose arguments can be scripts.
+var shells = []string{"sh", "bash", "dash", "ksh", "zsh"}
+
+// Check a single command of a stdin chain: its placeholders, its builtins and
+// that the binary that it runs can be found.
+func checkCommand(specs map[string]CommandSpec, name string, result *checkResult) {
+	spec := specs[name]
+	for n, arg := range spec.Action {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(arg, -1) {
+			placeholder := match[1] + match[2]
+			param, isParam := spec.Params[placeholder]
+			switch {
+			case match[0] == "$$":
+			case isParam || slices.Contains(commandPlaceholders, placeholder):
+				// Values from clients are safe as arguments, but not as
+				// part of a shell script.
+				free := placeholder == "script" || isParam && param.Type == "string" && len(param.Values) == 0
+				if free && n > 0 && slices.Contains(shells, filepath.Base(spec.Action[0])) {
+					result.warnf("command %q: %s is expanded into a shell script, which lets clients run any command", name, match[0])
+				}
+			case arg == match[0]:
+				result.errorf("command %q: unknown placeholder %s", name, arg)
+			case match[1] != "":
+				result.warnf("command %q: %s in %q is not a placeholder and is passed as is", name, match[0], arg)
+			}
+		}
+	}
+
+	// The pipeline of the command is created as if it was run for a file,
+	// which validates the builtins and their arguments. Errors in the stdin
+	// command are reported for that command.
+	fc := FrontendCommand{Command: name, Script: specs[name].Default, Entry: ListEntry{Path: os.DevNull}, Nlines: 10}
+	pipe, err := newPipeline(specs, fc)
+	if err != nil {
+		if stdin := specs[name].Stdin; stdin != "" {
+			fc.Command, fc.Script = stdin, specs[stdin].Default
+			if _, err := newPipeline(specs, fc); err != nil {
+				return
+			}
+		}
+		result.errorf("command %q: %s", name, err)
+		return
+	}
+	if st, ok := pipe.stages[len(pipe.stages)-1].(*execStage); ok {
+		if _, err := exec.LookPath(st.name); err != nil {
+			result.errorf("command %q: %s", name, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckConfig(t *testing.T) {
+	config, err := makeConfig(`
+		allow-commands = ["cat", "missing", "typo", "grep-first", "empty", "upper"]
+
+		[commands.cat]
+		action = ["cat", "$path"]
+		[commands.missing]
+		action = ["no-such-binary-for-tailon", "$path"]
+		[commands.typo]
+		action = ["cat", "$paht"]
+		[commands.grep-first]
+		action = ["@grep", "x"]
+		[commands.empty]
+		action = []
+		[commands.upper]
+		stdin = "cat"
+		action = ["sh", "-c", "tr a-z A-Z; echo $script ${HOME}", "--file=${path}"]
+		[commands.unused]
+		action = ["cat", "$path"]
+
+		[[files]]
+		path = "testdata/ex1/var/log/1.log"
+		[[files]]
+		path = "testdata/ex1/var/log/nothing-*.log"
+		group = "none"
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if checkConfig(config, &out) {
+		t.Fatalf("no errors found:\n%s", out.String())
+	}
+	expect := []string{
+		"  upper: cat $path | sh -c tr a-z A-Z; echo $script ${HOME} --file=${path}\n",
+		"  __default__:\n    testdata/ex1/var/log/1.log\n",
+		"warning: command \"upper\": $script is expanded into a shell script, which lets clients run any command\n",
+		"warning: command \"upper\": ${HOME} in \"tr a-z A-Z; echo $script ${HOME}\" is not a placeholder and is passed as is\n",
+		"warning: command \"unused\" is not in allow-commands and cannot be run\n",
+		"warning: glob testdata/ex1/var/log/nothing-*.log matches no files\n",
+		"error: command \"missing\": exec: \"no-such-binary-for-tailon\": executable file not found in $PATH\n",
+		"error: command \"typo\": unknown placeholder $paht\n",
+		"error: command \"grep-first\": grep-first: @grep needs a stdin command\n",
+		"error: command \"empty\": command \"empty\" has an empty action\n",
+	}
+	for _, line := range expect {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("missing %q in:\n%s", line, out.String())
+		}
+	}
+
+	config, _ = makeConfig("allow-commands = ['tail']\n[[files]]\npath = 'testdata/ex1/var/log/1.log'")
+	out.Reset()
+	if !checkConfig(config, &out) {
+		t.Fatalf("unexpected errors:\n%s", out.String())
+	}
+}
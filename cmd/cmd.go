@@ -76,6 +76,8 @@ type Cmd struct {
 	statusChan chan Status   // nil until Start() called
 	doneChan   chan struct{} // closed when done running
 	buffered   bool          // buffer STDOUT and STDERR to Status.Stdout and Std
+	limits     Limits        // resource limits of the proc
+	timedOut   bool          // stopped after limits.Timeout
 }
 
 // Status represents the running status and consolidated return of a Cmd. It can
@@ -104,6 +106,7 @@ type Status struct {
 	Runtime  float64  // seconds, zero if Cmd not started
 	Stdout   []string // buffered STDOUT; see Cmd.Status for more info
 	Stderr   []string // buffered STDERR; see Cmd.Status for more info
+	Limit    string   // LimitTimeout or LimitCPUTime if the proc exceeded it
 }
 
 // NewCmd creates a new Cmd for the given command name and arguments. The command
@@ -139,13 +142,58 @@ type Options struct {
 	// faster and more efficient than polling Cmd.Status. The caller must read both
 	// streaming channels, else lines are dropped silently.
 	Streaming bool
+
+	// Resource limits of the proc. See Limits.
+	Limits Limits
+}
+
+// Limits are resource limits of a Cmd. Zero values mean no limit. Except for
+// Timeout, they are applied before the proc is executed and are inherited by
+// its children.
+type Limits struct {
+	// The wall-clock time after which the proc is stopped. It is killed if it
+	// is still running TimeoutKillDelay later.
+	Timeout time.Duration
+
+	// The CPU time (RLIMIT_CPU) after which the proc receives SIGXCPU and,
+	// a second later, SIGKILL.
+	CPUTime time.Duration
+
+	// The size of the virtual memory of the proc (RLIMIT_AS) in bytes.
+	Memory uint64
+
+	// The nice value of the proc.
+	Nice int
+
+	// The I/O scheduling class (IOClassRealtime, IOClassBestEffort or
+	// IOClassIdle) and priority (0-7) of the proc.
+	IOClass int
+	IOLevel int
 }
 
+// I/O scheduling classes, as used by ioprio_set(2).
+const (
+	IOClassRealtime   = 1
+	IOClassBestEffort = 2
+	IOClassIdle       = 3
+)
+
+// How long a proc that is stopped after its timeout has to exit before it is
+// sent SIGKILL.
+const TimeoutKillDelay = 2 * time.Second
+
+// The limits that Status.Limit reports.
+const (
+	LimitTimeout = "timeout"
+	LimitCPUTime = "cpu-time"
+)
+
 // NewCmdOptions creates a new Cmd with options. The command is not started
 // until Start is called.
 func NewCmdOptions(options Options, name string, args ...string) *Cmd {
 	out := NewCmd(name, args...)
 	out.buffered = options.Buffered
+	out.limits = options.Limits
 	if options.Streaming {
 		out.Stdout = make(chan string, DEFAULT_STREAM_CHAN_SIZE)
 		out.Stderr = make(chan string, DEFAULT_STREAM_CHAN_SIZE)
@@ -189,15 +237,18 @@ func (c *Cmd) Stop() error {
 	c.Lock()
 	defer c.Unlock()
 
-	// Nothing to stop if Start hasn't been called, or the proc hasn't started,
-	// or it's already done.
-	if c.statusChan == nil || !c.started || c.done {
+	// Nothing to stop if Start hasn't been called or it's already done.
+	if c.statusChan == nil || c.done {
 		return nil
 	}
 
 	// Flag that command was stopped, it didn't complete. This results in
-	// status.Complete = false
+	// status.Complete = false. If the proc hasn't started yet, run stops it
+	// as soon as it does.
 	c.stopped = true
+	if !c.started {
+		return nil
+	}
 
 	// Signal the process group (-pid), not just the process, so that the process
 	// and all its children are signaled. Else, child procs can keep running and
@@ -307,11 +358,21 @@ func (c *Cmd) run() {
 	// is nil, use the current process' environment.
 	cmd.Env = c.Env
 
+	// A proc that can't be limited must not run.
+	checkLimits, err := limitCommand(cmd, c.limits)
+
 	// //////////////////////////////////////////////////////////////////////
 	// Start command
 	// //////////////////////////////////////////////////////////////////////
 	now := time.Now()
-	if err := cmd.Start(); err != nil {
+	if err == nil {
+		err = cmd.Start()
+		if limitErr := checkLimits(); err == nil && limitErr != nil {
+			err = fmt.Errorf("cannot apply limits: %w", limitErr)
+			cmd.Wait()
+		}
+	}
+	if err != nil {
 		c.Lock()
 		c.status.Error = err
 		c.status.StartTs = now.UnixNano()
@@ -327,12 +388,36 @@ func (c *Cmd) run() {
 	c.status.PID = cmd.Process.Pid // command is running
 	c.status.StartTs = now.UnixNano()
 	c.started = true
+	if c.stopped {
+		syscall.Kill(-c.status.PID, syscall.SIGTERM)
+	}
 	c.Unlock()
 
+	if c.limits.Timeout > 0 {
+		timer := time.AfterFunc(c.limits.Timeout, func() {
+			c.Lock()
+			c.timedOut = true
+			c.Unlock()
+			c.Stop()
+
+			// A proc that ignores SIGTERM is killed.
+			select {
+			case <-c.doneChan:
+			case <-time.After(TimeoutKillDelay):
+				c.Lock()
+				if !c.done {
+					syscall.Kill(-c.status.PID, syscall.SIGKILL)
+				}
+				c.Unlock()
+			}
+		})
+		defer timer.Stop()
+	}
+
 	// //////////////////////////////////////////////////////////////////////
 	// Wait for command to finish or be killed
 	// //////////////////////////////////////////////////////////////////////
-	err := cmd.Wait()
+	err = cmd.Wait()
 	now = time.Now()
 
 	// Get exit code of the command. According to the manual, Wait() returns:
@@ -340,6 +425,7 @@ func (c *Cmd) run() {
 	// is of type *ExitError. Other error types may be returned for I/O problems."
 	exitCode := 0
 	signaled := false
+	limit := ""
 	if err != nil {
 		switch err.(type) {
 		case *exec.ExitError:
@@ -358,6 +444,12 @@ func (c *Cmd) run() {
 				if waitStatus.Signaled() {
 					signaled = true
 					err = errors.New(exiterr.Error()) // "signal: terminated"
+
+					// SIGKILL is sent if the proc ignores SIGXCPU.
+					cpuTime := exiterr.UserTime() + exiterr.SystemTime()
+					if c.limits.CPUTime > 0 && (waitStatus.Signal() == syscall.SIGXCPU || cpuTime >= c.limits.CPUTime) {
+						limit = LimitCPUTime
+					}
 				}
 			}
 		default:
@@ -370,6 +462,10 @@ func (c *Cmd) run() {
 	if !c.stopped && !signaled {
 		c.status.Complete = true
 	}
+	if c.timedOut {
+		limit = LimitTimeout
+	}
+	c.status.Limit = limit
 	c.status.Runtime = now.Sub(c.startTime).Seconds()
 	c.status.StopTs = now.UnixNano()
 	c.status.Exit = exitCode
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Go cannot run code in a child between fork and exec, so a proc with limits
+// is started as a copy of this binary, which applies the limits to itself and
+// then executes the proc. The copy is started with this argv[0], followed by
+// the limits and the path of the proc and then by the argv of the proc. Unlike
+// the environment, argv[0] is not inherited, so only limitCommand can set it.
+const limitsArg0 = "go-cmd-limits"
+
+// The "who" argument of ioprio_set(2) for a process, which with a pid of 0 is
+// the calling thread.
+const ioprioWhoProcess = 1
+
+func init() {
+	if len(os.Args) > 2 && os.Args[0] == limitsArg0 {
+		execLimited(os.Args[1], os.Args[2:])
+	}
+}
+
+// Whether there are limits that must be applied before the proc is executed.
+func (limits Limits) preExec() bool {
+	return limits.CPUTime > 0 || limits.Memory > 0 || limits.Nice != 0 || limits.IOClass != 0
+}
+
+// Make cmd apply the limits before it executes its proc. The returned function
+// must be called after cmd.Start, even if it failed. It returns the error that
+// kept the limits from being applied, in which case the proc was not executed.
+func limitCommand(cmd *exec.Cmd, limits Limits) (func() error, error) {
+	if !limits.preExec() || cmd.Err != nil {
+		return func() error { return nil }, nil
+	}
+
+	// The error is written to a pipe that is closed on exec.
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	cpu := uint64((limits.CPUTime + time.Second - 1) / time.Second)
+	spec := fmt.Sprintf("%d,%d,%d,%d,%d,%s", cpu, limits.Memory, limits.Nice, limits.IOClass, limits.IOLevel, cmd.Path)
+	cmd.Args = append([]string{limitsArg0, spec}, cmd.Args...)
+	cmd.Path = "/proc/self/exe"
+	cmd.ExtraFiles = []*os.File{w}
+
+	return func() error {
+		w.Close()
+		defer r.Close()
+		if msg, _ := io.ReadAll(r); len(msg) > 0 {
+			return fmt.Errorf("%s", msg)
+		}
+		return nil
+	}, nil
+}
+
+// Apply the limits of spec to this process and execute the proc with argv. On
+// errors, they are written to the pipe of limitCommand and the process exits.
+// Never returns.
+func execLimited(spec string, argv []string) {
+	// The nice value and I/O priority are those of the thread that executes
+	// the proc.
+	runtime.LockOSThread()
+	const errFd = 3
+	syscall.CloseOnExec(errFd)
+
+	fail := func(err error) {
+		syscall.Write(errFd, []byte(err.Error()))
+		os.Exit(127)
+	}
+
+	fields := strings.SplitN(spec, ",", 6)
+	if len(fields) != 6 {
+		fail(fmt.Errorf("invalid limits %q", spec))
+	}
+	var values [5]int64
+	for n := range values {
+		var err error
+		if values[n], err = strconv.ParseInt(fields[n], 10, 64); err != nil {
+			fail(fmt.Errorf("invalid limits %q", spec))
+		}
+	}
+	cpu, memory, nice, ioClass, ioLevel, path := uint64(values[0]), uint64(values[1]), int(values[2]), values[3], values[4], fields[5]
+
+	if nice != 0 {
+		if err := unix.Setpriority(unix.PRIO_PROCESS, 0, nice); err != nil {
+			fail(fmt.Errorf("nice: %w", err))
+		}
+	}
+	if ioClass != 0 {
+		prio := ioClass<<13 | ioLevel
+		if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, 0, uintptr(prio)); errno != 0 {
+			fail(fmt.Errorf("ionice: %w", errno))
+		}
+	}
+	if cpu > 0 {
+		if err := unix.Setrlimit(unix.RLIMIT_CPU, &unix.Rlimit{Cur: cpu, Max: cpu + 1}); err != nil {
+			fail(fmt.Errorf("cpu-time: %w", err))
+		}
+	}
+	// The address space is limited last, since this process may not be able
+	// to map more memory once it is.
+	if memory > 0 {
+		if err := unix.Setrlimit(unix.RLIMIT_AS, &unix.Rlimit{Cur: memory, Max: memory}); err != nil {
+			fail(fmt.Errorf("memory: %w", err))
+		}
+	}
+
+	err := syscall.Exec(path, argv, os.Environ())
+	fail(fmt.Errorf("exec %s: %w", path, err))
+}
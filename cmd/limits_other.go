@@ -0,0 +1,15 @@
+//go:build !linux
+
+package cmd
+
+import (
+	"errors"
+	"os/exec"
+)
+
+func limitCommand(cmd *exec.Cmd, limits Limits) (func() error, error) {
+	if limits.CPUTime > 0 || limits.Memory > 0 || limits.Nice != 0 || limits.IOClass != 0 {
+		return nil, errors.New("resource limits are only supported on Linux")
+	}
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,616 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pelletier/go-toml"
+	flag "github.com/spf13/pflag"
+)
+
+// Config contains all backend and frontend configuration options and relevant state.
+type Config struct {
+	Title             string
+	RelativeRoot      string
+	BindAddr          []string
+	ConfigPaths       []string
+	WatchConfig       bool
+	WrapLinesInitial  bool
+	TailLinesInitial  int
+	AllowCommandNames []string
+	AllowDownload     bool
+	Metrics           bool
+	RefreshInterval   time.Duration
+	SendQueueSize     int
+	SendQueuePolicy   string
+	SendBatchSize     int
+
+	// The minimum level, format and destination of the server log.
+	LogLevel  string
+	LogFormat string
+	LogFile   string
+
+	// Admission limits; 0 means no limit.
+	MaxSessions          int
+	MaxSessionsPerClient int
+	MaxCommands          int
+	MaxCommandsPerClient int
+	IdleTimeout          time.Duration
+
+	CommandSpecs   map[string]CommandSpec
+	CommandScripts map[string]string
+	FileSpecs      []FileSpec
+	ListenSpecs    []ListenSpec
+	Authenticators []Authenticator
+	ACL            *ACL
+	Audit          *AuditSpec
+
+	// The [auth] table as it was given, for --print-config.
+	authTable map[string]any
+
+	// The include patterns of the config file that is being decoded, and the
+	// config files that were read, including the included ones.
+	include []string
+	files   []string
+
+	// Problems with the config file that are not errors, such as unknown
+	// keys. They are logged once logging is set up.
+	warnings []string
+}
+
+var activeConfig atomic.Pointer[Config]
+
+// The current config. Reloading swaps in a new config as a whole, so code that
+// reads several options should load it once.
+func currentConfig() *Config {
+	if config := activeConfig.Load(); config != nil {
+		return config
+	}
+	return &Config{}
+}
+
+func setConfig(config *Config) {
+	activeConfig.Store(config)
+}
+
+// The defaults of the send queue of each client.
+const (
+	defaultSendQueueSize = 1000
+	defaultSendBatchSize = 100
+)
+
+// configOption is a top-level key of the config file. Its value is decoded
+// into the field of Config that value points to, and then checked.
+type configOption struct {
+	value any
+	check func() error
+}
+
+// The top-level keys of the config file, other than the tables.
+func (config *Config) options() map[string]configOption {
+	positive := func(n *int) func() error {
+		return func() error {
+			if *n <= 0 {
+				return errors.New("expected a positive integer")
+			}
+			return nil
+		}
+	}
+	nonNegative := func(n *int) func() error {
+		return func() error {
+			if *n < 0 {
+				return errors.New("expected a non-negative integer")
+			}
+			return nil
+		}
+	}
+	oneOf := func(s *string, values []string) func() error {
+		return func() error {
+			if !slices.Contains(values, *s) {
+				return fmt.Errorf("expected one of %s", strings.Join(values, ", "))
+			}
+			return nil
+		}
+	}
+
+	return map[string]configOption{
+		"title":          {value: &config.Title},
+		"relative-root":  {value: &config.RelativeRoot},
+		"listen-addr":    {value: &config.BindAddr},
+		"allow-download": {value: &config.AllowDownload},
+		"allow-commands": {value: &config.AllowCommandNames, check: config.checkAllowedCommands},
+		"wrap-lines":     {value: &config.WrapLinesInitial},
+		"tail-lines":     {value: &config.TailLinesInitial, check: nonNegative(&config.TailLinesInitial)},
+		"metrics":        {value: &config.Metrics},
+		"watch-config":   {value: &config.WatchConfig},
+		"refresh-interval": {value: &config.RefreshInterval, check: func() error {
+			if config.RefreshInterval <= 0 {
+				return errors.New("expected a positive duration")
+			}
+			return nil
+		}},
+		"send-queue-size":         {value: &config.SendQueueSize, check: positive(&config.SendQueueSize)},
+		"send-queue-policy":       {value: &config.SendQueuePolicy, check: oneOf(&config.SendQueuePolicy, sendQueuePolicies)},
+		"send-batch-size":         {value: &config.SendBatchSize, check: positive(&config.SendBatchSize)},
+		"max-sessions":            {value: &config.MaxSessions, check: nonNegative(&config.MaxSessions)},
+		"max-sessions-per-client": {value: &config.MaxSessionsPerClient, check: nonNegative(&config.MaxSessionsPerClient)},
+		"max-commands":            {value: &config.MaxCommands, check: nonNegative(&config.MaxCommands)},
+		"max-commands-per-client": {value: &config.MaxCommandsPerClient, check: nonNegative(&config.MaxCommandsPerClient)},
+		"idle-timeout": {value: &config.IdleTimeout, check: func() error {
+			if config.IdleTimeout < 0 {
+				return errors.New("expected a non-negative duration")
+			}
+			return nil
+		}},
+		"log-level":  {value: &config.LogLevel, check: oneOf(&config.LogLevel, logLevels)},
+		"log-format": {value: &config.LogFormat, check: oneOf(&config.LogFormat, logFormats)},
+		"log-file":   {value: &config.LogFile},
+		"include":    {value: &config.include},
+	}
+}
+
+// The tables of the config file, which are decoded by their own parsers.
+var configTables = []string{"commands", "files", "listen", "auth", "groups", "acl", "audit"}
+
+func (config *Config) checkAllowedCommands() error {
+	for _, name := range config.AllowCommandNames {
+		if _, ok := config.CommandSpecs[name]; !ok {
+			return fmt.Errorf("unknown command %q", name)
+		}
+	}
+	return nil
+}
+
+// Decode the value of a config key into the field that target points to.
+func decodeOption(value, target any) error {
+	if target, ok := target.(*time.Duration); ok {
+		s, ok := value.(string)
+		if !ok {
+			return errors.New("expected a duration string")
+		}
+		duration, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		*target = duration
+		return nil
+	}
+
+	decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{ZeroFields: true, Result: target})
+	if err := decoder.Decode(value); err != nil {
+		switch target.(type) {
+		case *string:
+			return errors.New("expected a string")
+		case *bool:
+			return errors.New("expected a boolean")
+		case *int:
+			return errors.New("expected an integer")
+		case *[]string:
+			return errors.New("expected an array of strings")
+		}
+		return err
+	}
+	return nil
+}
+
+// Create the config from the defaults and the content of a config file.
+func makeConfig(content string) (*Config, error) {
+	config := defaultConfig()
+	if err := config.decode(content); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// The config that defaultTomlConfig describes.
+func defaultConfig() *Config {
+	config := &Config{CommandSpecs: make(map[string]CommandSpec)}
+	if err := config.decode(defaultTomlConfig); err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// Decode a config file on top of config. Keys that are missing from the file
+// keep their values, commands are added to the existing ones and so are the
+// [files] and [[listen]] tables.
+func (config *Config) decode(content string) error {
+	cfg, err := toml.Load(content)
+	if err != nil {
+		return err
+	}
+
+	// The commands come first, since other keys refer to them.
+	commands, warnings, err := parseCommandTables(cfg)
+	if err != nil {
+		return err
+	}
+	config.warnings = append(config.warnings, warnings...)
+	maps.Copy(config.CommandSpecs, commands)
+
+	// Reject stdin chains that are cyclic or refer to unknown commands. A
+	// cycle always includes one of the new commands.
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		if _, err := commandChain(config.CommandSpecs, name); err != nil {
+			return fmt.Errorf("[commands.%s] at line %d: %s", name, cfg.GetPosition("commands."+name).Line, err)
+		}
+	}
+
+	// Keys are decoded in the order of the file, so that the first error is
+	// reported.
+	options := config.options()
+	for _, key := range sortedKeys(cfg) {
+		line := cfg.GetPosition(key).Line
+		if slices.Contains(configTables, key) {
+			continue
+		}
+		option, ok := options[key]
+		if !ok {
+			config.warnings = append(config.warnings, fmt.Sprintf("unknown key %s at line %d", key, line))
+			continue
+		}
+		err := decodeOption(cfg.Get(key), option.value)
+		if err == nil && option.check != nil {
+			err = option.check()
+		}
+		if err != nil {
+			return fmt.Errorf("%s at line %d: %s", key, line, err)
+		}
+	}
+
+	filespecs, err := parseFileSpecTables(cfg)
+	if err != nil {
+		return err
+	}
+	config.FileSpecs = append(config.FileSpecs, filespecs...)
+
+	listenspecs, err := parseListenTables(cfg)
+	if err != nil {
+		return err
+	}
+	config.ListenSpecs = append(config.ListenSpecs, listenspecs...)
+
+	if cfg.Has("auth") {
+		if config.Authenticators, err = parseAuthConfig(cfg); err != nil {
+			return err
+		}
+		config.authTable = cfg.Get("auth").(*toml.Tree).ToMap()
+	}
+
+	acl, err := parseACLConfig(cfg, config.CommandSpecs)
+	if err != nil {
+		return err
+	}
+	if acl != nil {
+		config.ACL = acl
+	}
+	if config.ACL != nil && len(config.Authenticators) == 0 {
+		return errors.New("[[acl]] rules require an [auth] table")
+	}
+
+	audit, err := parseAuditConfig(cfg)
+	if err != nil {
+		return err
+	}
+	if audit != nil {
+		config.Audit = audit
+	}
+
+	return nil
+}
+
+// The keys of a table in the order of the config file.
+func sortedKeys(table *toml.Tree) []string {
+	keys := table.Keys()
+	slices.SortFunc(keys, func(a, b string) int {
+		return table.GetPosition(a).Line - table.GetPosition(b).Line
+	})
+	return keys
+}
+
+// Parse the [commands.<name>] tables of the config file. Unknown keys in them
+// are returned as warnings.
+func parseCommandTables(cfg *toml.Tree) (map[string]CommandSpec, []string, error) {
+	commands := make(map[string]CommandSpec)
+	if !cfg.Has("commands") {
+		return commands, nil, nil
+	}
+	table, ok := cfg.Get("commands").(*toml.Tree)
+	if !ok {
+		return nil, nil, fmt.Errorf("commands at line %d: expected a table", cfg.GetPosition("commands").Line)
+	}
+
+	var warnings []string
+	for _, key := range sortedKeys(table) {
+		line := cfg.GetPosition("commands." + key).Line
+		value, ok := table.Get(key).(*toml.Tree)
+		if !ok {
+			return nil, nil, fmt.Errorf("commands.%s at line %d: expected a table", key, line)
+		}
+
+		command := CommandSpec{}
+		var metadata mapstructure.Metadata
+		decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{Metadata: &metadata, Result: &command})
+		err := decoder.Decode(value.ToMap())
+		if err == nil {
+			err = command.parseLimits()
+		}
+		if err == nil {
+			err = command.parseParams()
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("[commands.%s] at line %d: %s", key, line, err)
+		}
+		slices.Sort(metadata.Unused)
+		for _, unused := range metadata.Unused {
+			warnings = append(warnings, fmt.Sprintf("unknown key %s in [commands.%s] at line %d", unused, key, line))
+		}
+		commands[key] = command
+	}
+	return commands, warnings, nil
+}
+
+// --------------------------------------------------------------------------
+
+// Load the config in layers, each of which overrides the ones before it: the
+// defaults, the config files in order, the TAILON_* environment variables and
+// the flags that were given on the command line.
+func loadConfig(paths []string, environ []string, flags *flag.FlagSet) (*Config, error) {
+	config := defaultConfig()
+	for _, path := range paths {
+		if err := config.decodeFile(path, nil); err != nil {
+			return nil, err
+		}
+	}
+	if err := config.decodeEnv(environ); err != nil {
+		return nil, err
+	}
+	if flags != nil {
+		if err := config.decodeFlags(flags); err != nil {
+			return nil, err
+		}
+	}
+	config.ConfigPaths = paths
+	return config, nil
+}
+
+// Decode a config file and then the files that its include patterns match.
+// Patterns are relative to the directory of the file. A file that was already
+// decoded, given twice or matched by several patterns, is skipped, so that its
+// [[files]] and [[listen]] tables are not added again.
+func (config *Config) decodeFile(path string, including []string) error {
+	path = filepath.Clean(path)
+	if slices.Contains(including, path) {
+		return fmt.Errorf("%s: included recursively", path)
+	}
+	if slices.Contains(config.files, path) {
+		return nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	config.files = append(config.files, path)
+
+	warnings := len(config.warnings)
+	config.include = nil
+	if err := config.decode(string(content)); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	for n := warnings; n < len(config.warnings); n++ {
+		config.warnings[n] = path + ": " + config.warnings[n]
+	}
+
+	var includes []string
+	for _, pattern := range config.include {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(path), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid include pattern %q", path, pattern)
+		}
+		includes = append(includes, matches...)
+	}
+	for _, include := range includes {
+		if err := config.decodeFile(include, append(including, path)); err != nil {
+			return err
+		}
+	}
+	config.include = nil
+	return nil
+}
+
+// The prefix of the environment variables that set config options. The rest of
+// the name is the key in upper case, with underscores instead of dashes.
+const envPrefix = "TAILON_"
+
+// Set the options that are given in the environment.
+func (config *Config) decodeEnv(environ []string) error {
+	keys := make(map[string]string)
+	for key := range config.options() {
+		keys[envPrefix+strings.ToUpper(strings.ReplaceAll(key, "-", "_"))] = key
+	}
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, envPrefix) {
+			continue
+		}
+		key, ok := keys[name]
+		if !ok || key == "include" {
+			config.warnings = append(config.warnings, "unknown environment variable "+name)
+			continue
+		}
+		if err := config.setOption(key, value); err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// The flags that set config options of another name. The other flags that set
+// options have the name of the option.
+var flagOptions = map[string]string{"bind": "listen-addr"}
+
+// Set the options of the flags that were given on the command line.
+func (config *Config) decodeFlags(flags *flag.FlagSet) error {
+	options := config.options()
+	var err error
+	flags.Visit(func(f *flag.Flag) {
+		key := cmp.Or(flagOptions[f.Name], f.Name)
+		if _, ok := options[key]; !ok || key == "include" || err != nil {
+			return
+		}
+		if err = config.setOption(key, f.Value.String()); err != nil {
+			err = fmt.Errorf("--%s: %s", f.Name, err)
+		}
+	})
+	return err
+}
+
+// Set an option from a string, as given in the environment or on the command
+// line. The values of arrays are separated by commas.
+func (config *Config) setOption(key, value string) error {
+	option := config.options()[key]
+
+	var decoded any = value
+	switch option.value.(type) {
+	case *bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected a boolean")
+		}
+		decoded = b
+	case *int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("expected an integer")
+		}
+		decoded = n
+	case *[]string:
+		decoded = []string{}
+		if value != "" {
+			decoded = strings.Split(value, ",")
+		}
+	}
+
+	err := decodeOption(decoded, option.value)
+	if err == nil && option.check != nil {
+		err = option.check()
+	}
+	return err
+}
+
+// The effective config in the format of the config file. The passwords and
+// tokens of the [auth] table are redacted.
+func (config *Config) marshal() (string, error) {
+	out := make(map[string]any)
+	for key, option := range config.options() {
+		switch value := option.value.(type) {
+		case *[]string:
+			if key != "include" {
+				out[key] = *value
+			}
+		case *time.Duration:
+			out[key] = value.String()
+		default:
+			out[key] = reflect.ValueOf(value).Elem().Interface()
+		}
+	}
+
+	commands := make(map[string]any)
+	for name, spec := range config.CommandSpecs {
+		table := configTable(spec)
+		if spec.Params != nil {
+			params := make(map[string]any)
+			for param, paramSpec := range spec.Params {
+				params[param] = configTable(paramSpec)
+			}
+			table["params"] = params
+		}
+		commands[name] = table
+	}
+	out["commands"] = commands
+
+	var files, listen []map[string]any
+	for _, spec := range config.FileSpecs {
+		files = append(files, configTable(spec))
+	}
+	for _, spec := range config.ListenSpecs {
+		listen = append(listen, configTable(spec))
+	}
+	if files != nil {
+		out["files"] = files
+	}
+	if listen != nil {
+		out["listen"] = listen
+	}
+
+	if config.authTable != nil {
+		auth := maps.Clone(config.authTable)
+		for _, method := range []string{"basic", "token"} {
+			table, ok := auth[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			table = maps.Clone(table)
+			for _, key := range []string{"users", "tokens"} {
+				if secrets, ok := table[key].(map[string]any); ok {
+					redacted := make(map[string]any)
+					for name := range secrets {
+						redacted[name] = "redacted"
+					}
+					table[key] = redacted
+				}
+			}
+			auth[method] = table
+		}
+		out["auth"] = auth
+	}
+	if config.ACL != nil {
+		if len(config.ACL.Groups) > 0 {
+			out["groups"] = config.ACL.Groups
+		}
+		var rules []map[string]any
+		for _, rule := range config.ACL.Rules {
+			rules = append(rules, configTable(rule))
+		}
+		out["acl"] = rules
+	}
+	if config.Audit != nil {
+		out["audit"] = configTable(*config.Audit)
+	}
+
+	tree, err := toml.TreeFromMap(out)
+	if err != nil {
+		return "", err
+	}
+	return tree.String(), nil
+}
+
+// The exported fields of a struct that are not zero, keyed by the names that
+// they have in the config file.
+func configTable(value any) map[string]any {
+	table := make(map[string]any)
+	v := reflect.ValueOf(value)
+	for n := 0; n < v.NumField(); n++ {
+		field := v.Type().Field(n)
+		if !field.IsExported() || v.Field(n).IsZero() {
+			continue
+		}
+		key := cmp.Or(field.Tag.Get("mapstructure"), strings.ToLower(field.Name))
+		table[key] = v.Field(n).Interface()
+	}
+	return table
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	config := defaultConfig()
+	if config.Title != "Tailon file viewer" || config.TailLinesInitial != 10 || config.WrapLinesInitial ||
+		!reflect.DeepEqual(config.BindAddr, []string{":8080"}) || config.RefreshInterval != 10*time.Second ||
+		config.SendQueueSize != defaultSendQueueSize || config.LogLevel != "info" || len(config.CommandSpecs) != 4 ||
+		config.MaxSessionsPerClient != 0 || config.MaxCommandsPerClient != 0 || config.IdleTimeout != 0 {
+		t.Fatalf("%+v", config)
+	}
+	if len(config.warnings) != 0 {
+		t.Fatal(config.warnings)
+	}
+}
+
+func TestMakeConfig(t *testing.T) {
+	config, err := makeConfig(`
+	title = "Logs"
+	wrap-lines = true
+	tail-lines = 50
+	listen-addr = ["127.0.0.1:8080", "/run/tailon.sock"]
+	allow-commands = ["tail", "cat"]
+	idle-timeout = "0s"
+	colour = "red"
+
+	[commands.cat]
+	action = ["cat", "$path"]
+	shell = true
+
+	[[files]]
+	path = "/var/log/messages"
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Title != "Logs" || !config.WrapLinesInitial || config.TailLinesInitial != 50 || config.IdleTimeout != 0 {
+		t.Fatalf("%+v", config)
+	}
+	if !reflect.DeepEqual(config.BindAddr, []string{"127.0.0.1:8080", "/run/tailon.sock"}) {
+		t.Fatal(config.BindAddr)
+	}
+	if !reflect.DeepEqual(config.AllowCommandNames, []string{"tail", "cat"}) || len(config.CommandSpecs) != 5 {
+		t.Fatal(config.AllowCommandNames, config.CommandSpecs)
+	}
+	if len(config.FileSpecs) != 1 || config.RefreshInterval != 10*time.Second {
+		t.Fatalf("%+v", config)
+	}
+	expect := []string{"unknown key shell in [commands.cat] at line 10", "unknown key colour at line 8"}
+	if !reflect.DeepEqual(config.warnings, expect) {
+		t.Fatalf("%q != %q", config.warnings, expect)
+	}
+
+	errors := map[string]string{
+		"title = 1":                                 "title at line 1: expected a string",
+		"listen-addr = ':8080'":                     "listen-addr at line 1: expected an array of strings",
+		"wrap-lines = 'yes'":                        "wrap-lines at line 1: expected a boolean",
+		"tail-lines = -1":                           "tail-lines at line 1: expected a non-negative integer",
+		"tail-lines = 'ten'":                        "tail-lines at line 1: expected an integer",
+		"\nrefresh-interval = 10":                   "refresh-interval at line 2: expected a duration string",
+		"refresh-interval = '0s'":                   "refresh-interval at line 1: expected a positive duration",
+		"idle-timeout = 'soon'":                     `idle-timeout at line 1: invalid duration "soon"`,
+		"send-queue-size = 0":                       "send-queue-size at line 1: expected a positive integer",
+		"send-queue-policy = 'block'":               "send-queue-policy at line 1: expected one of drop-oldest, pause",
+		"max-sessions = -1":                         "max-sessions at line 1: expected a non-negative integer",
+		"log-level = 'verbose'":                     "log-level at line 1: expected one of debug, info, warn, error",
+		"allow-commands = ['cat']":                  `allow-commands at line 1: unknown command "cat"`,
+		"title = 'a'\ntail-lines = 'b'":             "tail-lines at line 2: expected an integer",
+		"commands = 1":                              "commands at line 1: expected a table",
+		"[commands.x]\naction = ['x']\nnice = 99":   "[commands.x] at line 1: invalid nice 99",
+		"[commands.x]\nstdin = 'y'\naction = ['x']": `[commands.x] at line 1: command "x" reads stdin from unknown command "y"`,
+		"[[acl]]\nusers = ['*']":                    "[[acl]] rules require an [auth] table",
+	}
+	for content, prefix := range errors {
+		if _, err := makeConfig(content); err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("%q: %v does not start with %q", content, err, prefix)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	main := write("tailon.toml", "title = 'main'\ninclude = ['conf.d/*.toml']\ntail-lines = 20\nwrap-lines = true\n[[files]]\npath = '/var/log/a'")
+	write("conf.d/10-b.toml", "title = 'b'\n[[files]]\npath = '/var/log/b'")
+	write("conf.d/20-c.toml", "title = 'c'\ncolour = 'red'\n[commands.cat]\naction = ['cat', '$path']")
+	local := write("local.toml", "listen-addr = [':9000']\nmax-sessions = 5")
+
+	flags := flag.NewFlagSet("tailon", flag.ContinueOnError)
+	flags.StringP("bind", "b", ":8080", "")
+	flags.Int("tail-lines", 10, "")
+	flags.Bool("wrap-lines", false, "")
+	flags.Parse([]string{"--tail-lines", "40"})
+
+	environ := []string{"HOME=/root", "TAILON_TAIL_LINES=30", "TAILON_MAX_SESSIONS=7", "TAILON_ALLOW_COMMANDS=tail,cat", "TAILON_BOGUS=1"}
+	config, err := loadConfig([]string{main, local}, environ, flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Title != "c" || config.TailLinesInitial != 40 || !config.WrapLinesInitial || config.MaxSessions != 7 {
+		t.Fatalf("%+v", config)
+	}
+	if !reflect.DeepEqual(config.BindAddr, []string{":9000"}) || !reflect.DeepEqual(config.AllowCommandNames, []string{"tail", "cat"}) {
+		t.Fatal(config.BindAddr, config.AllowCommandNames)
+	}
+	if len(config.FileSpecs) != 2 || config.FileSpecs[1].Path != "/var/log/b" {
+		t.Fatal(config.FileSpecs)
+	}
+	expect := []string{dir + "/conf.d/20-c.toml: unknown key colour at line 2", "unknown environment variable TAILON_BOGUS"}
+	if !reflect.DeepEqual(config.warnings, expect) {
+		t.Fatalf("%q != %q", config.warnings, expect)
+	}
+
+	flags.Parse([]string{"-b", "a:1,b:2"})
+	if config, err = loadConfig(nil, nil, flags); err != nil || !reflect.DeepEqual(config.BindAddr, []string{"a:1", "b:2"}) {
+		t.Fatal(config.BindAddr, err)
+	}
+
+	errors := map[string][]string{
+		"TAILON_TAIL_LINES: expected an integer":           {"TAILON_TAIL_LINES=many"},
+		"TAILON_LOG_LEVEL: expected one of":                {"TAILON_LOG_LEVEL=loud"},
+		"TAILON_REFRESH_INTERVAL: invalid duration \"10\"": {"TAILON_REFRESH_INTERVAL=10"},
+	}
+	for prefix, environ := range errors {
+		if _, err := loadConfig(nil, environ, nil); err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("%q: %v does not start with %q", environ, err, prefix)
+		}
+	}
+
+	// Files that are included several times are decoded once.
+	twice := write("twice.toml", "include = ['conf.d/*.toml', 'conf.d/10-b.toml', 'tailon.toml']")
+	if config, err = loadConfig([]string{twice, main}, nil, nil); err != nil || len(config.FileSpecs) != 2 || len(config.files) != 4 {
+		t.Fatal(config.files, config.FileSpecs, err)
+	}
+
+	loop := write("loop.toml", "include = ['loop.toml']")
+	if _, err := loadConfig([]string{loop}, nil, nil); err == nil || !strings.Contains(err.Error(), "included recursively") {
+		t.Fatal(err)
+	}
+	bad := write("bad.toml", "tail-lines = 'x'")
+	if _, err := loadConfig([]string{bad}, nil, nil); err == nil || err.Error() != bad+": tail-lines at line 1: expected an integer" {
+		t.Fatal(err)
+	}
+}
+
+func TestPrintConfig(t *testing.T) {
+	config, err := makeConfig(`
+	title = "Logs"
+	idle-timeout = "1m"
+	[commands.cat]
+	action = ["cat", "$path"]
+	timeout = "10s"
+	params.n = { type = "int", default = 5 }
+	[[files]]
+	path = "/var/log/"
+	type = "dir"
+	maxdepth = 2
+	[[listen]]
+	addr = ":8443"
+	cert-file = "cert.pem"
+	key-file = "key.pem"
+	[auth.token]
+	tokens = { ci = "a-long-random-token" }
+	[[acl]]
+	users = ["ci"]
+	commands = ["cat"]
+	[audit]
+	file = "audit.log"
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := config.marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "a-long-random-token") || !strings.Contains(out, `ci = "redacted"`) {
+		t.Fatal(out)
+	}
+
+	// Apart from the secrets, the output reads back as the same config.
+	out = strings.Replace(out, `"redacted"`, `"a-long-random-token"`, 1)
+	printed, err := makeConfig(out)
+	if err != nil {
+		t.Fatal(err, "\n", out)
+	}
+	for _, c := range []*Config{config, printed} {
+		c.Authenticators, c.authTable, c.warnings = nil, nil, nil
+	}
+	if !reflect.DeepEqual(config, printed) {
+		t.Fatalf("%+v != %+v", config, printed)
+	}
+}
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"log/slog"
+	"maps"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/fsnotify/fsnotify"
 )
 
 // ListEntry is an entry that appears in the UI file input.
@@ -21,8 +29,9 @@ func fileInfo(path string) *ListEntry {
 	entry := ListEntry{}
 	entry.Path = path
 
+	// Files that cannot be stat'ed, for any reason, are not present.
 	info, err := os.Stat(path)
-	if !os.IsNotExist(err) {
+	if err == nil {
 		entry.Exists = true
 		entry.Size = info.Size()
 		entry.ModTime = info.ModTime()
@@ -31,11 +40,31 @@ func fileInfo(path string) *ListEntry {
 	return &entry
 }
 
-var allFiles map[string]bool
+// fileListing is a snapshot of the files that are matched by the filespecs.
+// It is never modified after it is created.
+type fileListing struct {
+	// The entries of the file input, keyed by group.
+	groups map[string][]*ListEntry
 
-func createListing(filespecs []FileSpec) map[string][]*ListEntry {
-	allFiles = make(map[string]bool)
-	res := make(map[string][]*ListEntry)
+	// All files and the groups in which they appear.
+	files map[string][]string
+
+	// The directories in which files can appear or disappear.
+	dirs map[string]bool
+}
+
+// Expand filespecs into the entries of the file input.
+func createListing(filespecs []FileSpec) *fileListing {
+	res := &fileListing{
+		groups: make(map[string][]*ListEntry),
+		files:  make(map[string][]string),
+		dirs:   make(map[string]bool),
+	}
+
+	add := func(group string, entry *ListEntry) {
+		res.groups[group] = append(res.groups[group], entry)
+		res.files[entry.Path] = append(res.files[entry.Path], group)
+	}
 
 	for _, spec := range filespecs {
 		group := "__default__"
@@ -51,9 +80,15 @@ func createListing(filespecs []FileSpec) map[string][]*ListEntry {
 			} else {
 				entry.Alias = entry.Path
 			}
-			res[group] = append(res[group], entry)
-			allFiles[entry.Path] = true
+			add(group, entry)
+			res.dirs[filepath.Dir(spec.Path)] = true
 		case "glob":
+			res.dirs[globPrefix(spec.Path)] = true
+			dirs, _ := filepath.Glob(filepath.Dir(spec.Path))
+			for _, dir := range dirs {
+				res.dirs[dir] = true
+			}
+
 			matches, _ := filepath.Glob(spec.Path)
 			for _, match := range matches {
 				entry := fileInfo(match)
@@ -64,16 +99,365 @@ func createListing(filespecs []FileSpec) map[string][]*ListEntry {
 					rel, _ := filepath.Rel(cwd, entry.Path)
 					entry.Alias = rel
 				}
-				res[group] = append(res[group], entry)
-				allFiles[entry.Path] = true
+				add(group, entry)
+			}
+		case "dir":
+			files, dirs := walkDir(spec)
+			for _, dir := range dirs {
+				res.dirs[dir] = true
+			}
+			for _, rel := range files {
+				entry := fileInfo(filepath.Join(spec.Path, rel))
+				if spec.Alias != "" {
+					entry.Alias = path.Join(spec.Alias, filepath.ToSlash(rel))
+				} else {
+					entry.Alias = entry.Path
+				}
+				add(group, entry)
+			}
+		}
+	}
+
+	return res
+}
+
+// Return the groups of the listing that identity is allowed to see.
+func (l *fileListing) visible(identity *Identity) map[string][]*ListEntry {
+	res := make(map[string][]*ListEntry)
+	for group, entries := range l.groups {
+		if currentConfig().ACL.AllowFileGroup(identity, group) {
+			res[group] = entries
+		}
+	}
+	return res
+}
+
+// The first group of a file that identity can see, or an empty string.
+func (l *fileListing) group(path string, identity *Identity) string {
+	for _, group := range l.files[path] {
+		if currentConfig().ACL.AllowFileGroup(identity, group) {
+			return group
+		}
+	}
+	return ""
+}
+
+// Check if a file is matched by a filespec in a group that identity can see.
+func (l *fileListing) allowed(path string, identity *Identity) bool {
+	for _, group := range l.files[path] {
+		if currentConfig().ACL.AllowFileGroup(identity, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileRegistry holds the current listing of a set of filespecs and is safe for
+// concurrent use. Refreshing builds a new listing and swaps it in, so readers
+// always see a complete snapshot and never wait for the filesystem.
+type fileRegistry struct {
+	filespecs []FileSpec
+	current   atomic.Pointer[fileListing]
+
+	// Serializes refreshes, so that a slow refresh cannot overwrite the
+	// result of a newer one.
+	refreshing sync.Mutex
+
+	// Guards filespecs and subscribers. It is not held while the filesystem
+	// is walked, so that subscribing never waits for a refresh.
+	mu          sync.Mutex
+	subscribers map[chan struct{}]bool
+
+	// Receives a value when the filespecs are replaced, so that run watches
+	// the directories of the new listing.
+	respecified chan struct{}
+}
+
+// How long to wait after a change in a watched directory before refreshing,
+// so that bursts of events (e.g. a log rotation) cause a single refresh.
+const watchDelay = 250 * time.Millisecond
+
+func newFileRegistry(filespecs []FileSpec) *fileRegistry {
+	registry := &fileRegistry{
+		filespecs:   filespecs,
+		subscribers: make(map[chan struct{}]bool),
+		respecified: make(chan struct{}, 1),
+	}
+	registry.refresh()
+	return registry
+}
+
+// Expand the filespecs again and replace the current listing. Subscribers are
+// notified if any entry was added, removed or changed.
+func (r *fileRegistry) refresh() {
+	r.refreshing.Lock()
+	defer r.refreshing.Unlock()
+
+	r.mu.Lock()
+	filespecs := r.filespecs
+	r.mu.Unlock()
+
+	var listing *fileListing
+	metrics.RefreshDuration.time(func() { listing = createListing(filespecs) })
+	old := r.current.Load()
+	changed := old != nil && len(diffListings(old.groups, listing.groups)) > 0
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current.Store(listing)
+	if !changed {
+		return
+	}
+
+	for ch := range r.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Replace the filespecs, e.g. after the config was reloaded, and refresh the
+// listing.
+func (r *fileRegistry) setFileSpecs(filespecs []FileSpec) {
+	r.mu.Lock()
+	r.filespecs = filespecs
+	r.mu.Unlock()
+	r.refresh()
+
+	select {
+	case r.respecified <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe to changes of the listing. The returned channel receives a value
+// after the listing changes, but changes that happen before it is read are
+// coalesced. Call the returned function to unsubscribe.
+func (r *fileRegistry) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	r.mu.Lock()
+	r.subscribers[ch] = true
+	r.mu.Unlock()
+
+	return ch, func() {
+		r.mu.Lock()
+		delete(r.subscribers, ch)
+		r.mu.Unlock()
+	}
+}
+
+// Refresh the listing every interval and shortly after changes in the watched
+// directories, until stop is closed. The interval is the fallback for changes
+// that are not reported by fsnotify (e.g. on network filesystems).
+func (r *fileRegistry) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var events chan fsnotify.Event
+	var errors chan error
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("Cannot watch files, falling back to polling", "error", err)
+	} else {
+		defer watcher.Close()
+		events, errors = watcher.Events, watcher.Errors
+	}
+
+	// Directories are added and removed as the listing changes.
+	watched := make(map[string]bool)
+	rewatch := func() {
+		if watcher == nil {
+			return
+		}
+		dirs := r.current.Load().dirs
+		for dir := range watched {
+			if !dirs[dir] {
+				watcher.Remove(dir)
+				delete(watched, dir)
+			}
+		}
+		for dir := range dirs {
+			if !watched[dir] && watcher.Add(dir) == nil {
+				watched[dir] = true
+			}
+		}
+	}
+	rewatch()
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-ticker.C:
+		case <-events:
+			if pending == nil {
+				pending = time.After(watchDelay)
+			}
+			continue
+		case err := <-errors:
+			slog.Warn("Error watching files", "error", err)
+			continue
+		case <-pending:
+			pending = nil
+		case <-r.respecified:
+			rewatch()
+			continue
+		case <-stop:
+			return
+		}
+
+		r.refresh()
+		rewatch()
+	}
+}
+
+// Return the entries of the file input that identity is allowed to see.
+func (r *fileRegistry) listing(identity *Identity) map[string][]*ListEntry {
+	return r.current.Load().visible(identity)
+}
+
+// Check if a file is known and visible to identity.
+func (r *fileRegistry) fileAllowed(path string, identity *Identity) bool {
+	return r.current.Load().allowed(path, identity)
+}
+
+// Return the group in which identity sees a file.
+func (r *fileRegistry) fileGroup(path string, identity *Identity) string {
+	return r.current.Load().group(path, identity)
+}
+
+// ListingChange is an entry of the file input that was added, removed or that
+// changed in size, modification time or existence.
+type ListingChange struct {
+	Op    string     `json:"op"`
+	Group string     `json:"group"`
+	Entry *ListEntry `json:"entry"`
+}
+
+// Return the changes that turn listing a into listing b. Entries are
+// identified by their group, path and alias.
+func diffListings(a, b map[string][]*ListEntry) []ListingChange {
+	type key struct{ group, path, alias string }
+
+	index := func(listing map[string][]*ListEntry) ([]key, map[key]*ListEntry) {
+		var keys []key
+		entries := make(map[key]*ListEntry)
+		for _, group := range slices.Sorted(maps.Keys(listing)) {
+			for _, entry := range listing[group] {
+				k := key{group, entry.Path, entry.Alias}
+				keys = append(keys, k)
+				entries[k] = entry
 			}
 		}
+		return keys, entries
 	}
+	keysA, entriesA := index(a)
+	keysB, entriesB := index(b)
 
+	var res []ListingChange
+	for _, k := range keysA {
+		if entriesB[k] == nil {
+			res = append(res, ListingChange{"removed", k.group, entriesA[k]})
+		}
+	}
+	for _, k := range keysB {
+		old, entry := entriesA[k], entriesB[k]
+		switch {
+		case old == nil:
+			res = append(res, ListingChange{"added", k.group, entry})
+		case old.Size != entry.Size || !old.ModTime.Equal(entry.ModTime) || old.Exists != entry.Exists:
+			res = append(res, ListingChange{"changed", k.group, entry})
+		}
+	}
 	return res
 }
 
-func fileAllowed(path string) bool {
-	_, ok := allFiles[path]
-	return ok
+// Recursively walk the directory of a "dir" filespec and return the paths of
+// all files under it, relative to the directory, and the directories that were
+// walked. Symlinked directories are descended into only with the "follow"
+// symlink policy, in which case a directory that is its own ancestor is
+// skipped to avoid loops.
+func walkDir(spec FileSpec) (files []string, dirs []string) {
+	ancestors := make(map[string]bool)
+
+	var walk func(dir, rel string, depth int)
+	walk = func(dir, rel string, depth int) {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			if ancestors[real] {
+				return
+			}
+			ancestors[real] = true
+			defer delete(ancestors, real)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		dirs = append(dirs, dir)
+
+		for _, dirEntry := range entries {
+			name := dirEntry.Name()
+			entryPath := filepath.Join(dir, name)
+			entryRel := filepath.Join(rel, name)
+
+			if matchAny(spec.Exclude, name, entryRel) {
+				continue
+			}
+
+			mode := dirEntry.Type()
+			if mode&os.ModeSymlink != 0 {
+				if spec.Symlinks == "skip" {
+					continue
+				}
+				info, err := os.Stat(entryPath)
+				if err != nil {
+					continue
+				}
+				if info.IsDir() && spec.Symlinks != "follow" {
+					continue
+				}
+				mode = info.Mode().Type()
+			}
+
+			switch {
+			case mode.IsDir():
+				if spec.MaxDepth == 0 || depth < spec.MaxDepth {
+					walk(entryPath, entryRel, depth+1)
+				}
+			case mode.IsRegular():
+				if len(spec.Include) == 0 || matchAny(spec.Include, name, entryRel) {
+					files = append(files, entryRel)
+				}
+			}
+		}
+	}
+
+	walk(spec.Path, "", 1)
+	return files, dirs
+}
+
+// Return the longest leading directory of a glob pattern that contains no
+// pattern characters.
+func globPrefix(pattern string) string {
+	dir := filepath.Dir(pattern)
+	for strings.ContainsAny(dir, "*?[\\") {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// Check if either the base name or the relative path of a file matches any of
+// the given shell patterns.
+func matchAny(patterns []string, name, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, filepath.ToSlash(rel)); ok {
+			return true
+		}
+	}
+	return false
 }
@@ -25,7 +25,7 @@ Server rendered element contains fewer child nodes than client vdom.`),y=!0),Ln(
 `,-1)}E_(e.hoists,t),r(),i("return ")}function nl(e,t,{helper:n,push:s,newline:i,isTS:r}){let o=n(t==="filter"?oo:t==="component"?so:ro);for(let a=0;a<e.length;a++){let l=e[a],c=l.endsWith("__self");c&&(l=l.slice(0,-6)),s(`const ${xs(l,t)} = ${o}(${JSON.stringify(l)}${c?", true":""})${r?"!":""}`),a<e.length-1&&i()}}function E_(e,t){if(!e.length)return;t.pure=!0;let{push:n,newline:s}=t;s();for(let i=0;i<e.length;i++){let r=e[i];r&&(n(`const _hoisted_${i+1} = `),Qe(r,t),s())}t.pure=!1}function Ol(e,t){let n=e.length>3||!1;t.push("["),n&&t.indent(),Si(e,t,n),n&&t.deindent(),t.push("]")}function Si(e,t,n=!1,s=!0){let{push:i,newline:r}=t;for(let o=0;o<e.length;o++){let a=e[o];Q(a)?i(a,-3):G(a)?Ol(a,t):Qe(a,t),o<e.length-1&&(n?(s&&i(","),r()):s&&i(", "))}}function Qe(e,t){if(Q(e)){t.push(e,-3);return}if(Ie(e)){t.push(t.helper(e));return}switch(e.type){case 1:case 9:case 11:Qe(e.codegenNode,t);break;case 2:__(e,t);break;case 4:Bp(e,t);break;case 5:v_(e,t);break;case 12:Qe(e.codegenNode,t);break;case 8:jp(e,t);break;case 3:N_(e,t);break;case 13:b_(e,t);break;case 14:S_(e,t);break;case 15:w_(e,t);break;case 17:T_(e,t);break;case 18:C_(e,t);break;case 19:x_(e,t);break;case 20:D_(e,t);break;case 21:Si(e.body,t,!0,!1);break;case 22:break;case 23:break;case 24:break;case 25:break;case 26:break;case 10:break;default:}}function __(e,t){t.push(JSON.stringify(e.content),-3,e)}function Bp(e,t){let{content:n,isStatic:s}=e;t.push(s?JSON.stringify(n):n,-3,e)}function v_(e,t){let{push:n,helper:s,pure:i}=t;i&&n(Eo),n(`${s(As)}(`),Qe(e.content,t),n(")")}function jp(e,t){for(let n=0;n<e.children.length;n++){let s=e.children[n];Q(s)?t.push(s,-3):Qe(s,t)}}function y_(e,t){let{push:n}=t;if(e.type===8)n("["),jp(e,t),n("]");else if(e.isStatic){let s=bi(e.content)?e.content:JSON.stringify(e.content);n(s,-2,e)}else n(`[${e.content}]`,-3,e)}function N_(e,t){let{push:n,helper:s,pure:i}=t;i&&n(Eo),n(`${s(Ds)}(${JSON.stringify(e.content)})`,-3,e)}function b_(e,t){let{push:n,helper:s,pure:i}=t,{tag:r,props:o,children:a,patchFlag:l,dynamicProps:c,directives:f,isBlock:u,disableTracking:p,isComponent:d}=e,h;l&&(h=String(l)),f&&n(s(ao)+"("),u&&n(`(${s(xn)}(${p?"true":""}), `),i&&n(Eo);let E=u?es(t.inSSR,d):Zn(t.inSSR,d);n(s(E)+"(",-2,e),Si(O_([r,o,a,h,c]),t),n(")"),u&&n(")"),f&&(n(", "),Qe(f,t),n(")"))}function O_(e){let t=e.length;for(;t--&&e[t]==null;);return e.slice(0,t+1).map(n=>n||"null")}function S_(e,t){let{push:n,helper:s,pure:i}=t,r=Q(e.callee)?e.callee:s(e.callee);i&&n(Eo),n(r+"(",-2,e),Si(e.arguments,t),n(")")}function w_(e,t){let{push:n,indent:s,deindent:i,newline:r}=t,{properties:o}=e;if(!o.length){n("{}",-2,e);return}let a=o.length>1||!1;n(a?"{":"{ "),a&&s();for(let l=0;l<o.length;l++){let{key:c,value:f}=o[l];y_(c,t),n(": "),Qe(f,t),l<o.length-1&&(n(","),r())}a&&i(),n(a?"}":" }")}function T_(e,t){Ol(e.elements,t)}function C_(e,t){let{push:n,indent:s,deindent:i}=t,{params:r,returns:o,body:a,newline:l,isSlot:c}=e;c&&n(`_${qn[po]}(`),n("(",-2,e),G(r)?Si(r,t):r&&Qe(r,t),n(") => "),(l||a)&&(n("{"),s()),o?(l&&n("return "),G(o)?Ol(o,t):Qe(o,t)):a&&Qe(a,t),(l||a)&&(i(),n("}")),c&&(e.isNonScopedSlot&&n(", undefined, true"),n(")"))}function x_(e,t){let{test:n,consequent:s,alternate:i,newline:r}=e,{push:o,indent:a,deindent:l,newline:c}=t;if(n.type===4){let u=!bi(n.content);u&&o("("),Bp(n,t),u&&o(")")}else o("("),Qe(n,t),o(")");r&&a(),t.indentLevel++,r||o(" "),o("? "),Qe(s,t),t.indentLevel--,r&&c(),r||o(" "),o(": ");let f=i.type===19;f||t.indentLevel++,Qe(i,t),f||t.indentLevel--,r&&l(!0)}function D_(e,t){let{push:n,helper:s,indent:i,deindent:r,newline:o}=t,{needPauseTracking:a,needArraySpread:l}=e;l&&n("[...("),n(`_cache[${e.index}] || (`),a&&(i(),n(`${s(_i)}(-1),`),o(),n("(")),n(`_cache[${e.index}] = `),Qe(e.value,t),a&&(n(`).cacheIndex = ${e.index},`),o(),n(`${s(_i)}(1),`),o(),n(`_cache[${e.index}]`),r()),n(")"),l&&n(")]")}function Kr(e,t,n=!1,s=!1,i=Object.create(t.identifiers)){return e}function Xp(e){return Q(e)?e:e.type===4?e.content:e.children.map(Xp).join("")}function Kp(e,t,n,s){if(t.name!=="else"&&(!t.exp||!t.exp.content.trim())){let i=t.exp?t.exp.loc:e.loc;n.onError(ve(28,t.loc)),t.exp=re("true",!1,i)}if(t.name==="if"){let i=tp(e,t),r={type:9,loc:l_(e.loc),branches:[i]};if(n.replaceNode(r),s)return s(r,i,!0)}else{let i=n.parent.children,r=[],o=i.indexOf(e);for(;o-->=-1;){let a=i[o];if(a&&a.type===3){n.removeNode(a);continue}if(a&&a.type===2&&!a.content.trim().length){n.removeNode(a);continue}if(a&&a.type===9){t.name==="else-if"&&a.branches[a.branches.length-1].condition===void 0&&n.onError(ve(30,e.loc)),n.removeNode();let l=tp(e,t);a.branches.push(l);let c=s&&s(a,l,!1);Oi(l,n),c&&c(),n.currentNode=null}else n.onError(ve(30,e.loc));break}}}function tp(e,t){let n=e.tagType===3;return{type:10,loc:e.loc,condition:t.name==="else"?void 0:t.exp,children:n&&!ze(e,"for")?e.children:[e],userKey:Vs(e,"key"),isTemplateIf:n}}function np(e,t,n){return e.condition?Yr(e.condition,sp(e,t,n),De(n.helper(Ds),['""',"true"])):sp(e,t,n)}function sp(e,t,n){let{helper:s}=n,i=Oe("key",re(`${t}`,!1,Pe,2)),{children:r}=e,o=r[0];if(r.length!==1||o.type!==1)if(r.length===1&&o.type===11){let l=o.codegenNode;return yi(l,i,n),l}else return Ts(n,s(Ss),dt([i]),r,64,void 0,void 0,!0,!1,!1,e.loc);else{let l=o.codegenNode,c=xp(l);return c.type===13&&mo(c,n),yi(c,i,n),l}}function V_(e){for(;;)if(e.type===19)if(e.alternate.type===19)e=e.alternate;else return e;else e.type===20&&(e=e.value)}function Jp(e,t,n,s){if(!t.exp){n.onError(ve(31,t.loc));return}let i=t.forParseResult;if(!i){n.onError(ve(32,t.loc));return}Sl(i,n);let{addIdentifiers:r,removeIdentifiers:o,scopes:a}=n,{source:l,value:c,key:f,index:u}=i,p={type:11,loc:t.loc,source:l,valueAlias:c,keyAlias:f,objectIndexAlias:u,parseResult:i,children:Cs(e)?e.children:[e]};n.replaceNode(p),a.vFor++;let d=s&&s(p);return()=>{a.vFor--,d&&d()}}function Sl(e,t){e.finalized||(e.finalized=!0)}function qr({value:e,key:t,index:n},s=[]){return P_([e,t,n,...s])}function P_(e){let t=e.length;for(;t--&&!e[t];);return e.slice(0,t+1).map((n,s)=>n||re("_".repeat(s+1),!1))}function zp(e,t,n=L_){t.helper(po);let{children:s,loc:i}=e,r=[],o=[],a=t.scopes.vSlot>0||t.scopes.vFor>0,l=ze(e,"slot",!0);if(l){let{arg:v,exp:_}=l;v&&!qe(v)&&(a=!0),r.push(Oe(v||re("default",!0),n(_,void 0,s,i)))}let c=!1,f=!1,u=[],p=new Set,d=0;for(let v=0;v<s.length;v++){let _=s[v],g;if(!Cs(_)||!(g=ze(_,"slot",!0))){_.type!==3&&u.push(_);continue}if(l){t.onError(ve(37,g.loc));break}c=!0;let{children:m,loc:w}=_,{arg:S=re("default",!0),exp:I,loc:k}=g,V;qe(S)?V=S?S.content:"default":a=!0;let N=ze(_,"for"),b=n(I,N,m,w),D,y;if(D=ze(_,"if"))a=!0,o.push(Yr(D.exp,$r(S,b,d++),rp));else if(y=ze(_,/^else(-if)?$/,!0)){let x=v,C;for(;x--&&(C=s[x],C.type===3););if(C&&Cs(C)&&ze(C,/^(else-)?if$/)){let R=o[o.length-1];for(;R.alternate.type===19;)R=R.alternate;R.alternate=y.exp?Yr(y.exp,$r(S,b,d++),rp):$r(S,b,d++)}else t.onError(ve(30,y.loc))}else if(N){a=!0;let x=N.forParseResult;x?(Sl(x,t),o.push(De(t.helper(lo),[x.source,Qn(qr(x),$r(S,b),!0)]))):t.onError(ve(32,N.loc))}else{if(V){if(p.has(V)){t.onError(ve(38,k));continue}p.add(V),V==="default"&&(f=!0)}r.push(Oe(S,b))}}if(!l){let v=(_,g)=>{let m=n(_,void 0,g,i);return t.compatConfig&&(m.isNonScopedSlot=!0),Oe("default",m)};c?u.length&&u.some(_=>qp(_))&&(f?t.onError(ve(39,u[0].loc)):r.push(v(void 0,u))):r.push(v(void 0,s))}let h=a?2:Wr(e.children)?3:1,E=dt(r.concat(Oe("_",re(h+"",!1))),i);return o.length&&(E=De(t.helper(dl),[E,Cn(o)])),{slots:E,hasDynamicSlots:a}}function $r(e,t,n){let s=[Oe("name",e),Oe("fn",t)];return n!=null&&s.push(Oe("key",re(String(n),!0))),dt(s)}function Wr(e){for(let t=0;t<e.length;t++){let n=e[t];switch(n.type){case 1:if(n.tagType===2||Wr(n.children))return!0;break;case 9:if(Wr(n.branches))return!0;break;case 10:case 11:if(Wr(n.children))return!0;break}}return!1}function qp(e){return e.type!==2&&e.type!==12?!0:e.type===2?!!e.content.trim():qp(e.content)}function ed(e,t,n=!1){let{tag:s}=e,i=al(s),r=Vs(e,"is",!1,!0);if(r)if(i||zn("COMPILER_IS_ON_ELEMENT",t)){let a;if(r.type===6?a=r.value&&re(r.value.content,!0):(a=r.exp,a||(a=re("is",!1,r.arg.loc))),a)return De(t.helper(io),[a])}else r.type===6&&r.value.content.startsWith("vue:")&&(s=r.value.content.slice(4));let o=El(s)||t.isBuiltInComponent(s);return o?(n||t.helper(o),o):(t.helper(so),t.components.add(s),xs(s,"component"))}function wl(e,t,n=e.props,s,i,r=!1){let{tag:o,loc:a,children:l}=e,c=[],f=[],u=[],p=l.length>0,d=!1,h=0,E=!1,v=!1,_=!1,g=!1,m=!1,w=!1,S=[],I=b=>{c.length&&(f.push(dt(op(c),a)),c=[]),b&&f.push(b)},k=()=>{t.scopes.vFor>0&&c.push(Oe(re("ref_for",!0),re("true")))},V=({key:b,value:D})=>{if(qe(b)){let y=b.content,x=bt(y);if(x&&(!s||i)&&y.toLowerCase()!=="onclick"&&y!=="onUpdate:modelValue"&&!Ot(y)&&(g=!0),x&&Ot(y)&&(w=!0),x&&D.type===14&&(D=D.arguments[0]),D.type===20||(D.type===4||D.type===8)&&nt(D,t)>0)return;y==="ref"?E=!0:y==="class"?v=!0:y==="style"?_=!0:y!=="key"&&!S.includes(y)&&S.push(y),s&&(y==="class"||y==="style")&&!S.includes(y)&&S.push(y)}else m=!0};for(let b=0;b<n.length;b++){let D=n[b];if(D.type===6){let{loc:y,name:x,nameLoc:C,value:R}=D,A=!0;if(x==="ref"&&(E=!0,k()),x==="is"&&(al(o)||R&&R.content.startsWith("vue:")||zn("COMPILER_IS_ON_ELEMENT",t)))continue;c.push(Oe(re(x,!0,C),re(R?R.content:"",A,R?R.loc:y)))}else{let{name:y,arg:x,exp:C,loc:R,modifiers:A}=D,H=y==="bind",j=y==="on";if(y==="slot"){s||t.onError(ve(40,R));continue}if(y==="once"||y==="memo"||y==="is"||H&&Kt(x,"is")&&(al(o)||zn("COMPILER_IS_ON_ELEMENT",t))||j&&r)continue;if((H&&Kt(x,"key")||j&&p&&Kt(x,"vue:before-update"))&&(d=!0),H&&Kt(x,"ref")&&k(),!x&&(H||j)){if(m=!0,C)if(H){if(k(),I(),zn("COMPILER_V_BIND_OBJECT_ORDER",t)){f.unshift(C);continue}f.push(C)}else I({type:14,loc:R,callee:t.helper(fo),arguments:s?[C]:[C,"true"]});else t.onError(ve(H?34:35,R));continue}H&&A.some(X=>X.content==="prop")&&(h|=32);let te=t.directiveTransforms[y];if(te){let{props:X,needRuntime:L}=te(D,e,t);!r&&X.forEach(V),j&&x&&!qe(x)?I(dt(X,a)):c.push(...X),L&&(u.push(D),Ie(L)&&Qp.set(D,L))}else Vi(y)||(u.push(D),p&&(d=!0))}}let N;if(f.length?(I(),f.length>1?N=De(t.helper(Ei),f,a):N=f[0]):c.length&&(N=dt(op(c),a)),m?h|=16:(v&&!s&&(h|=2),_&&!s&&(h|=4),S.length&&(h|=8),g&&(h|=32)),!d&&(h===0||h===32)&&(E||w||u.length>0)&&(h|=512),!t.inSSR&&N)switch(N.type){case 15:let b=-1,D=-1,y=!1;for(let R=0;R<N.properties.length;R++){let A=N.properties[R].key;qe(A)?A.content==="class"?b=R:A.content==="style"&&(D=R):A.isHandlerKey||(y=!0)}let x=N.properties[b],C=N.properties[D];y?N=De(t.helper(ws),[N]):(x&&!qe(x.value)&&(x.value=De(t.helper(co),[x.value])),C&&(_||C.value.type===4&&C.value.content.trim()[0]==="["||C.value.type===17)&&(C.value=De(t.helper(uo),[C.value])));break;case 14:break;default:N=De(t.helper(ws),[De(t.helper(Is),[N])]);break}return{props:N,directives:u,patchFlag:h,dynamicPropNames:S,shouldUseBlock:d}}function op(e){let t=new Map,n=[];for(let s=0;s<e.length;s++){let i=e[s];if(i.key.type===8||!i.key.isStatic){n.push(i);continue}let r=i.key.content,o=t.get(r);o?(r==="style"||r==="class"||bt(r))&&M_(o,i):(t.set(r,i),n.push(i))}return n}function M_(e,t){e.value.type===17?e.value.elements.push(t.value):e.value=Cn([e.value,t.value],e.loc)}function td(e,t){let n=[],s=Qp.get(e);s?n.push(t.helperString(s)):(t.helper(ro),t.directives.add(e.name),n.push(xs(e.name,"directive")));let{loc:i}=e;if(e.exp&&n.push(e.exp),e.arg&&(e.exp||n.push("void 0"),n.push(e.arg)),Object.keys(e.modifiers).length){e.arg||(e.exp||n.push("void 0"),n.push("void 0"));let r=re("true",!1,i);n.push(dt(e.modifiers.map(o=>Oe(o,r)),i))}return Cn(n,e.loc)}function F_(e){let t="[";for(let n=0,s=e.length;n<s;n++)t+=JSON.stringify(e[n]),n<s-1&&(t+=", ");return t+"]"}function al(e){return e==="component"||e==="Component"}function nd(e,t){let n='"default"',s,i=[];for(let r=0;r<e.props.length;r++){let o=e.props[r];if(o.type===6)o.value&&(o.name==="name"?n=JSON.stringify(o.value.content):(o.name=de(o.name),i.push(o)));else if(o.name==="bind"&&Kt(o.arg,"name")){if(o.exp)n=o.exp;else if(o.arg&&o.arg.type===4){let a=de(o.arg.content);n=o.exp=re(a,!1,o.arg.loc)}}else o.name==="bind"&&o.arg&&qe(o.arg)&&(o.arg.content=de(o.arg.content)),i.push(o)}if(i.length>0){let{props:r,directives:o}=wl(e,t,i,!1,!1);s=r,o.length&&t.onError(ve(36,o[0].loc))}return{slotName:n,slotProps:s}}function Ur(e=[]){return{props:e}}function Qr(e,t){if(e.type===4)lp(e,t);else for(let n=0;n<e.children.length;n++){let s=e.children[n];typeof s=="object"&&(s.type===4?lp(s,t):s.type===8?Qr(e,t):s.type===5&&Qr(s.content,t))}}function lp(e,t){let n=e.content,s=!1,i=!1,r=!1,o=!1,a=0,l=0,c=0,f=0,u,p,d,h,E=[];for(d=0;d<n.length;d++)if(p=u,u=n.charCodeAt(d),s)u===39&&p!==92&&(s=!1);else if(i)u===34&&p!==92&&(i=!1);else if(r)u===96&&p!==92&&(r=!1);else if(o)u===47&&p!==92&&(o=!1);else if(u===124&&n.charCodeAt(d+1)!==124&&n.charCodeAt(d-1)!==124&&!a&&!l&&!c)h===void 0?(f=d+1,h=n.slice(0,d).trim()):v();else{switch(u){case 34:i=!0;break;case 39:s=!0;break;case 96:r=!0;break;case 40:c++;break;case 41:c--;break;case 91:l++;break;case 93:l--;break;case 123:a++;break;case 125:a--;break}if(u===47){let _=d-1,g;for(;_>=0&&(g=n.charAt(_),g===" ");_--);(!g||!B_.test(g))&&(o=!0)}}h===void 0?h=n.slice(0,d).trim():f!==0&&v();function v(){E.push(n.slice(f,d).trim()),f=d+1}if(E.length){for(d=0;d<E.length;d++)h=X_(h,E[d],t);e.content=h,e.ast=void 0}}function X_(e,t,n){n.helper(oo);let s=t.indexOf("(");if(s<0)return n.filters.add(t),`${xs(t,"filter")}(${e})`;{let i=t.slice(0,s),r=t.slice(s+1);return n.filters.add(i),`${xs(i,"filter")}(${e}${r!==")"?","+r:r}`}}function sd(e){return[[H_,I_,K_,R_,j_,$_,Zp,Yp,U_],{on:_o,bind:Wp,model:vo}]}function Tl(e,t={}){let n=t.onError||gl,s=t.mode==="module";t.prefixIdentifiers===!0?n(ve(47)):s&&n(ve(48));let i=!1;t.cacheHandlers&&n(ve(49)),t.scopeId&&!s&&n(ve(50));let r=se({},t,{prefixIdentifiers:i}),o=Q(e)?go(e,r):e,[a,l]=sd();return $p(o,se({},r,{nodeTransforms:[...a,...t.nodeTransforms||[]],directiveTransforms:se({},l,t.directiveTransforms||{})})),Hp(o,r)}var Ss,Os,Zr,gi,ll,xn,cl,ul,eo,to,Ds,no,fl,so,io,ro,oo,ao,lo,pl,dl,As,Ei,co,uo,ws,Is,fo,Gr,up,Jr,_i,fp,pp,po,dp,hp,ho,hl,qn,_E,vE,yE,NE,Pe,Jf,Yf,Je,sl,xE,DE,IE,VE,HE,vp,BE,yp,qe,jE,bi,XE,KE,WE,bp,Op,GE,_l,JE,Sp,YE,wp,QE,Dp,Ap,he,Ni,pn,Ye,fe,rt,un,Jn,Nl,Tn,rl,Ne,Se,Qf,ZE,n_,r_,p_,Eo,Up,zv,A_,I_,Wp,Gp,ip,R_,rp,Yp,k_,L_,Qp,Zp,$_,_o,U_,ap,H_,vo,B_,j_,cp,K_,W_,Cl,xl=is(()=>{Pt();Pt();Ss=Symbol(""),Os=Symbol(""),Zr=Symbol(""),gi=Symbol(""),ll=Symbol(""),xn=Symbol(""),cl=Symbol(""),ul=Symbol(""),eo=Symbol(""),to=Symbol(""),Ds=Symbol(""),no=Symbol(""),fl=Symbol(""),so=Symbol(""),io=Symbol(""),ro=Symbol(""),oo=Symbol(""),ao=Symbol(""),lo=Symbol(""),pl=Symbol(""),dl=Symbol(""),As=Symbol(""),Ei=Symbol(""),co=Symbol(""),uo=Symbol(""),ws=Symbol(""),Is=Symbol(""),fo=Symbol(""),Gr=Symbol(""),up=Symbol(""),Jr=Symbol(""),_i=Symbol(""),fp=Symbol(""),pp=Symbol(""),po=Symbol(""),dp=Symbol(""),hp=Symbol(""),ho=Symbol(""),hl=Symbol(""),qn={[Ss]:"Fragment",[Os]:"Teleport",[Zr]:"Suspense",[gi]:"KeepAlive",[ll]:"BaseTransition",[xn]:"openBlock",[cl]:"createBlock",[ul]:"createElementBlock",[eo]:"createVNode",[to]:"createElementVNode",[Ds]:"createCommentVNode",[no]:"createTextVNode",[fl]:"createStaticVNode",[so]:"resolveComponent",[io]:"resolveDynamicComponent",[ro]:"resolveDirective",[oo]:"resolveFilter",[ao]:"withDirectives",[lo]:"renderList",[pl]:"renderSlot",[dl]:"createSlots",[As]:"toDisplayString",[Ei]:"mergeProps",[co]:"normalizeClass",[uo]:"normalizeStyle",[ws]:"normalizeProps",[Is]:"guardReactiveProps",[fo]:"toHandlers",[Gr]:"camelize",[up]:"capitalize",[Jr]:"toHandlerKey",[_i]:"setBlockTracking",[fp]:"pushScopeId",[pp]:"popScopeId",[po]:"withCtx",[dp]:"unref",[hp]:"isRef",[ho]:"withMemo",[hl]:"isMemoSame"};_E={HTML:0,0:"HTML",SVG:1,1:"SVG",MATH_ML:2,2:"MATH_ML"},vE={ROOT:0,0:"ROOT",ELEMENT:1,1:"ELEMENT",TEXT:2,2:"TEXT",COMMENT:3,3:"COMMENT",SIMPLE_EXPRESSION:4,4:"SIMPLE_EXPRESSION",INTERPOLATION:5,5:"INTERPOLATION",ATTRIBUTE:6,6:"ATTRIBUTE",DIRECTIVE:7,7:"DIRECTIVE",COMPOUND_EXPRESSION:8,8:"COMPOUND_EXPRESSION",IF:9,9:"IF",IF_BRANCH:10,10:"IF_BRANCH",FOR:11,11:"FOR",TEXT_CALL:12,12:"TEXT_CALL",VNODE_CALL:13,13:"VNODE_CALL",JS_CALL_EXPRESSION:14,14:"JS_CALL_EXPRESSION",JS_OBJECT_EXPRESSION:15,15:"JS_OBJECT_EXPRESSION",JS_PROPERTY:16,16:"JS_PROPERTY",JS_ARRAY_EXPRESSION:17,17:"JS_ARRAY_EXPRESSION",JS_FUNCTION_EXPRESSION:18,18:"JS_FUNCTION_EXPRESSION",JS_CONDITIONAL_EXPRESSION:19,19:"JS_CONDITIONAL_EXPRESSION",JS_CACHE_EXPRESSION:20,20:"JS_CACHE_EXPRESSION",JS_BLOCK_STATEMENT:21,21:"JS_BLOCK_STATEMENT",JS_TEMPLATE_LITERAL:22,22:"JS_TEMPLATE_LITERAL",JS_IF_STATEMENT:23,23:"JS_IF_STATEMENT",JS_ASSIGNMENT_EXPRESSION:24,24:"JS_ASSIGNMENT_EXPRESSION",JS_SEQUENCE_EXPRESSION:25,25:"JS_SEQUENCE_EXPRESSION",JS_RETURN_STATEMENT:26,26:"JS_RETURN_STATEMENT"},yE={ELEMENT:0,0:"ELEMENT",COMPONENT:1,1:"COMPONENT",SLOT:2,2:"SLOT",TEMPLATE:3,3:"TEMPLATE"},NE={NOT_CONSTANT:0,0:"NOT_CONSTANT",CAN_SKIP_PATCH:1,1:"CAN_SKIP_PATCH",CAN_CACHE:2,2:"CAN_CACHE",CAN_STRINGIFY:3,3:"CAN_STRINGIFY"},Pe={start:{line:1,column:1,offset:0},end:{line:1,column:1,offset:0},source:""};Jf=new Uint8Array([123,123]),Yf=new Uint8Array([125,125]);Je={Cdata:new Uint8Array([67,68,65,84,65,91]),CdataEnd:new Uint8Array([93,93,62]),CommentEnd:new Uint8Array([45,45,62]),ScriptEnd:new Uint8Array([60,47,115,99,114,105,112,116]),StyleEnd:new Uint8Array([60,47,115,116,121,108,101]),TitleEnd:new Uint8Array([60,47,116,105,116,108,101]),TextareaEnd:new Uint8Array([60,47,116,101,120,116,97,114,101,97])},sl=class{constructor(t,n){this.stack=t,this.cbs=n,this.state=1,this.buffer="",this.sectionStart=0,this.index=0,this.entityStart=0,this.baseState=1,this.inRCDATA=!1,this.inXML=!1,this.inVPre=!1,this.newlines=[],this.mode=0,this.delimiterOpen=Jf,this.delimiterClose=Yf,this.delimiterIndex=-1,this.currentSequence=void 0,this.sequenceIndex=0}get inSFCRoot(){return this.mode===2&&this.stack.length===0}reset(){this.state=1,this.mode=0,this.buffer="",this.sectionStart=0,this.index=0,this.baseState=1,this.inRCDATA=!1,this.currentSequence=void 0,this.newlines.length=0,this.delimiterOpen=Jf,this.delimiterClose=Yf}getPos(t){let n=1,s=t+1;for(let i=this.newlines.length-1;i>=0;i--){let r=this.newlines[i];if(t>r){n=i+2,s=t-r;break}}return{column:s,line:n,offset:t}}peek(){return this.buffer.charCodeAt(this.index+1)}stateText(t){t===60?(this.index>this.sectionStart&&this.cbs.ontext(this.sectionStart,this.index),this.state=5,this.sectionStart=this.index):!this.inVPre&&t===this.delimiterOpen[0]&&(this.state=2,this.delimiterIndex=0,this.stateInterpolationOpen(t))}stateInterpolationOpen(t){if(t===this.delimiterOpen[this.delimiterIndex])if(this.delimiterIndex===this.delimiterOpen.length-1){let n=this.index+1-this.delimiterOpen.length;n>this.sectionStart&&this.cbs.ontext(this.sectionStart,n),this.state=3,this.sectionStart=n}else this.delimiterIndex++;else this.inRCDATA?(this.state=32,this.stateInRCDATA(t)):(this.state=1,this.stateText(t))}stateInterpolation(t){t===this.delimiterClose[0]&&(this.state=4,this.delimiterIndex=0,this.stateInterpolationClose(t))}stateInterpolationClose(t){t===this.delimiterClose[this.delimiterIndex]?this.delimiterIndex===this.delimiterClose.length-1?(this.cbs.oninterpolation(this.sectionStart,this.index+1),this.inRCDATA?this.state=32:this.state=1,this.sectionStart=this.index+1):this.delimiterIndex++:(this.state=3,this.stateInterpolation(t))}stateSpecialStartSequence(t){let n=this.sequenceIndex===this.currentSequence.length;if(!(n?wn(t):(t|32)===this.currentSequence[this.sequenceIndex]))this.inRCDATA=!1;else if(!n){this.sequenceIndex++;return}this.sequenceIndex=0,this.state=6,this.stateInTagName(t)}stateInRCDATA(t){if(this.sequenceIndex===this.currentSequence.length){if(t===62||pt(t)){let n=this.index-this.currentSequence.length;if(this.sectionStart<n){let s=this.index;this.index=n,this.cbs.ontext(this.sectionStart,n),this.index=s}this.sectionStart=n+2,this.stateInClosingTagName(t),this.inRCDATA=!1;return}this.sequenceIndex=0}(t|32)===this.currentSequence[this.sequenceIndex]?this.sequenceIndex+=1:this.sequenceIndex===0?this.currentSequence===Je.TitleEnd||this.currentSequence===Je.TextareaEnd&&!this.inSFCRoot?!this.inVPre&&t===this.delimiterOpen[0]&&(this.state=2,this.delimiterIndex=0,this.stateInterpolationOpen(t)):this.fastForwardTo(60)&&(this.sequenceIndex=1):this.sequenceIndex=+(t===60)}stateCDATASequence(t){t===Je.Cdata[this.sequenceIndex]?++this.sequenceIndex===Je.Cdata.length&&(this.state=28,this.currentSequence=Je.CdataEnd,this.sequenceIndex=0,this.sectionStart=this.index+1):(this.sequenceIndex=0,this.state=23,this.stateInDeclaration(t))}fastForwardTo(t){for(;++this.index<this.buffer.length;){let n=this.buffer.charCodeAt(this.index);if(n===10&&this.newlines.push(this.index),n===t)return!0}return this.index=this.buffer.length-1,!1}stateInCommentLike(t){t===this.currentSequence[this.sequenceIndex]?++this.sequenceIndex===this.currentSequence.length&&(this.currentSequence===Je.CdataEnd?this.cbs.oncdata(this.sectionStart,this.index-2):this.cbs.oncomment(this.sectionStart,this.index-2),this.sequenceIndex=0,this.sectionStart=this.index+1,this.state=1):this.sequenceIndex===0?this.fastForwardTo(this.currentSequence[0])&&(this.sequenceIndex=1):t!==this.currentSequence[this.sequenceIndex-1]&&(this.sequenceIndex=0)}startSpecial(t,n){this.enterRCDATA(t,n),this.state=31}enterRCDATA(t,n){this.inRCDATA=!0,this.currentSequence=t,this.sequenceIndex=n}stateBeforeTagName(t){t===33?(this.state=22,this.sectionStart=this.index+1):t===63?(this.state=24,this.sectionStart=this.index+1):zf(t)?(this.sectionStart=this.index,this.mode===0?this.state=6:this.inSFCRoot?this.state=34:this.inXML?this.state=6:t===116?this.state=30:this.state=t===115?29:6):t===47?this.state=8:(this.state=1,this.stateText(t))}stateInTagName(t){wn(t)&&this.handleTagName(t)}stateInSFCRootTagName(t){if(wn(t)){let n=this.buffer.slice(this.sectionStart,this.index);n!=="template"&&this.enterRCDATA(zr("</"+n),0),this.handleTagName(t)}}handleTagName(t){this.cbs.onopentagname(this.sectionStart,this.index),this.sectionStart=-1,this.state=11,this.stateBeforeAttrName(t)}stateBeforeClosingTagName(t){pt(t)||(t===62?(this.state=1,this.sectionStart=this.index+1):(this.state=zf(t)?9:27,this.sectionStart=this.index))}stateInClosingTagName(t){(t===62||pt(t))&&(this.cbs.onclosetag(this.sectionStart,this.index),this.sectionStart=-1,this.state=10,this.stateAfterClosingTagName(t))}stateAfterClosingTagName(t){t===62&&(this.state=1,this.sectionStart=this.index+1)}stateBeforeAttrName(t){t===62?(this.cbs.onopentagend(this.index),this.inRCDATA?this.state=32:this.state=1,this.sectionStart=this.index+1):t===47?this.state=7:t===60&&this.peek()===47?(this.cbs.onopentagend(this.index),this.state=5,this.sectionStart=this.index):pt(t)||this.handleAttrStart(t)}handleAttrStart(t){t===118&&this.peek()===45?(this.state=13,this.sectionStart=this.index):t===46||t===58||t===64||t===35?(this.cbs.ondirname(this.index,this.index+1),this.state=14,this.sectionStart=this.index+1):(this.state=12,this.sectionStart=this.index)}stateInSelfClosingTag(t){t===62?(this.cbs.onselfclosingtag(this.index),this.state=1,this.sectionStart=this.index+1,this.inRCDATA=!1):pt(t)||(this.state=11,this.stateBeforeAttrName(t))}stateInAttrName(t){(t===61||wn(t))&&(this.cbs.onattribname(this.sectionStart,this.index),this.handleAttrNameEnd(t))}stateInDirName(t){t===61||wn(t)?(this.cbs.ondirname(this.sectionStart,this.index),this.handleAttrNameEnd(t)):t===58?(this.cbs.ondirname(this.sectionStart,this.index),this.state=14,this.sectionStart=this.index+1):t===46&&(this.cbs.ondirname(this.sectionStart,this.index),this.state=16,this.sectionStart=this.index+1)}stateInDirArg(t){t===61||wn(t)?(this.cbs.ondirarg(this.sectionStart,this.index),this.handleAttrNameEnd(t)):t===91?this.state=15:t===46&&(this.cbs.ondirarg(this.sectionStart,this.index),this.state=16,this.sectionStart=this.index+1)}stateInDynamicDirArg(t){t===93?this.state=14:(t===61||wn(t))&&(this.cbs.ondirarg(this.sectionStart,this.index+1),this.handleAttrNameEnd(t))}stateInDirModifier(t){t===61||wn(t)?(this.cbs.ondirmodifier(this.sectionStart,this.index),this.handleAttrNameEnd(t)):t===46&&(this.cbs.ondirmodifier(this.sectionStart,this.index),this.sectionStart=this.index+1)}handleAttrNameEnd(t){this.sectionStart=this.index,this.state=17,this.cbs.onattribnameend(this.index),this.stateAfterAttrName(t)}stateAfterAttrName(t){t===61?this.state=18:t===47||t===62?(this.cbs.onattribend(0,this.sectionStart),this.sectionStart=-1,this.state=11,this.stateBeforeAttrName(t)):pt(t)||(this.cbs.onattribend(0,this.sectionStart),this.handleAttrStart(t))}stateBeforeAttrValue(t){t===34?(this.state=19,this.sectionStart=this.index+1):t===39?(this.state=20,this.sectionStart=this.index+1):pt(t)||(this.sectionStart=this.index,this.state=21,this.stateInAttrValueNoQuotes(t))}handleInAttrValue(t,n){(t===n||this.fastForwardTo(n))&&(this.cbs.onattribdata(this.sectionStart,this.index),this.sectionStart=-1,this.cbs.onattribend(n===34?3:2,this.index+1),this.state=11)}stateInAttrValueDoubleQuotes(t){this.handleInAttrValue(t,34)}stateInAttrValueSingleQuotes(t){this.handleInAttrValue(t,39)}stateInAttrValueNoQuotes(t){pt(t)||t===62?(this.cbs.onattribdata(this.sectionStart,this.index),this.sectionStart=-1,this.cbs.onattribend(1,this.index),this.state=11,this.stateBeforeAttrName(t)):(t===39||t===60||t===61||t===96)&&this.cbs.onerr(18,this.index)}stateBeforeDeclaration(t){t===91?(this.state=26,this.sequenceIndex=0):this.state=t===45?25:23}stateInDeclaration(t){(t===62||this.fastForwardTo(62))&&(this.state=1,this.sectionStart=this.index+1)}stateInProcessingInstruction(t){(t===62||this.fastForwardTo(62))&&(this.cbs.onprocessinginstruction(this.sectionStart,this.index),this.state=1,this.sectionStart=this.index+1)}stateBeforeComment(t){t===45?(this.state=28,this.currentSequence=Je.CommentEnd,this.sequenceIndex=2,this.sectionStart=this.index+1):this.state=23}stateInSpecialComment(t){(t===62||this.fastForwardTo(62))&&(this.cbs.oncomment(this.sectionStart,this.index),this.state=1,this.sectionStart=this.index+1)}stateBeforeSpecialS(t){t===Je.ScriptEnd[3]?this.startSpecial(Je.ScriptEnd,4):t===Je.StyleEnd[3]?this.startSpecial(Je.StyleEnd,4):(this.state=6,this.stateInTagName(t))}stateBeforeSpecialT(t){t===Je.TitleEnd[3]?this.startSpecial(Je.TitleEnd,4):t===Je.TextareaEnd[3]?this.startSpecial(Je.TextareaEnd,4):(this.state=6,this.stateInTagName(t))}startEntity(){}stateInEntity(){}parse(t){for(this.buffer=t;this.index<this.buffer.length;){let n=this.buffer.charCodeAt(this.index);switch(n===10&&this.newlines.push(this.index),this.state){case 1:{this.stateText(n);break}case 2:{this.stateInterpolationOpen(n);break}case 3:{this.stateInterpolation(n);break}case 4:{this.stateInterpolationClose(n);break}case 31:{this.stateSpecialStartSequence(n);break}case 32:{this.stateInRCDATA(n);break}case 26:{this.stateCDATASequence(n);break}case 19:{this.stateInAttrValueDoubleQuotes(n);break}case 12:{this.stateInAttrName(n);break}case 13:{this.stateInDirName(n);break}case 14:{this.stateInDirArg(n);break}case 15:{this.stateInDynamicDirArg(n);break}case 16:{this.stateInDirModifier(n);break}case 28:{this.stateInCommentLike(n);break}case 27:{this.stateInSpecialComment(n);break}case 11:{this.stateBeforeAttrName(n);break}case 6:{this.stateInTagName(n);break}case 34:{this.stateInSFCRootTagName(n);break}case 9:{this.stateInClosingTagName(n);break}case 5:{this.stateBeforeTagName(n);break}case 17:{this.stateAfterAttrName(n);break}case 20:{this.stateInAttrValueSingleQuotes(n);break}case 18:{this.stateBeforeAttrValue(n);break}case 8:{this.stateBeforeClosingTagName(n);break}case 10:{this.stateAfterClosingTagName(n);break}case 29:{this.stateBeforeSpecialS(n);break}case 30:{this.stateBeforeSpecialT(n);break}case 21:{this.stateInAttrValueNoQuotes(n);break}case 7:{this.stateInSelfClosingTag(n);break}case 23:{this.stateInDeclaration(n);break}case 22:{this.stateBeforeDeclaration(n);break}case 25:{this.stateBeforeComment(n);break}case 24:{this.stateInProcessingInstruction(n);break}case 33:{this.stateInEntity();break}}this.index++}this.cleanup(),this.finish()}cleanup(){this.sectionStart!==this.index&&(this.state===1||this.state===32&&this.sequenceIndex===0?(this.cbs.ontext(this.sectionStart,this.index),this.sectionStart=this.index):(this.state===19||this.state===20||this.state===21)&&(this.cbs.onattribdata(this.sectionStart,this.index),this.sectionStart=this.index))}finish(){this.handleTrailingData(),this.cbs.onend()}handleTrailingData(){let t=this.buffer.length;this.sectionStart>=t||(this.state===28?this.currentSequence===Je.CdataEnd?this.cbs.oncdata(this.sectionStart,t):this.cbs.oncomment(this.sectionStart,t):this.state===6||this.state===11||this.state===18||this.state===17||this.state===12||this.state===13||this.state===14||this.state===15||this.state===16||this.state===20||this.state===19||this.state===21||this.state===9||this.cbs.ontext(this.sectionStart,t))}emitCodePoint(t,n){}},xE={COMPILER_IS_ON_ELEMENT:"COMPILER_IS_ON_ELEMENT",COMPILER_V_BIND_SYNC:"COMPILER_V_BIND_SYNC",COMPILER_V_BIND_OBJECT_ORDER:"COMPILER_V_BIND_OBJECT_ORDER",COMPILER_V_ON_NATIVE:"COMPILER_V_ON_NATIVE",COMPILER_V_IF_V_FOR_PRECEDENCE:"COMPILER_V_IF_V_FOR_PRECEDENCE",COMPILER_NATIVE_TEMPLATE:"COMPILER_NATIVE_TEMPLATE",COMPILER_INLINE_TEMPLATE:"COMPILER_INLINE_TEMPLATE",COMPILER_FILTERS:"COMPILER_FILTERS"},DE={COMPILER_IS_ON_ELEMENT:{message:'Platform-native elements with "is" prop will no longer be treated as components in Vue 3 unless the "is" value is explicitly prefixed with "vue:".',link:"https://v3-migration.vuejs.org/breaking-changes/custom-elements-interop.html"},COMPILER_V_BIND_SYNC:{message:e=>`.sync modifier for v-bind has been removed. Use v-model with argument instead. \`v-bind:${e}.sync\` should be changed to \`v-model:${e}\`.`,link:"https://v3-migration.vuejs.org/breaking-changes/v-model.html"},COMPILER_V_BIND_OBJECT_ORDER:{message:'v-bind="obj" usage is now order sensitive and behaves like JavaScript object spread: it will now overwrite an existing non-mergeable attribute that appears before v-bind in the case of conflict. To retain 2.x behavior, move v-bind to make it the first attribute. You can also suppress this warning if the usage is intended.',link:"https://v3-migration.vuejs.org/breaking-changes/v-bind.html"},COMPILER_V_ON_NATIVE:{message:".native modifier for v-on has been removed as is no longer necessary.",link:"https://v3-migration.vuejs.org/breaking-changes/v-on-native-modifier-removed.html"},COMPILER_V_IF_V_FOR_PRECEDENCE:{message:"v-if / v-for precedence when used on the same element has changed in Vue 3: v-if now takes higher precedence and will no longer have access to v-for scope variables. It is best to avoid the ambiguity with <template> tags or use a computed property that filters v-for data source.",link:"https://v3-migration.vuejs.org/breaking-changes/v-if-v-for.html"},COMPILER_NATIVE_TEMPLATE:{message:"<template> with no special directives will render as a native template element instead of its inner content in Vue 3."},COMPILER_INLINE_TEMPLATE:{message:'"inline-template" has been removed in Vue 3.',link:"https://v3-migration.vuejs.org/breaking-changes/inline-template-attribute.html"},COMPILER_FILTERS:{message:'filters have been removed in Vue 3. The "|" symbol will be treated as native JavaScript bitwise OR operator. Use method calls or computed properties instead.',link:"https://v3-migration.vuejs.org/breaking-changes/filters.html"}};IE={ABRUPT_CLOSING_OF_EMPTY_COMMENT:0,0:"ABRUPT_CLOSING_OF_EMPTY_COMMENT",CDATA_IN_HTML_CONTENT:1,1:"CDATA_IN_HTML_CONTENT",DUPLICATE_ATTRIBUTE:2,2:"DUPLICATE_ATTRIBUTE",END_TAG_WITH_ATTRIBUTES:3,3:"END_TAG_WITH_ATTRIBUTES",END_TAG_WITH_TRAILING_SOLIDUS:4,4:"END_TAG_WITH_TRAILING_SOLIDUS",EOF_BEFORE_TAG_NAME:5,5:"EOF_BEFORE_TAG_NAME",EOF_IN_CDATA:6,6:"EOF_IN_CDATA",EOF_IN_COMMENT:7,7:"EOF_IN_COMMENT",EOF_IN_SCRIPT_HTML_COMMENT_LIKE_TEXT:8,8:"EOF_IN_SCRIPT_HTML_COMMENT_LIKE_TEXT",EOF_IN_TAG:9,9:"EOF_IN_TAG",INCORRECTLY_CLOSED_COMMENT:10,10:"INCORRECTLY_CLOSED_COMMENT",INCORRECTLY_OPENED_COMMENT:11,11:"INCORRECTLY_OPENED_COMMENT",INVALID_FIRST_CHARACTER_OF_TAG_NAME:12,12:"INVALID_FIRST_CHARACTER_OF_TAG_NAME",MISSING_ATTRIBUTE_VALUE:13,13:"MISSING_ATTRIBUTE_VALUE",MISSING_END_TAG_NAME:14,14:"MISSING_END_TAG_NAME",MISSING_WHITESPACE_BETWEEN_ATTRIBUTES:15,15:"MISSING_WHITESPACE_BETWEEN_ATTRIBUTES",NESTED_COMMENT:16,16:"NESTED_COMMENT",UNEXPECTED_CHARACTER_IN_ATTRIBUTE_NAME:17,17:"UNEXPECTED_CHARACTER_IN_ATTRIBUTE_NAME",UNEXPECTED_CHARACTER_IN_UNQUOTED_ATTRIBUTE_VALUE:18,18:"UNEXPECTED_CHARACTER_IN_UNQUOTED_ATTRIBUTE_VALUE",UNEXPECTED_EQUALS_SIGN_BEFORE_ATTRIBUTE_NAME:19,19:"UNEXPECTED_EQUALS_SIGN_BEFORE_ATTRIBUTE_NAME",UNEXPECTED_NULL_CHARACTER:20,20:"UNEXPECTED_NULL_CHARACTER",UNEXPECTED_QUESTION_MARK_INSTEAD_OF_TAG_NAME:21,21:"UNEXPECTED_QUESTION_MARK_INSTEAD_OF_TAG_NAME",UNEXPECTED_SOLIDUS_IN_TAG:22,22:"UNEXPECTED_SOLIDUS_IN_TAG",X_INVALID_END_TAG:23,23:"X_INVALID_END_TAG",X_MISSING_END_TAG:24,24:"X_MISSING_END_TAG",X_MISSING_INTERPOLATION_END:25,25:"X_MISSING_INTERPOLATION_END",X_MISSING_DIRECTIVE_NAME:26,26:"X_MISSING_DIRECTIVE_NAME",X_MISSING_DYNAMIC_DIRECTIVE_ARGUMENT_END:27,27:"X_MISSING_DYNAMIC_DIRECTIVE_ARGUMENT_END",X_V_IF_NO_EXPRESSION:28,28:"X_V_IF_NO_EXPRESSION",X_V_IF_SAME_KEY:29,29:"X_V_IF_SAME_KEY",X_V_ELSE_NO_ADJACENT_IF:30,30:"X_V_ELSE_NO_ADJACENT_IF",X_V_FOR_NO_EXPRESSION:31,31:"X_V_FOR_NO_EXPRESSION",X_V_FOR_MALFORMED_EXPRESSION:32,32:"X_V_FOR_MALFORMED_EXPRESSION",X_V_FOR_TEMPLATE_KEY_PLACEMENT:33,33:"X_V_FOR_TEMPLATE_KEY_PLACEMENT",X_V_BIND_NO_EXPRESSION:34,34:"X_V_BIND_NO_EXPRESSION",X_V_ON_NO_EXPRESSION:35,35:"X_V_ON_NO_EXPRESSION",X_V_SLOT_UNEXPECTED_DIRECTIVE_ON_SLOT_OUTLET:36,36:"X_V_SLOT_UNEXPECTED_DIRECTIVE_ON_SLOT_OUTLET",X_V_SLOT_MIXED_SLOT_USAGE:37,37:"X_V_SLOT_MIXED_SLOT_USAGE",X_V_SLOT_DUPLICATE_SLOT_NAMES:38,38:"X_V_SLOT_DUPLICATE_SLOT_NAMES",X_V_SLOT_EXTRANEOUS_DEFAULT_SLOT_CHILDREN:39,39:"X_V_SLOT_EXTRANEOUS_DEFAULT_SLOT_CHILDREN",X_V_SLOT_MISPLACED:40,40:"X_V_SLOT_MISPLACED",X_V_MODEL_NO_EXPRESSION:41,41:"X_V_MODEL_NO_EXPRESSION",X_V_MODEL_MALFORMED_EXPRESSION:42,42:"X_V_MODEL_MALFORMED_EXPRESSION",X_V_MODEL_ON_SCOPE_VARIABLE:43,43:"X_V_MODEL_ON_SCOPE_VARIABLE",X_V_MODEL_ON_PROPS:44,44:"X_V_MODEL_ON_PROPS",X_INVALID_EXPRESSION:45,45:"X_INVALID_EXPRESSION",X_KEEP_ALIVE_INVALID_CHILDREN:46,46:"X_KEEP_ALIVE_INVALID_CHILDREN",X_PREFIX_ID_NOT_SUPPORTED:47,47:"X_PREFIX_ID_NOT_SUPPORTED",X_MODULE_MODE_NOT_SUPPORTED:48,48:"X_MODULE_MODE_NOT_SUPPORTED",X_CACHE_HANDLER_NOT_SUPPORTED:49,49:"X_CACHE_HANDLER_NOT_SUPPORTED",X_SCOPE_ID_NOT_SUPPORTED:50,50:"X_SCOPE_ID_NOT_SUPPORTED",X_VNODE_HOOKS:51,51:"X_VNODE_HOOKS",X_V_BIND_INVALID_SAME_NAME_ARGUMENT:52,52:"X_V_BIND_INVALID_SAME_NAME_ARGUMENT",__EXTEND_POINT__:53,53:"__EXTEND_POINT__"},VE={0:"Illegal comment.",1:"CDATA section is allowed only in XML context.",2:"Duplicate attribute.",3:"End tag cannot have attributes.",4:"Illegal '/' in tags.",5:"Unexpected EOF in tag.",6:"Unexpected EOF in CDATA section.",7:"Unexpected EOF in comment.",8:"Unexpected EOF in script.",9:"Unexpected EOF in tag.",10:"Incorrectly closed comment.",11:"Incorrectly opened comment.",12:"Illegal tag name. Use '&lt;' to print '<'.",13:"Attribute value was expected.",14:"End tag name was expected.",15:"Whitespace was expected.",16:"Unexpected '<!--' in comment.",17:`Attribute name cannot contain U+0022 ("), U+0027 ('), and U+003C (<).`,18:"Unquoted attribute value cannot contain U+0022 (\"), U+0027 ('), U+003C (<), U+003D (=), and U+0060 (`).",19:"Attribute name cannot start with '='.",21:"'<?' is allowed only in XML context.",20:"Unexpected null character.",22:"Illegal '/' in tags.",23:"Invalid end tag.",24:"Element is missing end tag.",25:"Interpolation end sign was not found.",27:"End bracket for dynamic directive argument was not found. Note that dynamic directive argument cannot contain spaces.",26:"Legal directive name was expected.",28:"v-if/v-else-if is missing expression.",29:"v-if/else branches must use unique keys.",30:"v-else/v-else-if has no adjacent v-if or v-else-if.",31:"v-for is missing expression.",32:"v-for has invalid expression.",33:"<template v-for> key should be placed on the <template> tag.",34:"v-bind is missing expression.",52:"v-bind with same-name shorthand only allows static argument.",35:"v-on is missing expression.",36:"Unexpected custom directive on <slot> outlet.",37:"Mixed v-slot usage on both the component and nested <template>. When there are multiple named slots, all slots should use <template> syntax to avoid scope ambiguity.",38:"Duplicate slot names found. ",39:"Extraneous children found when component already has explicitly named default slot. These children will be ignored.",40:"v-slot can only be used on components or <template> tags.",41:"v-model is missing expression.",42:"v-model value must be a valid JavaScript member expression.",43:"v-model cannot be used on v-for or v-slot scope variables because they are not writable.",44:`v-model cannot be used on a prop, because local prop bindings are not writable.
 Use a v-bind binding combined with a v-on listener that emits update:x event instead.`,45:"Error parsing JavaScript expression: ",46:"<KeepAlive> expects exactly one child component.",51:"@vnode-* hooks in templates are no longer supported. Use the vue: prefix instead. For example, @vnode-mounted should be changed to @vue:mounted. @vnode-* hooks support has been removed in 3.4.",47:'"prefixIdentifiers" option is not supported in this build of compiler.',48:"ES module mode is not supported in this build of compiler.",49:'"cacheHandlers" option is only supported when the "prefixIdentifiers" option is enabled.',50:'"scopeId" option is only supported in module mode.',53:""};HE=e=>/Function(?:Expression|Declaration)$|Method$/.test(e.type),vp=e=>e&&(e.type==="ObjectProperty"||e.type==="ObjectMethod")&&!e.computed,BE=(e,t)=>vp(t)&&t.key===e,yp=["TSAsExpression","TSTypeAssertion","TSNonNullExpression","TSInstantiationExpression","TSSatisfiesExpression"];qe=e=>e.type===4&&e.isStatic;jE=/^\d|[^\$\w\xA0-\uFFFF]/,bi=e=>!jE.test(e),XE=/[A-Za-z_$\xA0-\uFFFF]/,KE=/[\.\?\w$\xA0-\uFFFF]/,WE=/\s+[.[]\s*|\s*[.[]\s+/g,bp=e=>e.type===4?e.content:e.loc.source,Op=e=>{let t=bp(e).trim().replace(WE,a=>a.trim()),n=0,s=[],i=0,r=0,o=null;for(let a=0;a<t.length;a++){let l=t.charAt(a);switch(n){case 0:if(l==="[")s.push(n),n=1,i++;else if(l==="(")s.push(n),n=2,r++;else if(!(a===0?XE:KE).test(l))return!1;break;case 1:l==="'"||l==='"'||l==="`"?(s.push(n),n=3,o=l):l==="["?i++:l==="]"&&(--i||(n=s.pop()));break;case 2:if(l==="'"||l==='"'||l==="`")s.push(n),n=3,o=l;else if(l==="(")r++;else if(l===")"){if(a===t.length-1)return!1;--r||(n=s.pop())}break;case 3:l===o&&(n=s.pop(),o=null);break}}return!i&&!r},GE=Ee,_l=Op,JE=/^\s*(async\s*)?(\([^)]*?\)|[\w$_]+)\s*(:[^=]+)?=>|^\s*(async\s+)?function(?:\s+[\w$]+)?\s*\(/,Sp=e=>JE.test(bp(e)),YE=Ee,wp=Sp;QE=new Set([ws,Is]);Dp=/([\s\S]*?)\s+(?:in|of)\s+(\S[\s\S]*)/,Ap={parseMode:"base",ns:0,delimiters:["{{","}}"],getNamespace:()=>0,isVoidTag:hn,isPreTag:hn,isIgnoreNewlineTag:hn,isCustomElement:hn,onError:gl,onWarn:_p,comments:!1,prefixIdentifiers:!1},he=Ap,Ni=null,pn="",Ye=null,fe=null,rt="",un=-1,Jn=-1,Nl=0,Tn=!1,rl=null,Ne=[],Se=new sl(Ne,{onerr:cn,ontext(e,t){Fr(je(e,t),e,t)},ontextentity(e,t,n){Fr(e,t,n)},oninterpolation(e,t){if(Tn)return Fr(je(e,t),e,t);let n=e+Se.delimiterOpen.length,s=t-Se.delimiterClose.length;for(;pt(pn.charCodeAt(n));)n++;for(;pt(pn.charCodeAt(s-1));)s--;let i=je(n,s);i.includes("&")&&(i=he.decodeEntities(i,!1)),ol({type:5,content:jr(i,!1,xe(n,s)),loc:xe(e,t)})},onopentagname(e,t){let n=je(e,t);Ye={type:1,tag:n,ns:he.getNamespace(n,Ne[0],he.ns),tagType:0,props:[],children:[],loc:xe(e-1,t),codegenNode:void 0}},onopentagend(e){Zf(e)},onclosetag(e,t){let n=je(e,t);if(!he.isVoidTag(n)){let s=!1;for(let i=0;i<Ne.length;i++)if(Ne[i].tag.toLowerCase()===n.toLowerCase()){s=!0,i>0&&cn(24,Ne[0].loc.start.offset);for(let o=0;o<=i;o++){let a=Ne.shift();Br(a,t,o<i)}break}s||cn(23,Ip(e,60))}},onselfclosingtag(e){let t=Ye.tag;Ye.isSelfClosing=!0,Zf(e),Ne[0]&&Ne[0].tag===t&&Br(Ne.shift(),e)},onattribname(e,t){fe={type:6,name:je(e,t),nameLoc:xe(e,t),value:void 0,loc:xe(e)}},ondirname(e,t){let n=je(e,t),s=n==="."||n===":"?"bind":n==="@"?"on":n==="#"?"slot":n.slice(2);if(!Tn&&s===""&&cn(26,e),Tn||s==="")fe={type:6,name:n,nameLoc:xe(e,t),value:void 0,loc:xe(e)};else if(fe={type:7,name:s,rawName:n,exp:void 0,arg:void 0,modifiers:n==="."?[re("prop")]:[],loc:xe(e)},s==="pre"){Tn=Se.inVPre=!0,rl=Ye;let i=Ye.props;for(let r=0;r<i.length;r++)i[r].type===7&&(i[r]=c_(i[r]))}},ondirarg(e,t){if(e===t)return;let n=je(e,t);if(Tn)fe.name+=n,Yn(fe.nameLoc,t);else{let s=n[0]!=="[";fe.arg=jr(s?n:n.slice(1,-1),s,xe(e,t),s?3:0)}},ondirmodifier(e,t){let n=je(e,t);if(Tn)fe.name+="."+n,Yn(fe.nameLoc,t);else if(fe.name==="slot"){let s=fe.arg;s&&(s.content+="."+n,Yn(s.loc,t))}else{let s=re(n,!0,xe(e,t));fe.modifiers.push(s)}},onattribdata(e,t){rt+=je(e,t),un<0&&(un=e),Jn=t},onattribentity(e,t,n){rt+=e,un<0&&(un=t),Jn=n},onattribnameend(e){let t=fe.loc.start.offset,n=je(t,e);fe.type===7&&(fe.rawName=n),Ye.props.some(s=>(s.type===7?s.rawName:s.name)===n)&&cn(2,t)},onattribend(e,t){if(Ye&&fe){if(Yn(fe.loc,t),e!==0)if(rt.includes("&")&&(rt=he.decodeEntities(rt,!0)),fe.type===6)fe.name==="class"&&(rt=Rp(rt).trim()),e===1&&!rt&&cn(13,t),fe.value={type:2,content:rt,loc:e===1?xe(un,Jn):xe(un-1,Jn+1)},Se.inSFCRoot&&Ye.tag==="template"&&fe.name==="lang"&&rt&&rt!=="html"&&Se.enterRCDATA(zr("</template"),0);else{let n=0;fe.exp=jr(rt,!1,xe(un,Jn),0,n),fe.name==="for"&&(fe.forParseResult=e_(fe.exp));let s=-1;fe.name==="bind"&&(s=fe.modifiers.findIndex(i=>i.content==="sync"))>-1&&ts("COMPILER_V_BIND_SYNC",he,fe.loc,fe.rawName)&&(fe.name="model",fe.modifiers.splice(s,1))}(fe.type!==7||fe.name!=="pre")&&Ye.props.push(fe)}rt="",un=Jn=-1},oncomment(e,t){he.comments&&ol({type:3,content:je(e,t),loc:xe(e-4,t+3)})},onend(){let e=pn.length;for(let t=0;t<Ne.length;t++)Br(Ne[t],e-1),cn(24,Ne[t].loc.start.offset)},oncdata(e,t){Ne[0].ns!==0?Fr(je(e,t),e,t):cn(1,e-9)},onprocessinginstruction(e){(Ne[0]?Ne[0].ns:he.ns)===0&&cn(21,e-1)}}),Qf=/,([^,\}\]]*)(?:,([^,\}\]]*))?$/,ZE=/^\(|\)$/g;n_=new Set(["if","else","else-if","for","slot"]);r_=/\r\n/g;p_=new Set([co,uo,ws,Is]);Eo="/*@__PURE__*/",Up=e=>`${qn[e]}: _${qn[e]}`;zv=new RegExp("\\b"+"arguments,await,break,case,catch,class,const,continue,debugger,default,delete,do,else,export,extends,finally,for,function,if,import,let,new,return,super,switch,throw,try,var,void,while,with,yield".split(",").join("\\b|\\b")+"\\b"),A_=(e,t)=>{if(e.type===5)e.content=Kr(e.content,t);else if(e.type===1)for(let n=0;n<e.props.length;n++){let s=e.props[n];if(s.type===7&&s.name!=="for"){let i=s.exp,r=s.arg;i&&i.type===4&&!(s.name==="on"&&r)&&(s.exp=Kr(i,t,s.name==="slot")),r&&r.type===4&&!r.isStatic&&(s.arg=Kr(r,t))}}};I_=bl(/^(if|else|else-if)$/,(e,t,n)=>Kp(e,t,n,(s,i,r)=>{let o=n.parent.children,a=o.indexOf(s),l=0;for(;a-->=0;){let c=o[a];c&&c.type===9&&(l+=c.branches.length)}return()=>{if(r)s.codegenNode=np(i,l,n);else{let c=V_(s.codegenNode);c.alternate=np(i,l+s.branches.length-1,n)}}}));Wp=(e,t,n)=>{let{modifiers:s,loc:i}=e,r=e.arg,{exp:o}=e;if(o&&o.type===4&&!o.content.trim()&&(o=void 0),!o){if(r.type!==4||!r.isStatic)return n.onError(ve(52,r.loc)),{props:[Oe(r,re("",!0,i))]};Gp(e),o=e.exp}return r.type!==4?(r.children.unshift("("),r.children.push(') || ""')):r.isStatic||(r.content=`${r.content} || ""`),s.some(a=>a.content==="camel")&&(r.type===4?r.isStatic?r.content=de(r.content):r.content=`${n.helperString(Gr)}(${r.content})`:(r.children.unshift(`${n.helperString(Gr)}(`),r.children.push(")"))),n.inSSR||(s.some(a=>a.content==="prop")&&ip(r,"."),s.some(a=>a.content==="attr")&&ip(r,"^")),{props:[Oe(r,o)]}},Gp=(e,t)=>{let n=e.arg,s=de(n.content);e.exp=re(s,!1,n.loc)},ip=(e,t)=>{e.type===4?e.isStatic?e.content=t+e.content:e.content=`\`${t}\${${e.content}}\``:(e.children.unshift(`'${t}' + (`),e.children.push(")"))},R_=bl("for",(e,t,n)=>{let{helper:s,removeHelper:i}=n;return Jp(e,t,n,r=>{let o=De(s(lo),[r.source]),a=Cs(e),l=ze(e,"memo"),c=Vs(e,"key",!1,!0);c&&c.type===7&&!c.exp&&Gp(c);let f=c&&(c.type===6?c.value?re(c.value.content,!0):void 0:c.exp),u=c&&f?Oe("key",f):null,p=r.source.type===4&&r.source.constType>0,d=p?64:c?128:256;return r.codegenNode=Ts(n,s(Ss),void 0,o,d,void 0,void 0,!0,!p,!1,e.loc),()=>{let h,{children:E}=r,v=E.length!==1||E[0].type!==1,_=vi(e)?e:a&&e.children.length===1&&vi(e.children[0])?e.children[0]:null;if(_?(h=_.codegenNode,a&&u&&yi(h,u,n)):v?h=Ts(n,s(Ss),u?dt([u]):void 0,e.children,64,void 0,void 0,!0,void 0,!1):(h=E[0].codegenNode,a&&u&&yi(h,u,n),h.isBlock!==!p&&(h.isBlock?(i(xn),i(es(n.inSSR,h.isComponent))):i(Zn(n.inSSR,h.isComponent))),h.isBlock=!p,h.isBlock?(s(xn),s(es(n.inSSR,h.isComponent))):s(Zn(n.inSSR,h.isComponent))),l){let g=Qn(qr(r.parseResult,[re("_cached")]));g.body=Ep([ht(["const _memo = (",l.exp,")"]),ht(["if (_cached",...f?[" && _cached.key === ",f]:[],` && ${n.helperString(hl)}(_cached, _memo)) return _cached`]),ht(["const _item = ",h]),re("_item.memo = _memo"),re("return _item")]),o.arguments.push(g,re("_cache"),re(String(n.cached.length))),n.cached.push(null)}else o.arguments.push(Qn(qr(r.parseResult),h,!0))}})});rp=re("undefined",!1),Yp=(e,t)=>{if(e.type===1&&(e.tagType===1||e.tagType===3)){let n=ze(e,"slot");if(n)return n.exp,t.scopes.vSlot++,()=>{t.scopes.vSlot--}}},k_=(e,t)=>{let n;if(Cs(e)&&e.props.some(yl)&&(n=ze(e,"for"))){let s=n.forParseResult;if(s){Sl(s,t);let{value:i,key:r,index:o}=s,{addIdentifiers:a,removeIdentifiers:l}=t;return i&&a(i),r&&a(r),o&&a(o),()=>{i&&l(i),r&&l(r),o&&l(o)}}}},L_=(e,t,n,s)=>Qn(e,n,!1,!0,n.length?n[0].loc:s);Qp=new WeakMap,Zp=(e,t)=>function(){if(e=t.currentNode,!(e.type===1&&(e.tagType===0||e.tagType===1)))return;let{tag:s,props:i}=e,r=e.tagType===1,o=r?ed(e,t):`"${s}"`,a=le(o)&&o.callee===io,l,c,f=0,u,p,d,h=a||o===Os||o===Zr||!r&&(s==="svg"||s==="foreignObject"||s==="math");if(i.length>0){let E=wl(e,t,void 0,r,a);l=E.props,f=E.patchFlag,p=E.dynamicPropNames;let v=E.directives;d=v&&v.length?Cn(v.map(_=>td(_,t))):void 0,E.shouldUseBlock&&(h=!0)}if(e.children.length>0)if(o===gi&&(h=!0,f|=1024),r&&o!==Os&&o!==gi){let{slots:v,hasDynamicSlots:_}=zp(e,t);c=v,_&&(f|=1024)}else if(e.children.length===1&&o!==Os){let v=e.children[0],_=v.type,g=_===5||_===8;g&&nt(v,t)===0&&(f|=1),g||_===2?c=v:c=e.children}else c=e.children;p&&p.length&&(u=F_(p)),e.codegenNode=Ts(t,o,l,c,f===0?void 0:f,u,d,!!h,!1,r,e.loc)};$_=(e,t)=>{if(vi(e)){let{children:n,loc:s}=e,{slotName:i,slotProps:r}=nd(e,t),o=[t.prefixIdentifiers?"_ctx.$slots":"$slots",i,"{}","undefined","true"],a=2;r&&(o[2]=r,a=3),n.length&&(o[3]=Qn([],n,!1,!1,s),a=4),t.scopeId&&!t.slotted&&(a=5),o.splice(a),e.codegenNode=De(t.helper(pl),o,s)}};_o=(e,t,n,s)=>{let{loc:i,modifiers:r,arg:o}=e;!e.exp&&!r.length&&n.onError(ve(35,i));let a;if(o.type===4)if(o.isStatic){let u=o.content;u.startsWith("vue:")&&(u=`vnode-${u.slice(4)}`);let p=t.tagType!==0||u.startsWith("vnode")||!/[A-Z]/.test(u)?Vt(de(u)):`on:${u}`;a=re(p,!0,o.loc)}else a=ht([`${n.helperString(Jr)}(`,o,")"]);else a=o,a.children.unshift(`${n.helperString(Jr)}(`),a.children.push(")");let l=e.exp;l&&!l.content.trim()&&(l=void 0);let c=n.cacheHandlers&&!l&&!n.inVOnce;if(l){let u=_l(l),p=!(u||wp(l)),d=l.content.includes(";");(p||c&&u)&&(l=ht([`${p?"$event":"(...args)"} => ${d?"{":"("}`,l,d?"}":")"]))}let f={props:[Oe(a,l||re("() => {}",!1,i))]};return s&&(f=s(f)),c&&(f.props[0].value=n.cache(f.props[0].value)),f.props.forEach(u=>u.key.isHandlerKey=!0),f},U_=(e,t)=>{if(e.type===0||e.type===1||e.type===11||e.type===10)return()=>{let n=e.children,s,i=!1;for(let r=0;r<n.length;r++){let o=n[r];if(Hr(o)){i=!0;for(let a=r+1;a<n.length;a++){let l=n[a];if(Hr(l))s||(s=n[r]=ht([o],o.loc)),s.children.push(" + ",l),n.splice(a,1),a--;else{s=void 0;break}}}}if(!(!i||n.length===1&&(e.type===0||e.type===1&&e.tagType===0&&!e.props.find(r=>r.type===7&&!t.directiveTransforms[r.name])&&e.tag!=="template")))for(let r=0;r<n.length;r++){let o=n[r];if(Hr(o)||o.type===8){let a=[];(o.type!==2||o.content!==" ")&&a.push(o),!t.ssr&&nt(o,t)===0&&a.push("1"),n[r]={type:12,content:o,loc:o.loc,codegenNode:De(t.helper(no),a)}}}}},ap=new WeakSet,H_=(e,t)=>{if(e.type===1&&ze(e,"once",!0))return ap.has(e)||t.inVOnce||t.inSSR?void 0:(ap.add(e),t.inVOnce=!0,t.helper(_i),()=>{t.inVOnce=!1;let n=t.currentNode;n.codegenNode&&(n.codegenNode=t.cache(n.codegenNode,!0))})},vo=(e,t,n)=>{let{exp:s,arg:i}=e;if(!s)return n.onError(ve(41,e.loc)),Ur();let r=s.loc.source.trim(),o=s.type===4?s.content:r,a=n.bindingMetadata[r];if(a==="props"||a==="props-aliased")return n.onError(ve(44,s.loc)),Ur();if(!o.trim()||!_l(s)&&!!1)return n.onError(ve(42,s.loc)),Ur();let c=i||re("modelValue",!0),f=i?qe(i)?`onUpdate:${de(i.content)}`:ht(['"onUpdate:" + ',i]):"onUpdate:modelValue",u,p=n.isTS?"($event: any)":"$event";u=ht([`${p} => ((`,s,") = $event)"]);let d=[Oe(c,e.exp),Oe(f,u)];if(e.modifiers.length&&t.tagType===1){let h=e.modifiers.map(v=>v.content).map(v=>(bi(v)?v:JSON.stringify(v))+": true").join(", "),E=i?qe(i)?`${i.content}Modifiers`:ht([i,' + "Modifiers"']):"modelModifiers";d.push(Oe(E,re(`{ ${h} }`,!1,e.loc,2)))}return Ur(d)};B_=/[\w).+\-_$\]]/,j_=(e,t)=>{zn("COMPILER_FILTERS",t)&&(e.type===5?Qr(e.content,t):e.type===1&&e.props.forEach(n=>{n.type===7&&n.name!=="for"&&n.exp&&Qr(n.exp,t)}))};cp=new WeakSet,K_=(e,t)=>{if(e.type===1){let n=ze(e,"memo");return!n||cp.has(e)?void 0:(cp.add(e),()=>{let s=e.codegenNode||t.currentNode.codegenNode;s&&s.type===13&&(e.tagType!==1&&mo(s,t),e.codegenNode=De(t.helper(ho),[n.exp,Qn(void 0,s),"_cache",String(t.cached.length)]),t.cached.push(null))})}};W_={DATA:"data",PROPS:"props",PROPS_ALIASED:"props-aliased",SETUP_LET:"setup-let",SETUP_CONST:"setup-const",SETUP_REACTIVE_CONST:"setup-reactive-const",SETUP_MAYBE_REF:"setup-maybe-ref",SETUP_REF:"setup-ref",OPTIONS:"options",LITERAL_CONST:"literal-const"},Cl=()=>({props:[]})});var cd={};To(cd,{BASE_TRANSITION:()=>ll,BindingTypes:()=>W_,CAMELIZE:()=>Gr,CAPITALIZE:()=>up,CREATE_BLOCK:()=>cl,CREATE_COMMENT:()=>Ds,CREATE_ELEMENT_BLOCK:()=>ul,CREATE_ELEMENT_VNODE:()=>to,CREATE_SLOTS:()=>dl,CREATE_STATIC:()=>fl,CREATE_TEXT:()=>no,CREATE_VNODE:()=>eo,CompilerDeprecationTypes:()=>xE,ConstantTypes:()=>NE,DOMDirectiveTransforms:()=>ld,DOMErrorCodes:()=>Y_,DOMErrorMessages:()=>z_,DOMNodeTransforms:()=>ad,ElementTypes:()=>yE,ErrorCodes:()=>IE,FRAGMENT:()=>Ss,GUARD_REACTIVE_PROPS:()=>Is,IS_MEMO_SAME:()=>hl,IS_REF:()=>hp,KEEP_ALIVE:()=>gi,MERGE_PROPS:()=>Ei,NORMALIZE_CLASS:()=>co,NORMALIZE_PROPS:()=>ws,NORMALIZE_STYLE:()=>uo,Namespaces:()=>_E,NodeTypes:()=>vE,OPEN_BLOCK:()=>xn,POP_SCOPE_ID:()=>pp,PUSH_SCOPE_ID:()=>fp,RENDER_LIST:()=>lo,RENDER_SLOT:()=>pl,RESOLVE_COMPONENT:()=>so,RESOLVE_DIRECTIVE:()=>ro,RESOLVE_DYNAMIC_COMPONENT:()=>io,RESOLVE_FILTER:()=>oo,SET_BLOCK_TRACKING:()=>_i,SUSPENSE:()=>Zr,TELEPORT:()=>Os,TO_DISPLAY_STRING:()=>As,TO_HANDLERS:()=>fo,TO_HANDLER_KEY:()=>Jr,TRANSITION:()=>Ll,TRANSITION_GROUP:()=>Ml,TS_NODE_TYPES:()=>yp,UNREF:()=>dp,V_MODEL_CHECKBOX:()=>Al,V_MODEL_DYNAMIC:()=>yo,V_MODEL_RADIO:()=>Dl,V_MODEL_SELECT:()=>Vl,V_MODEL_TEXT:()=>Il,V_ON_WITH_KEYS:()=>Pl,V_ON_WITH_MODIFIERS:()=>Rl,V_SHOW:()=>kl,WITH_CTX:()=>po,WITH_DIRECTIVES:()=>ao,WITH_MEMO:()=>ho,advancePositionWithClone:()=>zE,advancePositionWithMutation:()=>Tp,assert:()=>qE,baseCompile:()=>Tl,baseParse:()=>go,buildDirectiveArgs:()=>td,buildProps:()=>wl,buildSlots:()=>zp,checkCompatEnabled:()=>ts,compile:()=>$l,convertToBlock:()=>mo,createArrayExpression:()=>Cn,createAssignmentExpression:()=>wE,createBlockStatement:()=>Ep,createCacheExpression:()=>gp,createCallExpression:()=>De,createCompilerError:()=>ve,createCompoundExpression:()=>ht,createConditionalExpression:()=>Yr,createDOMCompilerError:()=>Wt,createForLoopParams:()=>qr,createFunctionExpression:()=>Qn,createIfStatement:()=>SE,createInterpolation:()=>bE,createObjectExpression:()=>dt,createObjectProperty:()=>Oe,createReturnStatement:()=>CE,createRoot:()=>mp,createSequenceExpression:()=>TE,createSimpleExpression:()=>re,createStructuralDirectiveTransform:()=>bl,createTemplateLiteral:()=>OE,createTransformContext:()=>Fp,createVNodeCall:()=>Ts,errorMessages:()=>VE,extractIdentifiers:()=>fn,findDir:()=>ze,findProp:()=>Vs,forAliasRE:()=>Dp,generate:()=>Hp,generateCodeFrame:()=>Fs,getBaseTransformPreset:()=>sd,getConstantType:()=>nt,getMemoedVNodeCall:()=>xp,getVNodeBlockHelper:()=>es,getVNodeHelper:()=>Zn,hasDynamicKeyVBind:()=>vl,hasScopeRef:()=>xt,helperNameMap:()=>qn,injectProp:()=>yi,isCoreComponent:()=>El,isFnExpression:()=>wp,isFnExpressionBrowser:()=>Sp,isFnExpressionNode:()=>YE,isFunctionType:()=>HE,isInDestructureAssignment:()=>kE,isInNewExpression:()=>LE,isMemberExpression:()=>_l,isMemberExpressionBrowser:()=>Op,isMemberExpressionNode:()=>GE,isReferencedIdentifier:()=>PE,isSimpleIdentifier:()=>bi,isSlotOutlet:()=>vi,isStaticArgOf:()=>Kt,isStaticExp:()=>qe,isStaticProperty:()=>vp,isStaticPropertyKey:()=>BE,isTemplateNode:()=>Cs,isText:()=>Hr,isVSlot:()=>yl,locStub:()=>Pe,noopDirectiveTransform:()=>Cl,parse:()=>av,parserOptions:()=>Fl,processExpression:()=>Kr,processFor:()=>Jp,processIf:()=>Kp,processSlotOutlet:()=>nd,registerRuntimeHelpers:()=>ml,resolveComponentType:()=>ed,stringifyExpression:()=>Xp,toValidAssetId:()=>xs,trackSlotScopes:()=>Yp,trackVForSlotScopes:()=>k_,transform:()=>$p,transformBind:()=>Wp,transformElement:()=>Zp,transformExpression:()=>A_,transformModel:()=>vo,transformOn:()=>_o,transformStyle:()=>rd,traverseNode:()=>Oi,unwrapTSNode:()=>Np,walkBlockDeclarations:()=>FE,walkFunctionParams:()=>ME,walkIdentifiers:()=>RE,warnDeprecation:()=>AE});function G_(e,t=!1){return Rs||(Rs=document.createElement("div")),t?(Rs.innerHTML=`<div foo="${e.replace(/"/g,"&quot;")}">`,Rs.children[0].getAttribute("foo")):(Rs.innerHTML=e,Rs.textContent)}function Wt(e,t){return ve(e,t,void 0)}function $l(e,t={}){return Tl(e,se({},Fl,t,{nodeTransforms:[ov,...ad,...t.nodeTransforms||[]],directiveTransforms:se({},ld,t.directiveTransforms||{}),transformHoist:null}))}function av(e,t={}){return go(e,se({},Fl,t))}var Dl,Al,Il,Vl,yo,Rl,Pl,kl,Ll,Ml,Rs,Fl,rd,J_,Y_,z_,q_,Q_,Z_,ev,tv,nv,od,sv,id,iv,rv,ov,ad,ld,Ul=is(()=>{xl();xl();Pt();Dl=Symbol(""),Al=Symbol(""),Il=Symbol(""),Vl=Symbol(""),yo=Symbol(""),Rl=Symbol(""),Pl=Symbol(""),kl=Symbol(""),Ll=Symbol(""),Ml=Symbol("");ml({[Dl]:"vModelRadio",[Al]:"vModelCheckbox",[Il]:"vModelText",[Vl]:"vModelSelect",[yo]:"vModelDynamic",[Rl]:"withModifiers",[Pl]:"withKeys",[kl]:"vShow",[Ll]:"Transition",[Ml]:"TransitionGroup"});Fl={parseMode:"html",isVoidTag:Lo,isNativeTag:e=>Li(e)||Mi(e)||Fi(e),isPreTag:e=>e==="pre",isIgnoreNewlineTag:e=>e==="pre"||e==="textarea",decodeEntities:G_,isBuiltInComponent:e=>{if(e==="Transition"||e==="transition")return Ll;if(e==="TransitionGroup"||e==="transition-group")return Ml},getNamespace(e,t,n){let s=t?t.ns:n;if(t&&s===2)if(t.tag==="annotation-xml"){if(e==="svg")return 1;t.props.some(i=>i.type===6&&i.name==="encoding"&&i.value!=null&&(i.value.content==="text/html"||i.value.content==="application/xhtml+xml"))&&(s=0)}else/^m(?:[ions]|text)$/.test(t.tag)&&e!=="mglyph"&&e!=="malignmark"&&(s=0);else t&&s===1&&(t.tag==="foreignObject"||t.tag==="desc"||t.tag==="title")&&(s=0);if(s===0){if(e==="svg")return 1;if(e==="math")return 2}return s}},rd=e=>{e.type===1&&e.props.forEach((t,n)=>{t.type===6&&t.name==="style"&&t.value&&(e.props[n]={type:7,name:"bind",arg:re("style",!0,t.loc),exp:J_(t.value.content,t.loc),modifiers:[],loc:t.loc})})},J_=(e,t)=>{let n=ki(e);return re(JSON.stringify(n),!1,t,3)};Y_={X_V_HTML_NO_EXPRESSION:53,53:"X_V_HTML_NO_EXPRESSION",X_V_HTML_WITH_CHILDREN:54,54:"X_V_HTML_WITH_CHILDREN",X_V_TEXT_NO_EXPRESSION:55,55:"X_V_TEXT_NO_EXPRESSION",X_V_TEXT_WITH_CHILDREN:56,56:"X_V_TEXT_WITH_CHILDREN",X_V_MODEL_ON_INVALID_ELEMENT:57,57:"X_V_MODEL_ON_INVALID_ELEMENT",X_V_MODEL_ARG_ON_ELEMENT:58,58:"X_V_MODEL_ARG_ON_ELEMENT",X_V_MODEL_ON_FILE_INPUT_ELEMENT:59,59:"X_V_MODEL_ON_FILE_INPUT_ELEMENT",X_V_MODEL_UNNECESSARY_VALUE:60,60:"X_V_MODEL_UNNECESSARY_VALUE",X_V_SHOW_NO_EXPRESSION:61,61:"X_V_SHOW_NO_EXPRESSION",X_TRANSITION_INVALID_CHILDREN:62,62:"X_TRANSITION_INVALID_CHILDREN",X_IGNORED_SIDE_EFFECT_TAG:63,63:"X_IGNORED_SIDE_EFFECT_TAG",__EXTEND_POINT__:64,64:"__EXTEND_POINT__"},z_={53:"v-html is missing expression.",54:"v-html will override element children.",55:"v-text is missing expression.",56:"v-text will override element children.",57:"v-model can only be used on <input>, <textarea> and <select> elements.",58:"v-model argument is not supported on plain elements.",59:"v-model cannot be used on file inputs since they are read-only. Use a v-on:change listener instead.",60:"Unnecessary value binding used alongside v-model. It will interfere with v-model's behavior.",61:"v-show is missing expression.",62:"<Transition> expects exactly one child element or component.",63:"Tags with side effect (<script> and <style>) are ignored in client component templates."},q_=(e,t,n)=>{let{exp:s,loc:i}=e;return s||n.onError(Wt(53,i)),t.children.length&&(n.onError(Wt(54,i)),t.children.length=0),{props:[Oe(re("innerHTML",!0,i),s||re("",!0))]}},Q_=(e,t,n)=>{let{exp:s,loc:i}=e;return s||n.onError(Wt(55,i)),t.children.length&&(n.onError(Wt(56,i)),t.children.length=0),{props:[Oe(re("textContent",!0),s?nt(s,n)>0?s:De(n.helperString(As),[s],i):re("",!0))]}},Z_=(e,t,n)=>{let s=vo(e,t,n);if(!s.props.length||t.tagType===1)return s;e.arg&&n.onError(Wt(58,e.arg.loc));function i(){let a=ze(t,"bind");a&&Kt(a.arg,"value")&&n.onError(Wt(60,a.loc))}let{tag:r}=t,o=n.isCustomElement(r);if(r==="input"||r==="textarea"||r==="select"||o){let a=Il,l=!1;if(r==="input"||o){let c=Vs(t,"type");if(c){if(c.type===7)a=yo;else if(c.value)switch(c.value.content){case"radio":a=Dl;break;case"checkbox":a=Al;break;case"file":l=!0,n.onError(Wt(59,e.loc));break;default:break}}else vl(t)&&(a=yo)}else r==="select"&&(a=Vl);l||(s.needRuntime=n.helper(a))}else n.onError(Wt(57,e.loc));return s.props=s.props.filter(a=>!(a.key.type===4&&a.key.content==="modelValue")),s},ev=we("passive,once,capture"),tv=we("stop,prevent,self,ctrl,shift,alt,meta,exact,middle"),nv=we("left,right"),od=we("onkeyup,onkeydown,onkeypress"),sv=(e,t,n,s)=>{let i=[],r=[],o=[];for(let a=0;a<t.length;a++){let l=t[a].content;l==="native"&&ts("COMPILER_V_ON_NATIVE",n,s)||ev(l)?o.push(l):nv(l)?qe(e)?od(e.content.toLowerCase())?i.push(l):r.push(l):(i.push(l),r.push(l)):tv(l)?r.push(l):i.push(l)}return{keyModifiers:i,nonKeyModifiers:r,eventOptionModifiers:o}},id=(e,t)=>qe(e)&&e.content.toLowerCase()==="onclick"?re(t,!0):e.type!==4?ht(["(",e,`) === "onClick" ? "${t}" : (`,e,")"]):e,iv=(e,t,n)=>_o(e,t,n,s=>{let{modifiers:i}=e;if(!i.length)return s;let{key:r,value:o}=s.props[0],{keyModifiers:a,nonKeyModifiers:l,eventOptionModifiers:c}=sv(r,i,n,e.loc);if(l.includes("right")&&(r=id(r,"onContextmenu")),l.includes("middle")&&(r=id(r,"onMouseup")),l.length&&(o=De(n.helper(Rl),[o,JSON.stringify(l)])),a.length&&(!qe(r)||od(r.content.toLowerCase()))&&(o=De(n.helper(Pl),[o,JSON.stringify(a)])),c.length){let f=c.map(ct).join("");r=qe(r)?re(`${r.content}${f}`,!0):ht(["(",r,`) + "${f}"`])}return{props:[Oe(r,o)]}}),rv=(e,t,n)=>{let{exp:s,loc:i}=e;return s||n.onError(Wt(61,i)),{props:[],needRuntime:n.helper(kl)}},ov=(e,t)=>{e.type===1&&e.tagType===0&&(e.tag==="script"||e.tag==="style")&&t.removeNode()},ad=[rd],ld={cloak:Cl,html:q_,text:Q_,model:Z_,on:iv,show:rv}});var Ed=Ai(Ti=>{"use strict";Object.defineProperty(Ti,"__esModule",{value:!0});var wv=(Ul(),Co(cd)),No=(bs(),Co(Mr)),wi=(Pt(),Co(sc));function Tv(e){var t=Object.create(null);if(e)for(var n in e)t[n]=e[n];return t.default=e,Object.freeze(t)}var Cv=Tv(No),md=Object.create(null);function gd(e,t){if(!wi.isString(e))if(e.nodeType)e=e.innerHTML;else return wi.NOOP;let n=wi.genCacheKey(e,t),s=md[n];if(s)return s;if(e[0]==="#"){let a=document.querySelector(e);e=a?a.innerHTML:""}let i=wi.extend({hoistStatic:!0,onError:void 0,onWarn:wi.NOOP},t);!i.isCustomElement&&typeof customElements<"u"&&(i.isCustomElement=a=>!!customElements.get(a));let{code:r}=wv.compile(e,i),o=new Function("Vue",r)(Cv);return o._rc=!0,md[n]=o}No.registerRuntimeCompiler(gd);Ti.compile=gd;Object.keys(No).forEach(function(e){e!=="default"&&!Object.prototype.hasOwnProperty.call(Ti,e)&&(Ti[e]=No[e])})});var jl=Ai((py,_d)=>{"use strict";_d.exports=Ed()});var vd=Ai((Ci,Xl)=>{(function(t,n){typeof Ci=="object"&&typeof Xl=="object"?Xl.exports=n(jl()):typeof define=="function"&&define.amd?define("VueLoading",["vue"],n):typeof Ci=="object"?Ci.VueLoading=n(jl()):t.VueLoading=n(t.Vue)})(Ci,e=>(()=>{"use strict";var t={597:(L,Z)=>{Object.defineProperty(Z,"__esModule",{value:!0}),Z.default=(pe,Ae)=>{let mt=pe.__vccOpts||pe;for(let[Xe,Dt]of Ae)mt[Xe]=Dt;return mt}},594:L=>{L.exports=e}},n={};function s(L){var Z=n[L];if(Z!==void 0)return Z.exports;var pe=n[L]={exports:{}};return t[L](pe,pe.exports,s),pe.exports}s.d=(L,Z)=>{for(var pe in Z)s.o(Z,pe)&&!s.o(L,pe)&&Object.defineProperty(L,pe,{enumerable:!0,get:Z[pe]})},s.o=(L,Z)=>Object.prototype.hasOwnProperty.call(L,Z),s.r=L=>{typeof Symbol<"u"&&Symbol.toStringTag&&Object.defineProperty(L,Symbol.toStringTag,{value:"Module"}),Object.defineProperty(L,"__esModule",{value:!0})};var i={};s.r(i),s.d(i,{Component:()=>H,LoadingPlugin:()=>te,default:()=>X,useLoading:()=>j});var r=s(594);function o(L){typeof L.remove<"u"?L.remove():L.parentNode?.removeChild(L)}function a(L,Z,pe){let Ae=arguments.length>3&&arguments[3]!==void 0?arguments[3]:{},mt=(0,r.h)(L,Z,Ae),Xe=document.createElement("div");return Xe.classList.add("vld-container"),pe.appendChild(Xe),(0,r.render)(mt,Xe),mt.component}function l(){return typeof window<"u"}let c=l()?window.HTMLElement:Object,f=["aria-busy"],u={class:"vl-icon"};function p(L,Z,pe,Ae,mt,Xe){return(0,r.openBlock)(),(0,r.createBlock)(r.Transition,{name:L.transition},{default:(0,r.withCtx)(()=>[(0,r.withDirectives)((0,r.createElementVNode)("div",{tabindex:"0",class:(0,r.normalizeClass)(["vl-overlay vl-active",{"vl-full-page":L.isFullPage}]),"aria-busy":L.isActive,"aria-label":"Loading",style:(0,r.normalizeStyle)({zIndex:L.zIndex})},[(0,r.createElementVNode)("div",{class:"vl-background",onClick:Z[0]||(Z[0]=(0,r.withModifiers)(function(){return L.cancel&&L.cancel(...arguments)},["prevent"])),style:(0,r.normalizeStyle)(L.bgStyle)},null,4),(0,r.createElementVNode)("div",u,[(0,r.renderSlot)(L.$slots,"before"),(0,r.renderSlot)(L.$slots,"default",{},()=>[((0,r.openBlock)(),(0,r.createBlock)((0,r.resolveDynamicComponent)(L.loader),{color:L.color,width:L.width,height:L.height},null,8,["color","width","height"]))]),(0,r.renderSlot)(L.$slots,"after")])],14,f),[[r.vShow,L.isActive]])]),_:3},8,["name"])}let d={mounted(){this.enforceFocus&&document.addEventListener("focusin",this.focusIn)},methods:{focusIn(L){if(!this.isActive||L.target===this.$el||this.$el.contains(L.target))return;let Z=this.container?this.container:this.isFullPage?null:this.$el.parentElement;(this.isFullPage||Z&&Z.contains(L.target))&&(L.preventDefault(),this.$el.focus())}},beforeUnmount(){document.removeEventListener("focusin",this.focusIn)}},h=["width","height","stroke"];function E(L,Z,pe,Ae,mt,Xe){return(0,r.openBlock)(),(0,r.createElementBlock)("svg",{viewBox:"0 0 38 38",xmlns:"http://www.w3.org/2000/svg",width:L.width,height:L.height,stroke:L.color},Z[0]||(Z[0]=[(0,r.createStaticVNode)('<g fill="none" fill-rule="evenodd"><g transform="translate(1 1)" stroke-width="2"><circle stroke-opacity=".25" cx="18" cy="18" r="18"></circle><path d="M36 18c0-9.94-8.06-18-18-18"><animateTransform attributeName="transform" type="rotate" from="0 18 18" to="360 18 18" dur="0.8s" repeatCount="indefinite"></animateTransform></path></g></g>',1)]),8,h)}let v=(0,r.defineComponent)({name:"spinner",props:{color:{type:String,default:"#000"},height:{type:Number,default:64},width:{type:Number,default:64}}});var _=s(597);let m=(0,_.default)(v,[["render",E]]),w=["fill","width","height"];function S(L,Z,pe,Ae,mt,Xe){return(0,r.openBlock)(),(0,r.createElementBlock)("svg",{viewBox:"0 0 120 30",xmlns:"http://www.w3.org/2000/svg",fill:L.color,width:L.width,height:L.height},Z[0]||(Z[0]=[(0,r.createStaticVNode)('<circle cx="15" cy="15" r="15"><animate attributeName="r" from="15" to="15" begin="0s" dur="0.8s" values="15;9;15" calcMode="linear" repeatCount="indefinite"></animate><animate attributeName="fill-opacity" from="1" to="1" begin="0s" dur="0.8s" values="1;.5;1" calcMode="linear" repeatCount="indefinite"></animate></circle><circle cx="60" cy="15" r="9" fill-opacity="0.3"><animate attributeName="r" from="9" to="9" begin="0s" dur="0.8s" values="9;15;9" calcMode="linear" repeatCount="indefinite"></animate><animate attributeName="fill-opacity" from="0.5" to="0.5" begin="0s" dur="0.8s" values=".5;1;.5" calcMode="linear" repeatCount="indefinite"></animate></circle><circle cx="105" cy="15" r="15"><animate attributeName="r" from="15" to="15" begin="0s" dur="0.8s" values="15;9;15" calcMode="linear" repeatCount="indefinite"></animate><animate attributeName="fill-opacity" from="1" to="1" begin="0s" dur="0.8s" values="1;.5;1" calcMode="linear" repeatCount="indefinite"></animate></circle>',3)]),8,w)}let I=(0,r.defineComponent)({name:"dots",props:{color:{type:String,default:"#000"},height:{type:Number,default:240},width:{type:Number,default:60}}}),V=(0,_.default)(I,[["render",S]]),N=["height","width","fill"];function b(L,Z,pe,Ae,mt,Xe){return(0,r.openBlock)(),(0,r.createElementBlock)("svg",{xmlns:"http://www.w3.org/2000/svg",viewBox:"0 0 30 30",height:L.height,width:L.width,fill:L.color},Z[0]||(Z[0]=[(0,r.createStaticVNode)('<rect x="0" y="13" width="4" height="5"><animate attributeName="height" attributeType="XML" values="5;21;5" begin="0s" dur="0.6s" repeatCount="indefinite"></animate><animate attributeName="y" attributeType="XML" values="13; 5; 13" begin="0s" dur="0.6s" repeatCount="indefinite"></animate></rect><rect x="10" y="13" width="4" height="5"><animate attributeName="height" attributeType="XML" values="5;21;5" begin="0.15s" dur="0.6s" repeatCount="indefinite"></animate><animate attributeName="y" attributeType="XML" values="13; 5; 13" begin="0.15s" dur="0.6s" repeatCount="indefinite"></animate></rect><rect x="20" y="13" width="4" height="5"><animate attributeName="height" attributeType="XML" values="5;21;5" begin="0.3s" dur="0.6s" repeatCount="indefinite"></animate><animate attributeName="y" attributeType="XML" values="13; 5; 13" begin="0.3s" dur="0.6s" repeatCount="indefinite"></animate></rect>',3)]),8,N)}let D=(0,r.defineComponent)({name:"bars",props:{color:{type:String,default:"#000"},height:{type:Number,default:40},width:{type:Number,default:40}}}),C={Spinner:m,Dots:V,Bars:(0,_.default)(D,[["render",b]])},R=(0,r.defineComponent)({name:"VueLoading",mixins:[d],props:{active:Boolean,programmatic:Boolean,container:[Object,Function,c],isFullPage:{type:Boolean,default:!0},enforceFocus:{type:Boolean,default:!0},lockScroll:Boolean,transition:{type:String,default:"fade"},canCancel:Boolean,onCancel:{type:Function,default:()=>{}},color:String,backgroundColor:String,opacity:Number,width:Number,height:Number,zIndex:Number,loader:{type:String,default:"spinner"}},components:C,emits:["hide","update:active"],data(){return{isActive:this.active}},mounted(){document.addEventListener("keyup",this.keyPress)},methods:{cancel(){!this.canCancel||!this.isActive||(this.hide(),this.onCancel.apply(null,arguments))},hide(){this.$emit("hide"),this.$emit("update:active",!1),this.programmatic&&(this.isActive=!1,setTimeout(()=>{let L=this.$el.parentElement;(0,r.render)(null,L),o(L)},150))},disableScroll(){this.isFullPage&&this.lockScroll&&document.body.classList.add("vl-shown")},enableScroll(){this.isFullPage&&this.lockScroll&&document.body.classList.remove("vl-shown")},keyPress(L){L.keyCode===27&&this.cancel()}},watch:{active(L){this.isActive=L},isActive:{handler(L){L?this.disableScroll():this.enableScroll()},immediate:!0}},computed:{bgStyle(){return{background:this.backgroundColor,opacity:this.opacity}}},beforeUnmount(){document.removeEventListener("keyup",this.keyPress)}}),H=(0,_.default)(R,[["render",p]]);function j(){let L=arguments.length>0&&arguments[0]!==void 0?arguments[0]:{},Z=arguments.length>1&&arguments[1]!==void 0?arguments[1]:{};return{show(){let pe=arguments.length>0&&arguments[0]!==void 0?arguments[0]:L,Ae=arguments.length>1&&arguments[1]!==void 0?arguments[1]:Z,Xe={...L,...pe,...{programmatic:!0,lockScroll:!0,isFullPage:!1,active:!0}},Dt=Xe.container;Xe.container||(Dt=document.body,Xe.isFullPage=!0);let ns={...Z,...Ae};return{hide:a(H,Xe,Dt,ns).ctx.hide}}}}let te=function(L){let Z=arguments.length>1&&arguments[1]!==void 0?arguments[1]:{},pe=arguments.length>2&&arguments[2]!==void 0?arguments[2]:{},Ae=j(Z,pe);L.config.globalProperties.$loading=Ae,L.provide("$loading",Ae)},X=H;return i})())});var Nd=Ai((yd,Kl)=>{(function(e){if(typeof yd=="object"&&typeof Kl<"u")Kl.exports=e();else if(typeof define=="function"&&define.amd)define([],e);else{var t;typeof window<"u"?t=window:typeof global<"u"?t=global:typeof self<"u"?t=self:t=this,t.SockJS=e()}})(function(){var e,t,n;return function(){function s(i,r,o){function a(f,u){if(!r[f]){if(!i[f]){var p=typeof ks=="function"&&ks;if(!u&&p)return p(f,!0);if(l)return l(f,!0);var d=new Error("Cannot find module '"+f+"'");throw d.code="MODULE_NOT_FOUND",d}var h=r[f]={exports:{}};i[f][0].call(h.exports,function(E){var v=i[f][1][E];return a(v||E)},h,h.exports,s,i,r,o)}return r[f].exports}for(var l=typeof ks=="function"&&ks,c=0;c<o.length;c++)a(o[c]);return a}return s}()({1:[function(s,i,r){(function(o){(function(){"use strict";var a=s("./transport-list");i.exports=s("./main")(a),"_sockjs_onload"in o&&setTimeout(o._sockjs_onload,1)}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"./main":14,"./transport-list":16}],2:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./event");function l(){a.call(this),this.initEvent("close",!1,!1),this.wasClean=!1,this.code=0,this.reason=""}o(l,a),i.exports=l},{"./event":4,inherits:57}],3:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./eventtarget");function l(){a.call(this)}o(l,a),l.prototype.removeAllListeners=function(c){c?delete this._listeners[c]:this._listeners={}},l.prototype.once=function(c,f){var u=this,p=!1;function d(){u.removeListener(c,d),p||(p=!0,f.apply(this,arguments))}this.on(c,d)},l.prototype.emit=function(){var c=arguments[0],f=this._listeners[c];if(f){for(var u=arguments.length,p=new Array(u-1),d=1;d<u;d++)p[d-1]=arguments[d];for(var h=0;h<f.length;h++)f[h].apply(this,p)}},l.prototype.on=l.prototype.addListener=a.prototype.addEventListener,l.prototype.removeListener=a.prototype.removeEventListener,i.exports.EventEmitter=l},{"./eventtarget":5,inherits:57}],4:[function(s,i,r){"use strict";function o(a){this.type=a}o.prototype.initEvent=function(a,l,c){return this.type=a,this.bubbles=l,this.cancelable=c,this.timeStamp=+new Date,this},o.prototype.stopPropagation=function(){},o.prototype.preventDefault=function(){},o.CAPTURING_PHASE=1,o.AT_TARGET=2,o.BUBBLING_PHASE=3,i.exports=o},{}],5:[function(s,i,r){"use strict";function o(){this._listeners={}}o.prototype.addEventListener=function(a,l){a in this._listeners||(this._listeners[a]=[]);var c=this._listeners[a];c.indexOf(l)===-1&&(c=c.concat([l])),this._listeners[a]=c},o.prototype.removeEventListener=function(a,l){var c=this._listeners[a];if(c){var f=c.indexOf(l);if(f!==-1){c.length>1?this._listeners[a]=c.slice(0,f).concat(c.slice(f+1)):delete this._listeners[a];return}}},o.prototype.dispatchEvent=function(){var a=arguments[0],l=a.type,c=arguments.length===1?[a]:Array.apply(null,arguments);if(this["on"+l]&&this["on"+l].apply(this,c),l in this._listeners)for(var f=this._listeners[l],u=0;u<f.length;u++)f[u].apply(this,c)},i.exports=o},{}],6:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./event");function l(c){a.call(this),this.initEvent("message",!1,!1),this.data=c}o(l,a),i.exports=l},{"./event":4,inherits:57}],7:[function(s,i,r){"use strict";var o=s("./utils/iframe");function a(l){this._transport=l,l.on("message",this._transportMessage.bind(this)),l.on("close",this._transportClose.bind(this))}a.prototype._transportClose=function(l,c){o.postMessage("c",JSON.stringify([l,c]))},a.prototype._transportMessage=function(l){o.postMessage("t",l)},a.prototype._send=function(l){this._transport.send(l)},a.prototype._close=function(){this._transport.close(),this._transport.removeAllListeners()},i.exports=a},{"./utils/iframe":47}],8:[function(s,i,r){(function(o){(function(){"use strict";var a=s("./utils/url"),l=s("./utils/event"),c=s("./facade"),f=s("./info-iframe-receiver"),u=s("./utils/iframe"),p=s("./location"),d=function(){};o.env.NODE_ENV!=="production"&&(d=s("debug")("sockjs-client:iframe-bootstrap")),i.exports=function(h,E){var v={};E.forEach(function(g){g.facadeTransport&&(v[g.facadeTransport.transportName]=g.facadeTransport)}),v[f.transportName]=f;var _;h.bootstrap_iframe=function(){var g;u.currentWindowId=p.hash.slice(1);var m=function(w){if(w.source===parent&&(typeof _>"u"&&(_=w.origin),w.origin===_)){var S;try{S=JSON.parse(w.data)}catch{d("bad json",w.data);return}if(S.windowId===u.currentWindowId)switch(S.type){case"s":var I;try{I=JSON.parse(S.data)}catch{d("bad json",S.data);break}var k=I[0],V=I[1],N=I[2],b=I[3];if(d(k,V,N,b),k!==h.version)throw new Error('Incompatible SockJS! Main site uses: "'+k+'", the iframe: "'+h.version+'".');if(!a.isOriginEqual(N,p.href)||!a.isOriginEqual(b,p.href))throw new Error("Can't connect to different domain from within an iframe. ("+p.href+", "+N+", "+b+")");g=new c(new v[V](N,b));break;case"m":g._send(S.data);break;case"c":g&&g._close(),g=null;break}}};l.attachEvent("message",m),u.postMessage("s")}}}).call(this)}).call(this,{env:{}})},{"./facade":7,"./info-iframe-receiver":10,"./location":13,"./utils/event":46,"./utils/iframe":47,"./utils/url":52,debug:55}],9:[function(s,i,r){(function(o){(function(){"use strict";var a=s("events").EventEmitter,l=s("inherits"),c=s("./utils/object"),f=function(){};o.env.NODE_ENV!=="production"&&(f=s("debug")("sockjs-client:info-ajax"));function u(p,d){a.call(this);var h=this,E=+new Date;this.xo=new d("GET",p),this.xo.once("finish",function(v,_){var g,m;if(v===200){if(m=+new Date-E,_)try{g=JSON.parse(_)}catch{f("bad json",_)}c.isObject(g)||(g={})}h.emit("finish",g,m),h.removeAllListeners()})}l(u,a),u.prototype.close=function(){this.removeAllListeners(),this.xo.close()},i.exports=u}).call(this)}).call(this,{env:{}})},{"./utils/object":49,debug:55,events:3,inherits:57}],10:[function(s,i,r){"use strict";var o=s("inherits"),a=s("events").EventEmitter,l=s("./transport/sender/xhr-local"),c=s("./info-ajax");function f(u){var p=this;a.call(this),this.ir=new c(u,l),this.ir.once("finish",function(d,h){p.ir=null,p.emit("message",JSON.stringify([d,h]))})}o(f,a),f.transportName="iframe-info-receiver",f.prototype.close=function(){this.ir&&(this.ir.close(),this.ir=null),this.removeAllListeners()},i.exports=f},{"./info-ajax":9,"./transport/sender/xhr-local":37,events:3,inherits:57}],11:[function(s,i,r){(function(o,a){(function(){"use strict";var l=s("events").EventEmitter,c=s("inherits"),f=s("./utils/event"),u=s("./transport/iframe"),p=s("./info-iframe-receiver"),d=function(){};o.env.NODE_ENV!=="production"&&(d=s("debug")("sockjs-client:info-iframe"));function h(E,v){var _=this;l.call(this);var g=function(){var m=_.ifr=new u(p.transportName,v,E);m.once("message",function(w){if(w){var S;try{S=JSON.parse(w)}catch{d("bad json",w),_.emit("finish"),_.close();return}var I=S[0],k=S[1];_.emit("finish",I,k)}_.close()}),m.once("close",function(){_.emit("finish"),_.close()})};a.document.body?g():f.attachEvent("load",g)}c(h,l),h.enabled=function(){return u.enabled()},h.prototype.close=function(){this.ifr&&this.ifr.close(),this.removeAllListeners(),this.ifr=null},i.exports=h}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"./info-iframe-receiver":10,"./transport/iframe":22,"./utils/event":46,debug:55,events:3,inherits:57}],12:[function(s,i,r){(function(o){(function(){"use strict";var a=s("events").EventEmitter,l=s("inherits"),c=s("./utils/url"),f=s("./transport/sender/xdr"),u=s("./transport/sender/xhr-cors"),p=s("./transport/sender/xhr-local"),d=s("./transport/sender/xhr-fake"),h=s("./info-iframe"),E=s("./info-ajax"),v=function(){};o.env.NODE_ENV!=="production"&&(v=s("debug")("sockjs-client:info-receiver"));function _(g,m){v(g);var w=this;a.call(this),setTimeout(function(){w.doXhr(g,m)},0)}l(_,a),_._getReceiver=function(g,m,w){return w.sameOrigin?new E(m,p):u.enabled?new E(m,u):f.enabled&&w.sameScheme?new E(m,f):h.enabled()?new h(g,m):new E(m,d)},_.prototype.doXhr=function(g,m){var w=this,S=c.addPath(g,"/info");v("doXhr",S),this.xo=_._getReceiver(g,S,m),this.timeoutRef=setTimeout(function(){v("timeout"),w._cleanup(!1),w.emit("finish")},_.timeout),this.xo.once("finish",function(I,k){v("finish",I,k),w._cleanup(!0),w.emit("finish",I,k)})},_.prototype._cleanup=function(g){v("_cleanup"),clearTimeout(this.timeoutRef),this.timeoutRef=null,!g&&this.xo&&this.xo.close(),this.xo=null},_.prototype.close=function(){v("close"),this.removeAllListeners(),this._cleanup(!1)},_.timeout=8e3,i.exports=_}).call(this)}).call(this,{env:{}})},{"./info-ajax":9,"./info-iframe":11,"./transport/sender/xdr":34,"./transport/sender/xhr-cors":35,"./transport/sender/xhr-fake":36,"./transport/sender/xhr-local":37,"./utils/url":52,debug:55,events:3,inherits:57}],13:[function(s,i,r){(function(o){(function(){"use strict";i.exports=o.location||{origin:"http://localhost:80",protocol:"http:",host:"localhost",port:80,href:"http://localhost/",hash:""}}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{}],14:[function(s,i,r){(function(o,a){(function(){"use strict";s("./shims");var l=s("url-parse"),c=s("inherits"),f=s("./utils/random"),u=s("./utils/escape"),p=s("./utils/url"),d=s("./utils/event"),h=s("./utils/transport"),E=s("./utils/object"),v=s("./utils/browser"),_=s("./utils/log"),g=s("./event/event"),m=s("./event/eventtarget"),w=s("./location"),S=s("./event/close"),I=s("./event/trans-message"),k=s("./info-receiver"),V=function(){};o.env.NODE_ENV!=="production"&&(V=s("debug")("sockjs-client:main"));var N;function b(y,x,C){if(!(this instanceof b))return new b(y,x,C);if(arguments.length<1)throw new TypeError("Failed to construct 'SockJS: 1 argument required, but only 0 present");m.call(this),this.readyState=b.CONNECTING,this.extensions="",this.protocol="",C=C||{},C.protocols_whitelist&&_.warn("'protocols_whitelist' is DEPRECATED. Use 'transports' instead."),this._transportsWhitelist=C.transports,this._transportOptions=C.transportOptions||{},this._timeout=C.timeout||0;var R=C.sessionId||8;if(typeof R=="function")this._generateSessionId=R;else if(typeof R=="number")this._generateSessionId=function(){return f.string(R)};else throw new TypeError("If sessionId is used in the options, it needs to be a number or a function.");this._server=C.server||f.numberString(1e3);var A=new l(y);if(!A.host||!A.protocol)throw new SyntaxError("The URL '"+y+"' is invalid");if(A.hash)throw new SyntaxError("The URL must not contain a fragment");if(A.protocol!=="http:"&&A.protocol!=="https:")throw new SyntaxError("The URL's scheme must be either 'http:' or 'https:'. '"+A.protocol+"' is not allowed.");var H=A.protocol==="https:";if(w.protocol==="https:"&&!H&&!p.isLoopbackAddr(A.hostname))throw new Error("SecurityError: An insecure SockJS connection may not be initiated from a page loaded over HTTPS");x?Array.isArray(x)||(x=[x]):x=[];var j=x.sort();j.forEach(function(X,L){if(!X)throw new SyntaxError("The protocols entry '"+X+"' is invalid.");if(L<j.length-1&&X===j[L+1])throw new SyntaxError("The protocols entry '"+X+"' is duplicated.")});var te=p.getOrigin(w.href);this._origin=te?te.toLowerCase():null,A.set("pathname",A.pathname.replace(/\/+$/,"")),this.url=A.href,V("using url",this.url),this._urlInfo={nullOrigin:!v.hasDomain(),sameOrigin:p.isOriginEqual(this.url,w.href),sameScheme:p.isSchemeEqual(this.url,w.href)},this._ir=new k(this.url,this._urlInfo),this._ir.once("finish",this._receiveInfo.bind(this))}c(b,m);function D(y){return y===1e3||y>=3e3&&y<=4999}b.prototype.close=function(y,x){if(y&&!D(y))throw new Error("InvalidAccessError: Invalid code");if(x&&x.length>123)throw new SyntaxError("reason argument has an invalid length");if(!(this.readyState===b.CLOSING||this.readyState===b.CLOSED)){var C=!0;this._close(y||1e3,x||"Normal closure",C)}},b.prototype.send=function(y){if(typeof y!="string"&&(y=""+y),this.readyState===b.CONNECTING)throw new Error("InvalidStateError: The connection has not been established yet");this.readyState===b.OPEN&&this._transport.send(u.quote(y))},b.version=s("./version"),b.CONNECTING=0,b.OPEN=1,b.CLOSING=2,b.CLOSED=3,b.prototype._receiveInfo=function(y,x){if(V("_receiveInfo",x),this._ir=null,!y){this._close(1002,"Cannot connect to server");return}this._rto=this.countRTO(x),this._transUrl=y.base_url?y.base_url:this.url,y=E.extend(y,this._urlInfo),V("info",y);var C=N.filterToEnabled(this._transportsWhitelist,y);this._transports=C.main,V(this._transports.length+" enabled transports"),this._connect()},b.prototype._connect=function(){for(var y=this._transports.shift();y;y=this._transports.shift()){if(V("attempt",y.transportName),y.needBody&&(!a.document.body||typeof a.document.readyState<"u"&&a.document.readyState!=="complete"&&a.document.readyState!=="interactive")){V("waiting for body"),this._transports.unshift(y),d.attachEvent("load",this._connect.bind(this));return}var x=Math.max(this._timeout,this._rto*y.roundTrips||5e3);this._transportTimeoutId=setTimeout(this._transportTimeout.bind(this),x),V("using timeout",x);var C=p.addPath(this._transUrl,"/"+this._server+"/"+this._generateSessionId()),R=this._transportOptions[y.transportName];V("transport url",C);var A=new y(C,this._transUrl,R);A.on("message",this._transportMessage.bind(this)),A.once("close",this._transportClose.bind(this)),A.transportName=y.transportName,this._transport=A;return}this._close(2e3,"All transports failed",!1)},b.prototype._transportTimeout=function(){V("_transportTimeout"),this.readyState===b.CONNECTING&&(this._transport&&this._transport.close(),this._transportClose(2007,"Transport timed out"))},b.prototype._transportMessage=function(y){V("_transportMessage",y);var x=this,C=y.slice(0,1),R=y.slice(1),A;switch(C){case"o":this._open();return;case"h":this.dispatchEvent(new g("heartbeat")),V("heartbeat",this.transport);return}if(R)try{A=JSON.parse(R)}catch{V("bad json",R)}if(typeof A>"u"){V("empty payload",R);return}switch(C){case"a":Array.isArray(A)&&A.forEach(function(H){V("message",x.transport,H),x.dispatchEvent(new I(H))});break;case"m":V("message",this.transport,A),this.dispatchEvent(new I(A));break;case"c":Array.isArray(A)&&A.length===2&&this._close(A[0],A[1],!0);break}},b.prototype._transportClose=function(y,x){if(V("_transportClose",this.transport,y,x),this._transport&&(this._transport.removeAllListeners(),this._transport=null,this.transport=null),!D(y)&&y!==2e3&&this.readyState===b.CONNECTING){this._connect();return}this._close(y,x)},b.prototype._open=function(){V("_open",this._transport&&this._transport.transportName,this.readyState),this.readyState===b.CONNECTING?(this._transportTimeoutId&&(clearTimeout(this._transportTimeoutId),this._transportTimeoutId=null),this.readyState=b.OPEN,this.transport=this._transport.transportName,this.dispatchEvent(new g("open")),V("connected",this.transport)):this._close(1006,"Server lost session")},b.prototype._close=function(y,x,C){V("_close",this.transport,y,x,C,this.readyState);var R=!1;if(this._ir&&(R=!0,this._ir.close(),this._ir=null),this._transport&&(this._transport.close(),this._transport=null,this.transport=null),this.readyState===b.CLOSED)throw new Error("InvalidStateError: SockJS has already been closed");this.readyState=b.CLOSING,setTimeout(function(){this.readyState=b.CLOSED,R&&this.dispatchEvent(new g("error"));var A=new S("close");A.wasClean=C||!1,A.code=y||1e3,A.reason=x,this.dispatchEvent(A),this.onmessage=this.onclose=this.onerror=null,V("disconnected")}.bind(this),0)},b.prototype.countRTO=function(y){return y>100?4*y:300+y},i.exports=function(y){return N=h(y),s("./iframe-bootstrap")(b,y),b}}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"./event/close":2,"./event/event":4,"./event/eventtarget":5,"./event/trans-message":6,"./iframe-bootstrap":8,"./info-receiver":12,"./location":13,"./shims":15,"./utils/browser":44,"./utils/escape":45,"./utils/event":46,"./utils/log":48,"./utils/object":49,"./utils/random":50,"./utils/transport":51,"./utils/url":52,"./version":53,debug:55,inherits:57,"url-parse":60}],15:[function(s,i,r){"use strict";var o=Array.prototype,a=Object.prototype,l=Function.prototype,c=String.prototype,f=o.slice,u=a.toString,p=function(x){return a.toString.call(x)==="[object Function]"},d=function(C){return u.call(C)==="[object Array]"},h=function(C){return u.call(C)==="[object String]"},E=Object.defineProperty&&function(){try{return Object.defineProperty({},"x",{}),!0}catch{return!1}}(),v;E?v=function(x,C,R,A){!A&&C in x||Object.defineProperty(x,C,{configurable:!0,enumerable:!1,writable:!0,value:R})}:v=function(x,C,R,A){!A&&C in x||(x[C]=R)};var _=function(x,C,R){for(var A in C)a.hasOwnProperty.call(C,A)&&v(x,A,C[A],R)},g=function(x){if(x==null)throw new TypeError("can't convert "+x+" to object");return Object(x)};function m(x){var C=+x;return C!==C?C=0:C!==0&&C!==1/0&&C!==-1/0&&(C=(C>0||-1)*Math.floor(Math.abs(C))),C}function w(x){return x>>>0}function S(){}_(l,{bind:function(C){var R=this;if(!p(R))throw new TypeError("Function.prototype.bind called on incompatible "+R);for(var A=f.call(arguments,1),H=function(){if(this instanceof L){var Z=R.apply(this,A.concat(f.call(arguments)));return Object(Z)===Z?Z:this}else return R.apply(C,A.concat(f.call(arguments)))},j=Math.max(0,R.length-A.length),te=[],X=0;X<j;X++)te.push("$"+X);var L=Function("binder","return function ("+te.join(",")+"){ return binder.apply(this, arguments); }")(H);return R.prototype&&(S.prototype=R.prototype,L.prototype=new S,S.prototype=null),L}}),_(Array,{isArray:d});var I=Object("a"),k=I[0]!=="a"||!(0 in I),V=function(C){var R=!0,A=!0;return C&&(C.call("foo",function(H,j,te){typeof te!="object"&&(R=!1)}),C.call([1],function(){"use strict";A=typeof this=="string"},"x")),!!C&&R&&A};_(o,{forEach:function(C){var R=g(this),A=k&&h(this)?this.split(""):R,H=arguments[1],j=-1,te=A.length>>>0;if(!p(C))throw new TypeError;for(;++j<te;)j in A&&C.call(H,A[j],j,R)}},!V(o.forEach));var N=Array.prototype.indexOf&&[0,1].indexOf(1,2)!==-1;_(o,{indexOf:function(C){var R=k&&h(this)?this.split(""):g(this),A=R.length>>>0;if(!A)return-1;var H=0;for(arguments.length>1&&(H=m(arguments[1])),H=H>=0?H:Math.max(0,A+H);H<A;H++)if(H in R&&R[H]===C)return H;return-1}},N);var b=c.split;"ab".split(/(?:ab)*/).length!==2||".".split(/(.?)(.?)/).length!==4||"tesst".split(/(s)*/)[1]==="t"||"test".split(/(?:)/,-1).length!==4||"".split(/.?/).length||".".split(/()()/).length>1?function(){var x=/()??/.exec("")[1]===void 0;c.split=function(C,R){var A=this;if(C===void 0&&R===0)return[];if(u.call(C)!=="[object RegExp]")return b.call(this,C,R);var H=[],j=(C.ignoreCase?"i":"")+(C.multiline?"m":"")+(C.extended?"x":"")+(C.sticky?"y":""),te=0,X,L,Z,pe;for(C=new RegExp(C.source,j+"g"),A+="",x||(X=new RegExp("^"+C.source+"$(?!\\s)",j)),R=R===void 0?-1>>>0:w(R);(L=C.exec(A))&&(Z=L.index+L[0].length,!(Z>te&&(H.push(A.slice(te,L.index)),!x&&L.length>1&&L[0].replace(X,function(){for(var Ae=1;Ae<arguments.length-2;Ae++)arguments[Ae]===void 0&&(L[Ae]=void 0)}),L.length>1&&L.index<A.length&&o.push.apply(H,L.slice(1)),pe=L[0].length,te=Z,H.length>=R)));)C.lastIndex===L.index&&C.lastIndex++;return te===A.length?(pe||!C.test(""))&&H.push(""):H.push(A.slice(te)),H.length>R?H.slice(0,R):H}}():"0".split(void 0,0).length&&(c.split=function(C,R){return C===void 0&&R===0?[]:b.call(this,C,R)});var D=c.substr,y="".substr&&"0b".substr(-1)!=="b";_(c,{substr:function(C,R){return D.call(this,C<0&&(C=this.length+C)<0?0:C,R)}},y)},{}],16:[function(s,i,r){"use strict";i.exports=[s("./transport/websocket"),s("./transport/xhr-streaming"),s("./transport/xdr-streaming"),s("./transport/eventsource"),s("./transport/lib/iframe-wrap")(s("./transport/eventsource")),s("./transport/htmlfile"),s("./transport/lib/iframe-wrap")(s("./transport/htmlfile")),s("./transport/xhr-polling"),s("./transport/xdr-polling"),s("./transport/lib/iframe-wrap")(s("./transport/xhr-polling")),s("./transport/jsonp-polling")]},{"./transport/eventsource":20,"./transport/htmlfile":21,"./transport/jsonp-polling":23,"./transport/lib/iframe-wrap":26,"./transport/websocket":38,"./transport/xdr-polling":39,"./transport/xdr-streaming":40,"./transport/xhr-polling":41,"./transport/xhr-streaming":42}],17:[function(s,i,r){(function(o,a){(function(){"use strict";var l=s("events").EventEmitter,c=s("inherits"),f=s("../../utils/event"),u=s("../../utils/url"),p=a.XMLHttpRequest,d=function(){};o.env.NODE_ENV!=="production"&&(d=s("debug")("sockjs-client:browser:xhr"));function h(_,g,m,w){d(_,g);var S=this;l.call(this),setTimeout(function(){S._start(_,g,m,w)},0)}c(h,l),h.prototype._start=function(_,g,m,w){var S=this;try{this.xhr=new p}catch{}if(!this.xhr){d("no xhr"),this.emit("finish",0,"no xhr support"),this._cleanup();return}g=u.addQuery(g,"t="+ +new Date),this.unloadRef=f.unloadAdd(function(){d("unload cleanup"),S._cleanup(!0)});try{this.xhr.open(_,g,!0),this.timeout&&"timeout"in this.xhr&&(this.xhr.timeout=this.timeout,this.xhr.ontimeout=function(){d("xhr timeout"),S.emit("finish",0,""),S._cleanup(!1)})}catch(k){d("exception",k),this.emit("finish",0,""),this._cleanup(!1);return}if((!w||!w.noCredentials)&&h.supportsCORS&&(d("withCredentials"),this.xhr.withCredentials=!0),w&&w.headers)for(var I in w.headers)this.xhr.setRequestHeader(I,w.headers[I]);this.xhr.onreadystatechange=function(){if(S.xhr){var k=S.xhr,V,N;switch(d("readyState",k.readyState),k.readyState){case 3:try{N=k.status,V=k.responseText}catch{}d("status",N),N===1223&&(N=204),N===200&&V&&V.length>0&&(d("chunk"),S.emit("chunk",N,V));break;case 4:N=k.status,d("status",N),N===1223&&(N=204),(N===12005||N===12029)&&(N=0),d("finish",N,k.responseText),S.emit("finish",N,k.responseText),S._cleanup(!1);break}}};try{S.xhr.send(m)}catch{S.emit("finish",0,""),S._cleanup(!1)}},h.prototype._cleanup=function(_){if(d("cleanup"),!!this.xhr){if(this.removeAllListeners(),f.unloadDel(this.unloadRef),this.xhr.onreadystatechange=function(){},this.xhr.ontimeout&&(this.xhr.ontimeout=null),_)try{this.xhr.abort()}catch{}this.unloadRef=this.xhr=null}},h.prototype.close=function(){d("close"),this._cleanup(!0)},h.enabled=!!p;var E=["Active"].concat("Object").join("X");!h.enabled&&E in a&&(d("overriding xmlhttprequest"),p=function(){try{return new a[E]("Microsoft.XMLHTTP")}catch{return null}},h.enabled=!!new p);var v=!1;try{v="withCredentials"in new p}catch{}h.supportsCORS=v,i.exports=h}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"../../utils/event":46,"../../utils/url":52,debug:55,events:3,inherits:57}],18:[function(s,i,r){(function(o){(function(){i.exports=o.EventSource}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{}],19:[function(s,i,r){(function(o){(function(){"use strict";var a=o.WebSocket||o.MozWebSocket;a?i.exports=function(c){return new a(c)}:i.exports=void 0}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{}],20:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./lib/ajax-based"),l=s("./receiver/eventsource"),c=s("./sender/xhr-cors"),f=s("eventsource");function u(p){if(!u.enabled())throw new Error("Transport created when disabled");a.call(this,p,"/eventsource",l,c)}o(u,a),u.enabled=function(){return!!f},u.transportName="eventsource",u.roundTrips=2,i.exports=u},{"./lib/ajax-based":24,"./receiver/eventsource":29,"./sender/xhr-cors":35,eventsource:18,inherits:57}],21:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./receiver/htmlfile"),l=s("./sender/xhr-local"),c=s("./lib/ajax-based");function f(u){if(!a.enabled)throw new Error("Transport created when disabled");c.call(this,u,"/htmlfile",a,l)}o(f,c),f.enabled=function(u){return a.enabled&&u.sameOrigin},f.transportName="htmlfile",f.roundTrips=2,i.exports=f},{"./lib/ajax-based":24,"./receiver/htmlfile":30,"./sender/xhr-local":37,inherits:57}],22:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("events").EventEmitter,c=s("../version"),f=s("../utils/url"),u=s("../utils/iframe"),p=s("../utils/event"),d=s("../utils/random"),h=function(){};o.env.NODE_ENV!=="production"&&(h=s("debug")("sockjs-client:transport:iframe"));function E(v,_,g){if(!E.enabled())throw new Error("Transport created when disabled");l.call(this);var m=this;this.origin=f.getOrigin(g),this.baseUrl=g,this.transUrl=_,this.transport=v,this.windowId=d.string(8);var w=f.addPath(g,"/iframe.html")+"#"+this.windowId;h(v,_,w),this.iframeObj=u.createIframe(w,function(S){h("err callback"),m.emit("close",1006,"Unable to load an iframe ("+S+")"),m.close()}),this.onmessageCallback=this._message.bind(this),p.attachEvent("message",this.onmessageCallback)}a(E,l),E.prototype.close=function(){if(h("close"),this.removeAllListeners(),this.iframeObj){p.detachEvent("message",this.onmessageCallback);try{this.postMessage("c")}catch{}this.iframeObj.cleanup(),this.iframeObj=null,this.onmessageCallback=this.iframeObj=null}},E.prototype._message=function(v){if(h("message",v.data),!f.isOriginEqual(v.origin,this.origin)){h("not same origin",v.origin,this.origin);return}var _;try{_=JSON.parse(v.data)}catch{h("bad json",v.data);return}if(_.windowId!==this.windowId){h("mismatched window id",_.windowId,this.windowId);return}switch(_.type){case"s":this.iframeObj.loaded(),this.postMessage("s",JSON.stringify([c,this.transport,this.transUrl,this.baseUrl]));break;case"t":this.emit("message",_.data);break;case"c":var g;try{g=JSON.parse(_.data)}catch{h("bad json",_.data);return}this.emit("close",g[0],g[1]),this.close();break}},E.prototype.postMessage=function(v,_){h("postMessage",v,_),this.iframeObj.post(JSON.stringify({windowId:this.windowId,type:v,data:_||""}),this.origin)},E.prototype.send=function(v){h("send",v),this.postMessage("m",v)},E.enabled=function(){return u.iframeEnabled},E.transportName="iframe",E.roundTrips=2,i.exports=E}).call(this)}).call(this,{env:{}})},{"../utils/event":46,"../utils/iframe":47,"../utils/random":50,"../utils/url":52,"../version":53,debug:55,events:3,inherits:57}],23:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("./lib/sender-receiver"),c=s("./receiver/jsonp"),f=s("./sender/jsonp");function u(p){if(!u.enabled())throw new Error("Transport created when disabled");l.call(this,p,"/jsonp",f,c)}a(u,l),u.enabled=function(){return!!o.document},u.transportName="jsonp-polling",u.roundTrips=1,u.needBody=!0,i.exports=u}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"./lib/sender-receiver":28,"./receiver/jsonp":31,"./sender/jsonp":33,inherits:57}],24:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("../../utils/url"),c=s("./sender-receiver"),f=function(){};o.env.NODE_ENV!=="production"&&(f=s("debug")("sockjs-client:ajax-based"));function u(d){return function(h,E,v){f("create ajax sender",h,E);var _={};typeof E=="string"&&(_.headers={"Content-type":"text/plain"});var g=l.addPath(h,"/xhr_send"),m=new d("POST",g,E,_);return m.once("finish",function(w){if(f("finish",w),m=null,w!==200&&w!==204)return v(new Error("http status "+w));v()}),function(){f("abort"),m.close(),m=null;var w=new Error("Aborted");w.code=1e3,v(w)}}}function p(d,h,E,v){c.call(this,d,h,u(v),E,v)}a(p,c),i.exports=p}).call(this)}).call(this,{env:{}})},{"../../utils/url":52,"./sender-receiver":28,debug:55,inherits:57}],25:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("events").EventEmitter,c=function(){};o.env.NODE_ENV!=="production"&&(c=s("debug")("sockjs-client:buffered-sender"));function f(u,p){c(u),l.call(this),this.sendBuffer=[],this.sender=p,this.url=u}a(f,l),f.prototype.send=function(u){c("send",u),this.sendBuffer.push(u),this.sendStop||this.sendSchedule()},f.prototype.sendScheduleWait=function(){c("sendScheduleWait");var u=this,p;this.sendStop=function(){c("sendStop"),u.sendStop=null,clearTimeout(p)},p=setTimeout(function(){c("timeout"),u.sendStop=null,u.sendSchedule()},25)},f.prototype.sendSchedule=function(){c("sendSchedule",this.sendBuffer.length);var u=this;if(this.sendBuffer.length>0){var p="["+this.sendBuffer.join(",")+"]";this.sendStop=this.sender(this.url,p,function(d){u.sendStop=null,d?(c("error",d),u.emit("close",d.code||1006,"Sending error: "+d),u.close()):u.sendScheduleWait()}),this.sendBuffer=[]}},f.prototype._cleanup=function(){c("_cleanup"),this.removeAllListeners()},f.prototype.close=function(){c("close"),this._cleanup(),this.sendStop&&(this.sendStop(),this.sendStop=null)},i.exports=f}).call(this)}).call(this,{env:{}})},{debug:55,events:3,inherits:57}],26:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("../iframe"),c=s("../../utils/object");i.exports=function(f){function u(p,d){l.call(this,f.transportName,p,d)}return a(u,l),u.enabled=function(p,d){if(!o.document)return!1;var h=c.extend({},d);return h.sameOrigin=!0,f.enabled(h)&&l.enabled()},u.transportName="iframe-"+f.transportName,u.needBody=!0,u.roundTrips=l.roundTrips+f.roundTrips-1,u.facadeTransport=f,u}}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"../../utils/object":49,"../iframe":22,inherits:57}],27:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("events").EventEmitter,c=function(){};o.env.NODE_ENV!=="production"&&(c=s("debug")("sockjs-client:polling"));function f(u,p,d){c(p),l.call(this),this.Receiver=u,this.receiveUrl=p,this.AjaxObject=d,this._scheduleReceiver()}a(f,l),f.prototype._scheduleReceiver=function(){c("_scheduleReceiver");var u=this,p=this.poll=new this.Receiver(this.receiveUrl,this.AjaxObject);p.on("message",function(d){c("message",d),u.emit("message",d)}),p.once("close",function(d,h){c("close",d,h,u.pollIsClosing),u.poll=p=null,u.pollIsClosing||(h==="network"?u._scheduleReceiver():(u.emit("close",d||1006,h),u.removeAllListeners()))})},f.prototype.abort=function(){c("abort"),this.removeAllListeners(),this.pollIsClosing=!0,this.poll&&this.poll.abort()},i.exports=f}).call(this)}).call(this,{env:{}})},{debug:55,events:3,inherits:57}],28:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("../../utils/url"),c=s("./buffered-sender"),f=s("./polling"),u=function(){};o.env.NODE_ENV!=="production"&&(u=s("debug")("sockjs-client:sender-receiver"));function p(d,h,E,v,_){var g=l.addPath(d,h);u(g);var m=this;c.call(this,d,E),this.poll=new f(v,g,_),this.poll.on("message",function(w){u("poll message",w),m.emit("message",w)}),this.poll.once("close",function(w,S){u("poll close",w,S),m.poll=null,m.emit("close",w,S),m.close()})}a(p,c),p.prototype.close=function(){c.prototype.close.call(this),u("close"),this.removeAllListeners(),this.poll&&(this.poll.abort(),this.poll=null)},i.exports=p}).call(this)}).call(this,{env:{}})},{"../../utils/url":52,"./buffered-sender":25,"./polling":27,debug:55,inherits:57}],29:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("events").EventEmitter,c=s("eventsource"),f=function(){};o.env.NODE_ENV!=="production"&&(f=s("debug")("sockjs-client:receiver:eventsource"));function u(p){f(p),l.call(this);var d=this,h=this.es=new c(p);h.onmessage=function(E){f("message",E.data),d.emit("message",decodeURI(E.data))},h.onerror=function(E){f("error",h.readyState,E);var v=h.readyState!==2?"network":"permanent";d._cleanup(),d._close(v)}}a(u,l),u.prototype.abort=function(){f("abort"),this._cleanup(),this._close("user")},u.prototype._cleanup=function(){f("cleanup");var p=this.es;p&&(p.onmessage=p.onerror=null,p.close(),this.es=null)},u.prototype._close=function(p){f("close",p);var d=this;setTimeout(function(){d.emit("close",null,p),d.removeAllListeners()},200)},i.exports=u}).call(this)}).call(this,{env:{}})},{debug:55,events:3,eventsource:18,inherits:57}],30:[function(s,i,r){(function(o,a){(function(){"use strict";var l=s("inherits"),c=s("../../utils/iframe"),f=s("../../utils/url"),u=s("events").EventEmitter,p=s("../../utils/random"),d=function(){};o.env.NODE_ENV!=="production"&&(d=s("debug")("sockjs-client:receiver:htmlfile"));function h(v){d(v),u.call(this);var _=this;c.polluteGlobalNamespace(),this.id="a"+p.string(6),v=f.addQuery(v,"c="+decodeURIComponent(c.WPrefix+"."+this.id)),d("using htmlfile",h.htmlfileEnabled);var g=h.htmlfileEnabled?c.createHtmlfile:c.createIframe;a[c.WPrefix][this.id]={start:function(){d("start"),_.iframeObj.loaded()},message:function(m){d("message",m),_.emit("message",m)},stop:function(){d("stop"),_._cleanup(),_._close("network")}},this.iframeObj=g(v,function(){d("callback"),_._cleanup(),_._close("permanent")})}l(h,u),h.prototype.abort=function(){d("abort"),this._cleanup(),this._close("user")},h.prototype._cleanup=function(){d("_cleanup"),this.iframeObj&&(this.iframeObj.cleanup(),this.iframeObj=null),delete a[c.WPrefix][this.id]},h.prototype._close=function(v){d("_close",v),this.emit("close",null,v),this.removeAllListeners()},h.htmlfileEnabled=!1;var E=["Active"].concat("Object").join("X");if(E in a)try{h.htmlfileEnabled=!!new a[E]("htmlfile")}catch{}h.enabled=h.htmlfileEnabled||c.iframeEnabled,i.exports=h}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"../../utils/iframe":47,"../../utils/random":50,"../../utils/url":52,debug:55,events:3,inherits:57}],31:[function(s,i,r){(function(o,a){(function(){"use strict";var l=s("../../utils/iframe"),c=s("../../utils/random"),f=s("../../utils/browser"),u=s("../../utils/url"),p=s("inherits"),d=s("events").EventEmitter,h=function(){};o.env.NODE_ENV!=="production"&&(h=s("debug")("sockjs-client:receiver:jsonp"));function E(v){h(v);var _=this;d.call(this),l.polluteGlobalNamespace(),this.id="a"+c.string(6);var g=u.addQuery(v,"c="+encodeURIComponent(l.WPrefix+"."+this.id));a[l.WPrefix][this.id]=this._callback.bind(this),this._createScript(g),this.timeoutId=setTimeout(function(){h("timeout"),_._abort(new Error("JSONP script loaded abnormally (timeout)"))},E.timeout)}p(E,d),E.prototype.abort=function(){if(h("abort"),a[l.WPrefix][this.id]){var v=new Error("JSONP user aborted read");v.code=1e3,this._abort(v)}},E.timeout=35e3,E.scriptErrorTimeout=1e3,E.prototype._callback=function(v){h("_callback",v),this._cleanup(),!this.aborting&&(v&&(h("message",v),this.emit("message",v)),this.emit("close",null,"network"),this.removeAllListeners())},E.prototype._abort=function(v){h("_abort",v),this._cleanup(),this.aborting=!0,this.emit("close",v.code,v.message),this.removeAllListeners()},E.prototype._cleanup=function(){if(h("_cleanup"),clearTimeout(this.timeoutId),this.script2&&(this.script2.parentNode.removeChild(this.script2),this.script2=null),this.script){var v=this.script;v.parentNode.removeChild(v),v.onreadystatechange=v.onerror=v.onload=v.onclick=null,this.script=null}delete a[l.WPrefix][this.id]},E.prototype._scriptError=function(){h("_scriptError");var v=this;this.errorTimer||(this.errorTimer=setTimeout(function(){v.loadedOkay||v._abort(new Error("JSONP script loaded abnormally (onerror)"))},E.scriptErrorTimeout))},E.prototype._createScript=function(v){h("_createScript",v);var _=this,g=this.script=a.document.createElement("script"),m;if(g.id="a"+c.string(8),g.src=v,g.type="text/javascript",g.charset="UTF-8",g.onerror=this._scriptError.bind(this),g.onload=function(){h("onload"),_._abort(new Error("JSONP script loaded abnormally (onload)"))},g.onreadystatechange=function(){if(h("onreadystatechange",g.readyState),/loaded|closed/.test(g.readyState)){if(g&&g.htmlFor&&g.onclick){_.loadedOkay=!0;try{g.onclick()}catch{}}g&&_._abort(new Error("JSONP script loaded abnormally (onreadystatechange)"))}},typeof g.async>"u"&&a.document.attachEvent)if(f.isOpera())m=this.script2=a.document.createElement("script"),m.text="try{var a = document.getElementById('"+g.id+"'); if(a)a.onerror();}catch(x){};",g.async=m.async=!1;else{try{g.htmlFor=g.id,g.event="onclick"}catch{}g.async=!0}typeof g.async<"u"&&(g.async=!0);var w=a.document.getElementsByTagName("head")[0];w.insertBefore(g,w.firstChild),m&&w.insertBefore(m,w.firstChild)},i.exports=E}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"../../utils/browser":44,"../../utils/iframe":47,"../../utils/random":50,"../../utils/url":52,debug:55,events:3,inherits:57}],32:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("events").EventEmitter,c=function(){};o.env.NODE_ENV!=="production"&&(c=s("debug")("sockjs-client:receiver:xhr"));function f(u,p){c(u),l.call(this);var d=this;this.bufferPosition=0,this.xo=new p("POST",u,null),this.xo.on("chunk",this._chunkHandler.bind(this)),this.xo.once("finish",function(h,E){c("finish",h,E),d._chunkHandler(h,E),d.xo=null;var v=h===200?"network":"permanent";c("close",v),d.emit("close",null,v),d._cleanup()})}a(f,l),f.prototype._chunkHandler=function(u,p){if(c("_chunkHandler",u),!(u!==200||!p))for(var d=-1;;this.bufferPosition+=d+1){var h=p.slice(this.bufferPosition);if(d=h.indexOf(`
 `),d===-1)break;var E=h.slice(0,d);E&&(c("message",E),this.emit("message",E))}},f.prototype._cleanup=function(){c("_cleanup"),this.removeAllListeners()},f.prototype.abort=function(){c("abort"),this.xo&&(this.xo.close(),c("close"),this.emit("close",null,"user"),this.xo=null),this._cleanup()},i.exports=f}).call(this)}).call(this,{env:{}})},{debug:55,events:3,inherits:57}],33:[function(s,i,r){(function(o,a){(function(){"use strict";var l=s("../../utils/random"),c=s("../../utils/url"),f=function(){};o.env.NODE_ENV!=="production"&&(f=s("debug")("sockjs-client:sender:jsonp"));var u,p;function d(E){f("createIframe",E);try{return a.document.createElement('<iframe name="'+E+'">')}catch{var v=a.document.createElement("iframe");return v.name=E,v}}function h(){f("createForm"),u=a.document.createElement("form"),u.style.display="none",u.style.position="absolute",u.method="POST",u.enctype="application/x-www-form-urlencoded",u.acceptCharset="UTF-8",p=a.document.createElement("textarea"),p.name="d",u.appendChild(p),a.document.body.appendChild(u)}i.exports=function(E,v,_){f(E,v),u||h();var g="a"+l.string(8);u.target=g,u.action=c.addQuery(c.addPath(E,"/jsonp_send"),"i="+g);var m=d(g);m.id=g,m.style.display="none",u.appendChild(m);try{p.value=v}catch{}u.submit();var w=function(S){f("completed",g,S),m.onerror&&(m.onreadystatechange=m.onerror=m.onload=null,setTimeout(function(){f("cleaning up",g),m.parentNode.removeChild(m),m=null},500),p.value="",_(S))};return m.onerror=function(){f("onerror",g),w()},m.onload=function(){f("onload",g),w()},m.onreadystatechange=function(S){f("onreadystatechange",g,m.readyState,S),m.readyState==="complete"&&w()},function(){f("aborted",g),w(new Error("Aborted"))}}}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"../../utils/random":50,"../../utils/url":52,debug:55}],34:[function(s,i,r){(function(o,a){(function(){"use strict";var l=s("events").EventEmitter,c=s("inherits"),f=s("../../utils/event"),u=s("../../utils/browser"),p=s("../../utils/url"),d=function(){};o.env.NODE_ENV!=="production"&&(d=s("debug")("sockjs-client:sender:xdr"));function h(E,v,_){d(E,v);var g=this;l.call(this),setTimeout(function(){g._start(E,v,_)},0)}c(h,l),h.prototype._start=function(E,v,_){d("_start");var g=this,m=new a.XDomainRequest;v=p.addQuery(v,"t="+ +new Date),m.onerror=function(){d("onerror"),g._error()},m.ontimeout=function(){d("ontimeout"),g._error()},m.onprogress=function(){d("progress",m.responseText),g.emit("chunk",200,m.responseText)},m.onload=function(){d("load"),g.emit("finish",200,m.responseText),g._cleanup(!1)},this.xdr=m,this.unloadRef=f.unloadAdd(function(){g._cleanup(!0)});try{this.xdr.open(E,v),this.timeout&&(this.xdr.timeout=this.timeout),this.xdr.send(_)}catch{this._error()}},h.prototype._error=function(){this.emit("finish",0,""),this._cleanup(!1)},h.prototype._cleanup=function(E){if(d("cleanup",E),!!this.xdr){if(this.removeAllListeners(),f.unloadDel(this.unloadRef),this.xdr.ontimeout=this.xdr.onerror=this.xdr.onprogress=this.xdr.onload=null,E)try{this.xdr.abort()}catch{}this.unloadRef=this.xdr=null}},h.prototype.close=function(){d("close"),this._cleanup(!0)},h.enabled=!!(a.XDomainRequest&&u.hasDomain()),i.exports=h}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"../../utils/browser":44,"../../utils/event":46,"../../utils/url":52,debug:55,events:3,inherits:57}],35:[function(s,i,r){"use strict";var o=s("inherits"),a=s("../driver/xhr");function l(c,f,u,p){a.call(this,c,f,u,p)}o(l,a),l.enabled=a.enabled&&a.supportsCORS,i.exports=l},{"../driver/xhr":17,inherits:57}],36:[function(s,i,r){"use strict";var o=s("events").EventEmitter,a=s("inherits");function l(){var c=this;o.call(this),this.to=setTimeout(function(){c.emit("finish",200,"{}")},l.timeout)}a(l,o),l.prototype.close=function(){clearTimeout(this.to)},l.timeout=2e3,i.exports=l},{events:3,inherits:57}],37:[function(s,i,r){"use strict";var o=s("inherits"),a=s("../driver/xhr");function l(c,f,u){a.call(this,c,f,u,{noCredentials:!0})}o(l,a),l.enabled=a.enabled,i.exports=l},{"../driver/xhr":17,inherits:57}],38:[function(s,i,r){(function(o){(function(){"use strict";var a=s("../utils/event"),l=s("../utils/url"),c=s("inherits"),f=s("events").EventEmitter,u=s("./driver/websocket"),p=function(){};o.env.NODE_ENV!=="production"&&(p=s("debug")("sockjs-client:websocket"));function d(h,E,v){if(!d.enabled())throw new Error("Transport created when disabled");f.call(this),p("constructor",h);var _=this,g=l.addPath(h,"/websocket");g.slice(0,5)==="https"?g="wss"+g.slice(5):g="ws"+g.slice(4),this.url=g,this.ws=new u(this.url,[],v),this.ws.onmessage=function(m){p("message event",m.data),_.emit("message",m.data)},this.unloadRef=a.unloadAdd(function(){p("unload"),_.ws.close()}),this.ws.onclose=function(m){p("close event",m.code,m.reason),_.emit("close",m.code,m.reason),_._cleanup()},this.ws.onerror=function(m){p("error event",m),_.emit("close",1006,"WebSocket connection broken"),_._cleanup()}}c(d,f),d.prototype.send=function(h){var E="["+h+"]";p("send",E),this.ws.send(E)},d.prototype.close=function(){p("close");var h=this.ws;this._cleanup(),h&&h.close()},d.prototype._cleanup=function(){p("_cleanup");var h=this.ws;h&&(h.onmessage=h.onclose=h.onerror=null),a.unloadDel(this.unloadRef),this.unloadRef=this.ws=null,this.removeAllListeners()},d.enabled=function(){return p("enabled"),!!u},d.transportName="websocket",d.roundTrips=2,i.exports=d}).call(this)}).call(this,{env:{}})},{"../utils/event":46,"../utils/url":52,"./driver/websocket":19,debug:55,events:3,inherits:57}],39:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./lib/ajax-based"),l=s("./xdr-streaming"),c=s("./receiver/xhr"),f=s("./sender/xdr");function u(p){if(!f.enabled)throw new Error("Transport created when disabled");a.call(this,p,"/xhr",c,f)}o(u,a),u.enabled=l.enabled,u.transportName="xdr-polling",u.roundTrips=2,i.exports=u},{"./lib/ajax-based":24,"./receiver/xhr":32,"./sender/xdr":34,"./xdr-streaming":40,inherits:57}],40:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./lib/ajax-based"),l=s("./receiver/xhr"),c=s("./sender/xdr");function f(u){if(!c.enabled)throw new Error("Transport created when disabled");a.call(this,u,"/xhr_streaming",l,c)}o(f,a),f.enabled=function(u){return u.cookie_needed||u.nullOrigin?!1:c.enabled&&u.sameScheme},f.transportName="xdr-streaming",f.roundTrips=2,i.exports=f},{"./lib/ajax-based":24,"./receiver/xhr":32,"./sender/xdr":34,inherits:57}],41:[function(s,i,r){"use strict";var o=s("inherits"),a=s("./lib/ajax-based"),l=s("./receiver/xhr"),c=s("./sender/xhr-cors"),f=s("./sender/xhr-local");function u(p){if(!f.enabled&&!c.enabled)throw new Error("Transport created when disabled");a.call(this,p,"/xhr",l,c)}o(u,a),u.enabled=function(p){return p.nullOrigin?!1:f.enabled&&p.sameOrigin?!0:c.enabled},u.transportName="xhr-polling",u.roundTrips=2,i.exports=u},{"./lib/ajax-based":24,"./receiver/xhr":32,"./sender/xhr-cors":35,"./sender/xhr-local":37,inherits:57}],42:[function(s,i,r){(function(o){(function(){"use strict";var a=s("inherits"),l=s("./lib/ajax-based"),c=s("./receiver/xhr"),f=s("./sender/xhr-cors"),u=s("./sender/xhr-local"),p=s("../utils/browser");function d(h){if(!u.enabled&&!f.enabled)throw new Error("Transport created when disabled");l.call(this,h,"/xhr_streaming",c,f)}a(d,l),d.enabled=function(h){return h.nullOrigin||p.isOpera()?!1:f.enabled},d.transportName="xhr-streaming",d.roundTrips=2,d.needBody=!!o.document,i.exports=d}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"../utils/browser":44,"./lib/ajax-based":24,"./receiver/xhr":32,"./sender/xhr-cors":35,"./sender/xhr-local":37,inherits:57}],43:[function(s,i,r){(function(o){(function(){"use strict";o.crypto&&o.crypto.getRandomValues?i.exports.randomBytes=function(a){var l=new Uint8Array(a);return o.crypto.getRandomValues(l),l}:i.exports.randomBytes=function(a){for(var l=new Array(a),c=0;c<a;c++)l[c]=Math.floor(Math.random()*256);return l}}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{}],44:[function(s,i,r){(function(o){(function(){"use strict";i.exports={isOpera:function(){return o.navigator&&/opera/i.test(o.navigator.userAgent)},isKonqueror:function(){return o.navigator&&/konqueror/i.test(o.navigator.userAgent)},hasDomain:function(){if(!o.document)return!0;try{return!!o.document.domain}catch{return!1}}}}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{}],45:[function(s,i,r){"use strict";var o=/[\x00-\x1f\ud800-\udfff\ufffe\uffff\u0300-\u0333\u033d-\u0346\u034a-\u034c\u0350-\u0352\u0357-\u0358\u035c-\u0362\u0374\u037e\u0387\u0591-\u05af\u05c4\u0610-\u0617\u0653-\u0654\u0657-\u065b\u065d-\u065e\u06df-\u06e2\u06eb-\u06ec\u0730\u0732-\u0733\u0735-\u0736\u073a\u073d\u073f-\u0741\u0743\u0745\u0747\u07eb-\u07f1\u0951\u0958-\u095f\u09dc-\u09dd\u09df\u0a33\u0a36\u0a59-\u0a5b\u0a5e\u0b5c-\u0b5d\u0e38-\u0e39\u0f43\u0f4d\u0f52\u0f57\u0f5c\u0f69\u0f72-\u0f76\u0f78\u0f80-\u0f83\u0f93\u0f9d\u0fa2\u0fa7\u0fac\u0fb9\u1939-\u193a\u1a17\u1b6b\u1cda-\u1cdb\u1dc0-\u1dcf\u1dfc\u1dfe\u1f71\u1f73\u1f75\u1f77\u1f79\u1f7b\u1f7d\u1fbb\u1fbe\u1fc9\u1fcb\u1fd3\u1fdb\u1fe3\u1feb\u1fee-\u1fef\u1ff9\u1ffb\u1ffd\u2000-\u2001\u20d0-\u20d1\u20d4-\u20d7\u20e7-\u20e9\u2126\u212a-\u212b\u2329-\u232a\u2adc\u302b-\u302c\uaab2-\uaab3\uf900-\ufa0d\ufa10\ufa12\ufa15-\ufa1e\ufa20\ufa22\ufa25-\ufa26\ufa2a-\ufa2d\ufa30-\ufa6d\ufa70-\ufad9\ufb1d\ufb1f\ufb2a-\ufb36\ufb38-\ufb3c\ufb3e\ufb40-\ufb41\ufb43-\ufb44\ufb46-\ufb4e\ufff0-\uffff]/g,a,l=function(c){var f,u={},p=[];for(f=0;f<65536;f++)p.push(String.fromCharCode(f));return c.lastIndex=0,p.join("").replace(c,function(d){return u[d]="\\u"+("0000"+d.charCodeAt(0).toString(16)).slice(-4),""}),c.lastIndex=0,u};i.exports={quote:function(c){var f=JSON.stringify(c);return o.lastIndex=0,o.test(f)?(a||(a=l(o)),f.replace(o,function(u){return a[u]})):f}}},{}],46:[function(s,i,r){(function(o){(function(){"use strict";var a=s("./random"),l={},c=!1,f=o.chrome&&o.chrome.app&&o.chrome.app.runtime;i.exports={attachEvent:function(p,d){typeof o.addEventListener<"u"?o.addEventListener(p,d,!1):o.document&&o.attachEvent&&(o.document.attachEvent("on"+p,d),o.attachEvent("on"+p,d))},detachEvent:function(p,d){typeof o.addEventListener<"u"?o.removeEventListener(p,d,!1):o.document&&o.detachEvent&&(o.document.detachEvent("on"+p,d),o.detachEvent("on"+p,d))},unloadAdd:function(p){if(f)return null;var d=a.string(8);return l[d]=p,c&&setTimeout(this.triggerUnloadCallbacks,0),d},unloadDel:function(p){p in l&&delete l[p]},triggerUnloadCallbacks:function(){for(var p in l)l[p](),delete l[p]}};var u=function(){c||(c=!0,i.exports.triggerUnloadCallbacks())};f||i.exports.attachEvent("unload",u)}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"./random":50}],47:[function(s,i,r){(function(o,a){(function(){"use strict";var l=s("./event"),c=s("./browser"),f=function(){};o.env.NODE_ENV!=="production"&&(f=s("debug")("sockjs-client:utils:iframe")),i.exports={WPrefix:"_jp",currentWindowId:null,polluteGlobalNamespace:function(){i.exports.WPrefix in a||(a[i.exports.WPrefix]={})},postMessage:function(u,p){a.parent!==a?a.parent.postMessage(JSON.stringify({windowId:i.exports.currentWindowId,type:u,data:p||""}),"*"):f("Cannot postMessage, no parent window.",u,p)},createIframe:function(u,p){var d=a.document.createElement("iframe"),h,E,v=function(){f("unattach"),clearTimeout(h);try{d.onload=null}catch{}d.onerror=null},_=function(){f("cleanup"),d&&(v(),setTimeout(function(){d&&d.parentNode.removeChild(d),d=null},0),l.unloadDel(E))},g=function(w){f("onerror",w),d&&(_(),p(w))},m=function(w,S){f("post",w,S),setTimeout(function(){try{d&&d.contentWindow&&d.contentWindow.postMessage(w,S)}catch{}},0)};return d.src=u,d.style.display="none",d.style.position="absolute",d.onerror=function(){g("onerror")},d.onload=function(){f("onload"),clearTimeout(h),h=setTimeout(function(){g("onload timeout")},2e3)},a.document.body.appendChild(d),h=setTimeout(function(){g("timeout")},15e3),E=l.unloadAdd(_),{post:m,cleanup:_,loaded:v}},createHtmlfile:function(u,p){var d=["Active"].concat("Object").join("X"),h=new a[d]("htmlfile"),E,v,_,g=function(){clearTimeout(E),_.onerror=null},m=function(){h&&(g(),l.unloadDel(v),_.parentNode.removeChild(_),_=h=null,CollectGarbage())},w=function(k){f("onerror",k),h&&(m(),p(k))},S=function(k,V){try{setTimeout(function(){_&&_.contentWindow&&_.contentWindow.postMessage(k,V)},0)}catch{}};h.open(),h.write('<html><script>document.domain="'+a.document.domain+'";<\/script></html>'),h.close(),h.parentWindow[i.exports.WPrefix]=a[i.exports.WPrefix];var I=h.createElement("div");return h.body.appendChild(I),_=h.createElement("iframe"),I.appendChild(_),_.src=u,_.onerror=function(){w("onerror")},E=setTimeout(function(){w("timeout")},15e3),v=l.unloadAdd(m),{post:S,cleanup:m,loaded:g}}},i.exports.iframeEnabled=!1,a.document&&(i.exports.iframeEnabled=(typeof a.postMessage=="function"||typeof a.postMessage=="object")&&!c.isKonqueror())}).call(this)}).call(this,{env:{}},typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{"./browser":44,"./event":46,debug:55}],48:[function(s,i,r){(function(o){(function(){"use strict";var a={};["log","debug","warn"].forEach(function(l){var c;try{c=o.console&&o.console[l]&&o.console[l].apply}catch{}a[l]=c?function(){return o.console[l].apply(o.console,arguments)}:l==="log"?function(){}:a.log}),i.exports=a}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{}],49:[function(s,i,r){"use strict";i.exports={isObject:function(o){var a=typeof o;return a==="function"||a==="object"&&!!o},extend:function(o){if(!this.isObject(o))return o;for(var a,l,c=1,f=arguments.length;c<f;c++){a=arguments[c];for(l in a)Object.prototype.hasOwnProperty.call(a,l)&&(o[l]=a[l])}return o}}},{}],50:[function(s,i,r){"use strict";var o=s("crypto"),a="abcdefghijklmnopqrstuvwxyz012345";i.exports={string:function(l){for(var c=a.length,f=o.randomBytes(l),u=[],p=0;p<l;p++)u.push(a.substr(f[p]%c,1));return u.join("")},number:function(l){return Math.floor(Math.random()*l)},numberString:function(l){var c=(""+(l-1)).length,f=new Array(c+1).join("0");return(f+this.number(l)).slice(-c)}}},{crypto:43}],51:[function(s,i,r){(function(o){(function(){"use strict";var a=function(){};o.env.NODE_ENV!=="production"&&(a=s("debug")("sockjs-client:utils:transport")),i.exports=function(l){return{filterToEnabled:function(c,f){var u={main:[],facade:[]};return c?typeof c=="string"&&(c=[c]):c=[],l.forEach(function(p){if(p){if(p.transportName==="websocket"&&f.websocket===!1){a("disabled from server","websocket");return}if(c.length&&c.indexOf(p.transportName)===-1){a("not in whitelist",p.transportName);return}p.enabled(f)?(a("enabled",p.transportName),u.main.push(p),p.facadeTransport&&u.facade.push(p.facadeTransport)):a("disabled",p.transportName)}}),u}}}}).call(this)}).call(this,{env:{}})},{debug:55}],52:[function(s,i,r){(function(o){(function(){"use strict";var a=s("url-parse"),l=function(){};o.env.NODE_ENV!=="production"&&(l=s("debug")("sockjs-client:utils:url")),i.exports={getOrigin:function(c){if(!c)return null;var f=new a(c);if(f.protocol==="file:")return null;var u=f.port;return u||(u=f.protocol==="https:"?"443":"80"),f.protocol+"//"+f.hostname+":"+u},isOriginEqual:function(c,f){var u=this.getOrigin(c)===this.getOrigin(f);return l("same",c,f,u),u},isSchemeEqual:function(c,f){return c.split(":")[0]===f.split(":")[0]},addPath:function(c,f){var u=c.split("?");return u[0]+f+(u[1]?"?"+u[1]:"")},addQuery:function(c,f){return c+(c.indexOf("?")===-1?"?"+f:"&"+f)},isLoopbackAddr:function(c){return/^127\.([0-9]{1,3})\.([0-9]{1,3})\.([0-9]{1,3})$/i.test(c)||/^\[::1\]$/.test(c)}}}).call(this)}).call(this,{env:{}})},{debug:55,"url-parse":60}],53:[function(s,i,r){i.exports="1.6.1"},{}],54:[function(s,i,r){var o=1e3,a=o*60,l=a*60,c=l*24,f=c*7,u=c*365.25;i.exports=function(v,_){_=_||{};var g=typeof v;if(g==="string"&&v.length>0)return p(v);if(g==="number"&&isFinite(v))return _.long?h(v):d(v);throw new Error("val is not a non-empty string or a valid number. val="+JSON.stringify(v))};function p(v){if(v=String(v),!(v.length>100)){var _=/^(-?(?:\d+)?\.?\d+) *(milliseconds?|msecs?|ms|seconds?|secs?|s|minutes?|mins?|m|hours?|hrs?|h|days?|d|weeks?|w|years?|yrs?|y)?$/i.exec(v);if(_){var g=parseFloat(_[1]),m=(_[2]||"ms").toLowerCase();switch(m){case"years":case"year":case"yrs":case"yr":case"y":return g*u;case"weeks":case"week":case"w":return g*f;case"days":case"day":case"d":return g*c;case"hours":case"hour":case"hrs":case"hr":case"h":return g*l;case"minutes":case"minute":case"mins":case"min":case"m":return g*a;case"seconds":case"second":case"secs":case"sec":case"s":return g*o;case"milliseconds":case"millisecond":case"msecs":case"msec":case"ms":return g;default:return}}}}function d(v){var _=Math.abs(v);return _>=c?Math.round(v/c)+"d":_>=l?Math.round(v/l)+"h":_>=a?Math.round(v/a)+"m":_>=o?Math.round(v/o)+"s":v+"ms"}function h(v){var _=Math.abs(v);return _>=c?E(v,_,c,"day"):_>=l?E(v,_,l,"hour"):_>=a?E(v,_,a,"minute"):_>=o?E(v,_,o,"second"):v+" ms"}function E(v,_,g,m){var w=_>=g*1.5;return Math.round(v/g)+" "+m+(w?"s":"")}},{}],55:[function(s,i,r){(function(o){(function(){r.formatArgs=l,r.save=c,r.load=f,r.useColors=a,r.storage=u(),r.destroy=(()=>{let d=!1;return()=>{d||(d=!0,console.warn("Instance method `debug.destroy()` is deprecated and no longer does anything. It will be removed in the next major version of `debug`."))}})(),r.colors=["#0000CC","#0000FF","#0033CC","#0033FF","#0066CC","#0066FF","#0099CC","#0099FF","#00CC00","#00CC33","#00CC66","#00CC99","#00CCCC","#00CCFF","#3300CC","#3300FF","#3333CC","#3333FF","#3366CC","#3366FF","#3399CC","#3399FF","#33CC00","#33CC33","#33CC66","#33CC99","#33CCCC","#33CCFF","#6600CC","#6600FF","#6633CC","#6633FF","#66CC00","#66CC33","#9900CC","#9900FF","#9933CC","#9933FF","#99CC00","#99CC33","#CC0000","#CC0033","#CC0066","#CC0099","#CC00CC","#CC00FF","#CC3300","#CC3333","#CC3366","#CC3399","#CC33CC","#CC33FF","#CC6600","#CC6633","#CC9900","#CC9933","#CCCC00","#CCCC33","#FF0000","#FF0033","#FF0066","#FF0099","#FF00CC","#FF00FF","#FF3300","#FF3333","#FF3366","#FF3399","#FF33CC","#FF33FF","#FF6600","#FF6633","#FF9900","#FF9933","#FFCC00","#FFCC33"];function a(){return typeof window<"u"&&window.process&&(window.process.type==="renderer"||window.process.__nwjs)?!0:typeof navigator<"u"&&navigator.userAgent&&navigator.userAgent.toLowerCase().match(/(edge|trident)\/(\d+)/)?!1:typeof document<"u"&&document.documentElement&&document.documentElement.style&&document.documentElement.style.WebkitAppearance||typeof window<"u"&&window.console&&(window.console.firebug||window.console.exception&&window.console.table)||typeof navigator<"u"&&navigator.userAgent&&navigator.userAgent.toLowerCase().match(/firefox\/(\d+)/)&&parseInt(RegExp.$1,10)>=31||typeof navigator<"u"&&navigator.userAgent&&navigator.userAgent.toLowerCase().match(/applewebkit\/(\d+)/)}function l(d){if(d[0]=(this.useColors?"%c":"")+this.namespace+(this.useColors?" %c":" ")+d[0]+(this.useColors?"%c ":" ")+"+"+i.exports.humanize(this.diff),!this.useColors)return;let h="color: "+this.color;d.splice(1,0,h,"color: inherit");let E=0,v=0;d[0].replace(/%[a-zA-Z%]/g,_=>{_!=="%%"&&(E++,_==="%c"&&(v=E))}),d.splice(v,0,h)}r.log=console.debug||console.log||(()=>{});function c(d){try{d?r.storage.setItem("debug",d):r.storage.removeItem("debug")}catch{}}function f(){let d;try{d=r.storage.getItem("debug")}catch{}return!d&&typeof o<"u"&&"env"in o&&(d=o.env.DEBUG),d}function u(){try{return localStorage}catch{}}i.exports=s("./common")(r);let{formatters:p}=i.exports;p.j=function(d){try{return JSON.stringify(d)}catch(h){return"[UnexpectedJSONParseError]: "+h.message}}}).call(this)}).call(this,{env:{}})},{"./common":56}],56:[function(s,i,r){function o(a){c.debug=c,c.default=c,c.coerce=E,c.disable=p,c.enable=u,c.enabled=d,c.humanize=s("ms"),c.destroy=v,Object.keys(a).forEach(_=>{c[_]=a[_]}),c.names=[],c.skips=[],c.formatters={};function l(_){let g=0;for(let m=0;m<_.length;m++)g=(g<<5)-g+_.charCodeAt(m),g|=0;return c.colors[Math.abs(g)%c.colors.length]}c.selectColor=l;function c(_){let g,m=null,w,S;function I(...k){if(!I.enabled)return;let V=I,N=Number(new Date),b=N-(g||N);V.diff=b,V.prev=g,V.curr=N,g=N,k[0]=c.coerce(k[0]),typeof k[0]!="string"&&k.unshift("%O");let D=0;k[0]=k[0].replace(/%([a-zA-Z%])/g,(x,C)=>{if(x==="%%")return"%";D++;let R=c.formatters[C];if(typeof R=="function"){let A=k[D];x=R.call(V,A),k.splice(D,1),D--}return x}),c.formatArgs.call(V,k),(V.log||c.log).apply(V,k)}return I.namespace=_,I.useColors=c.useColors(),I.color=c.selectColor(_),I.extend=f,I.destroy=c.destroy,Object.defineProperty(I,"enabled",{enumerable:!0,configurable:!1,get:()=>m!==null?m:(w!==c.namespaces&&(w=c.namespaces,S=c.enabled(_)),S),set:k=>{m=k}}),typeof c.init=="function"&&c.init(I),I}function f(_,g){let m=c(this.namespace+(typeof g>"u"?":":g)+_);return m.log=this.log,m}function u(_){c.save(_),c.namespaces=_,c.names=[],c.skips=[];let g,m=(typeof _=="string"?_:"").split(/[\s,]+/),w=m.length;for(g=0;g<w;g++)m[g]&&(_=m[g].replace(/\*/g,".*?"),_[0]==="-"?c.skips.push(new RegExp("^"+_.substr(1)+"$")):c.names.push(new RegExp("^"+_+"$")))}function p(){let _=[...c.names.map(h),...c.skips.map(h).map(g=>"-"+g)].join(",");return c.enable(""),_}function d(_){if(_[_.length-1]==="*")return!0;let g,m;for(g=0,m=c.skips.length;g<m;g++)if(c.skips[g].test(_))return!1;for(g=0,m=c.names.length;g<m;g++)if(c.names[g].test(_))return!0;return!1}function h(_){return _.toString().substring(2,_.toString().length-2).replace(/\.\*\?$/,"*")}function E(_){return _ instanceof Error?_.stack||_.message:_}function v(){console.warn("Instance method `debug.destroy()` is deprecated and no longer does anything. It will be removed in the next major version of `debug`.")}return c.enable(c.load()),c}i.exports=o},{ms:54}],57:[function(s,i,r){typeof Object.create=="function"?i.exports=function(a,l){l&&(a.super_=l,a.prototype=Object.create(l.prototype,{constructor:{value:a,enumerable:!1,writable:!0,configurable:!0}}))}:i.exports=function(a,l){if(l){a.super_=l;var c=function(){};c.prototype=l.prototype,a.prototype=new c,a.prototype.constructor=a}}},{}],58:[function(s,i,r){"use strict";var o=Object.prototype.hasOwnProperty,a;function l(p){try{return decodeURIComponent(p.replace(/\+/g," "))}catch{return null}}function c(p){try{return encodeURIComponent(p)}catch{return null}}function f(p){for(var d=/([^=?&]+)=?([^&]*)/g,h={},E;E=d.exec(p);){var v=l(E[1]),_=l(E[2]);v===null||_===null||v in h||(h[v]=_)}return h}function u(p,d){d=d||"";var h=[],E,v;typeof d!="string"&&(d="?");for(v in p)if(o.call(p,v)){if(E=p[v],!E&&(E===null||E===a||isNaN(E))&&(E=""),v=encodeURIComponent(v),E=encodeURIComponent(E),v===null||E===null)continue;h.push(v+"="+E)}return h.length?d+h.join("&"):""}r.stringify=u,r.parse=f},{}],59:[function(s,i,r){"use strict";i.exports=function(a,l){if(l=l.split(":")[0],a=+a,!a)return!1;switch(l){case"http":case"ws":return a!==80;case"https":case"wss":return a!==443;case"ftp":return a!==21;case"gopher":return a!==70;case"file":return!1}return a!==0}},{}],60:[function(s,i,r){(function(o){(function(){"use strict";var a=s("requires-port"),l=s("querystringify"),c=/^[\x00-\x20\u00a0\u1680\u2000-\u200a\u2028\u2029\u202f\u205f\u3000\ufeff]+/,f=/[\n\r\t]/g,u=/^[A-Za-z][A-Za-z0-9+-.]*:\/\//,p=/:\d+$/,d=/^([a-z][a-z0-9.+-]*:)?(\/\/)?([\\/]+)?([\S\s]*)/i,h=/^[a-zA-Z]:/;function E(N){return(N||"").toString().replace(c,"")}var v=[["#","hash"],["?","query"],function(b,D){return m(D.protocol)?b.replace(/\\/g,"/"):b},["/","pathname"],["@","auth",1],[NaN,"host",void 0,1,1],[/:(\d*)$/,"port",void 0,1],[NaN,"hostname",void 0,1,1]],_={hash:1,query:1};function g(N){var b;typeof window<"u"?b=window:typeof o<"u"?b=o:typeof self<"u"?b=self:b={};var D=b.location||{};N=N||D;var y={},x=typeof N,C;if(N.protocol==="blob:")y=new I(unescape(N.pathname),{});else if(x==="string"){y=new I(N,{});for(C in _)delete y[C]}else if(x==="object"){for(C in N)C in _||(y[C]=N[C]);y.slashes===void 0&&(y.slashes=u.test(N.href))}return y}function m(N){return N==="file:"||N==="ftp:"||N==="http:"||N==="https:"||N==="ws:"||N==="wss:"}function w(N,b){N=E(N),N=N.replace(f,""),b=b||{};var D=d.exec(N),y=D[1]?D[1].toLowerCase():"",x=!!D[2],C=!!D[3],R=0,A;return x?C?(A=D[2]+D[3]+D[4],R=D[2].length+D[3].length):(A=D[2]+D[4],R=D[2].length):C?(A=D[3]+D[4],R=D[3].length):A=D[4],y==="file:"?R>=2&&(A=A.slice(2)):m(y)?A=D[4]:y?x&&(A=A.slice(2)):R>=2&&m(b.protocol)&&(A=D[4]),{protocol:y,slashes:x||m(y),slashesCount:R,rest:A}}function S(N,b){if(N==="")return b;for(var D=(b||"/").split("/").slice(0,-1).concat(N.split("/")),y=D.length,x=D[y-1],C=!1,R=0;y--;)D[y]==="."?D.splice(y,1):D[y]===".."?(D.splice(y,1),R++):R&&(y===0&&(C=!0),D.splice(y,1),R--);return C&&D.unshift(""),(x==="."||x==="..")&&D.push(""),D.join("/")}function I(N,b,D){if(N=E(N),N=N.replace(f,""),!(this instanceof I))return new I(N,b,D);var y,x,C,R,A,H,j=v.slice(),te=typeof b,X=this,L=0;for(te!=="object"&&te!=="string"&&(D=b,b=null),D&&typeof D!="function"&&(D=l.parse),b=g(b),x=w(N||"",b),y=!x.protocol&&!x.slashes,X.slashes=x.slashes||y&&b.slashes,X.protocol=x.protocol||b.protocol||"",N=x.rest,(x.protocol==="file:"&&(x.slashesCount!==2||h.test(N))||!x.slashes&&(x.protocol||x.slashesCount<2||!m(X.protocol)))&&(j[3]=[/(.*)/,"pathname"]);L<j.length;L++){if(R=j[L],typeof R=="function"){N=R(N,X);continue}C=R[0],H=R[1],C!==C?X[H]=N:typeof C=="string"?(A=C==="@"?N.lastIndexOf(C):N.indexOf(C),~A&&(typeof R[2]=="number"?(X[H]=N.slice(0,A),N=N.slice(A+R[2])):(X[H]=N.slice(A),N=N.slice(0,A)))):(A=C.exec(N))&&(X[H]=A[1],N=N.slice(0,A.index)),X[H]=X[H]||y&&R[3]&&b[H]||"",R[4]&&(X[H]=X[H].toLowerCase())}D&&(X.query=D(X.query)),y&&b.slashes&&X.pathname.charAt(0)!=="/"&&(X.pathname!==""||b.pathname!=="")&&(X.pathname=S(X.pathname,b.pathname)),X.pathname.charAt(0)!=="/"&&m(X.protocol)&&(X.pathname="/"+X.pathname),a(X.port,X.protocol)||(X.host=X.hostname,X.port=""),X.username=X.password="",X.auth&&(A=X.auth.indexOf(":"),~A?(X.username=X.auth.slice(0,A),X.username=encodeURIComponent(decodeURIComponent(X.username)),X.password=X.auth.slice(A+1),X.password=encodeURIComponent(decodeURIComponent(X.password))):X.username=encodeURIComponent(decodeURIComponent(X.auth)),X.auth=X.password?X.username+":"+X.password:X.username),X.origin=X.protocol!=="file:"&&m(X.protocol)&&X.host?X.protocol+"//"+X.host:"null",X.href=X.toString()}function k(N,b,D){var y=this;switch(N){case"query":typeof b=="string"&&b.length&&(b=(D||l.parse)(b)),y[N]=b;break;case"port":y[N]=b,a(b,y.protocol)?b&&(y.host=y.hostname+":"+b):(y.host=y.hostname,y[N]="");break;case"hostname":y[N]=b,y.port&&(b+=":"+y.port),y.host=b;break;case"host":y[N]=b,p.test(b)?(b=b.split(":"),y.port=b.pop(),y.hostname=b.join(":")):(y.hostname=b,y.port="");break;case"protocol":y.protocol=b.toLowerCase(),y.slashes=!D;break;case"pathname":case"hash":if(b){var x=N==="pathname"?"/":"#";y[N]=b.charAt(0)!==x?x+b:b}else y[N]=b;break;case"username":case"password":y[N]=encodeURIComponent(b);break;case"auth":var C=b.indexOf(":");~C?(y.username=b.slice(0,C),y.username=encodeURIComponent(decodeURIComponent(y.username)),y.password=b.slice(C+1),y.password=encodeURIComponent(decodeURIComponent(y.password))):y.username=encodeURIComponent(decodeURIComponent(b))}for(var R=0;R<v.length;R++){var A=v[R];A[4]&&(y[A[1]]=y[A[1]].toLowerCase())}return y.auth=y.password?y.username+":"+y.password:y.username,y.origin=y.protocol!=="file:"&&m(y.protocol)&&y.host?y.protocol+"//"+y.host:"null",y.href=y.toString(),y}function V(N){(!N||typeof N!="function")&&(N=l.stringify);var b,D=this,y=D.host,x=D.protocol;x&&x.charAt(x.length-1)!==":"&&(x+=":");var C=x+(D.protocol&&D.slashes||m(D.protocol)?"//":"");return D.username?(C+=D.username,D.password&&(C+=":"+D.password),C+="@"):D.password?(C+=":"+D.password,C+="@"):D.protocol!=="file:"&&m(D.protocol)&&!y&&D.pathname!=="/"&&(C+="@"),(y[y.length-1]===":"||p.test(D.hostname)&&!D.port)&&(y+=":"),C+=y+D.pathname,b=typeof D.query=="object"?N(D.query):D.query,b&&(C+=b.charAt(0)!=="?"?"?"+b:b),D.hash&&(C+=D.hash),C}I.prototype={set:k,toString:V},I.extractProtocol=w,I.location=g,I.trimLeft=E,I.qs=l,i.exports=I}).call(this)}).call(this,typeof global<"u"?global:typeof self<"u"?self:typeof window<"u"?window:{})},{querystringify:58,"requires-port":59}]},{},[1])(1)})});bs();bs();bs();Ul();Pt();var ud=Object.create(null);function lv(e,t){if(!Q(e))if(e.nodeType)e=e.innerHTML;else return Ee;let n=Ro(e,t),s=ud[n];if(s)return s;if(e[0]==="#"){let l=document.querySelector(e);e=l?l.innerHTML:""}let i=se({hoistStatic:!0,onError:void 0,onWarn:Ee},t);!i.isCustomElement&&typeof customElements<"u"&&(i.isCustomElement=l=>!!customElements.get(l));let{code:r}=$l(e,i);function o(l,c=!1){let f=c?l.message:`Template compilation error: ${l.message}`,u=l.loc&&Fs(e,l.loc.start.offset,l.loc.end.offset);xr(u?`${f}
-${u}`:f)}let a=new Function("Vue",r)(Mr);return a._rc=!0,ud[n]=a}Wa(lv);bs();function Hl(e){return e===0?!1:Array.isArray(e)&&e.length===0?!0:!e}function cv(e){return(...t)=>!e(...t)}function uv(e,t){return e===void 0&&(e="undefined"),e===null&&(e="null"),e===!1&&(e="false"),e.toString().toLowerCase().indexOf(t.trim())!==-1}function pd(e,t,n,s){return t?e.filter(i=>uv(s(i,n),t)).sort((i,r)=>s(i,n).length-s(r,n).length):e}function fv(e){return e.filter(t=>!t.$isLabel)}function Bl(e,t){return n=>n.reduce((s,i)=>i[e]&&i[e].length?(s.push({$groupLabel:i[t],$isLabel:!0}),s.concat(i[e])):s,[])}function pv(e,t,n,s,i){return r=>r.map(o=>{if(!o[n])return console.warn("Options passed to vue-multiselect do not contain groups, despite the config."),[];let a=pd(o[n],e,t,i);return a.length?{[s]:o[s],[n]:a}:[]})}var fd=(...e)=>t=>e.reduce((n,s)=>s(n),t),dv={data(){return{search:"",isOpen:!1,preferredOpenDirection:"below",optimizedHeight:this.maxHeight}},props:{internalSearch:{type:Boolean,default:!0},options:{type:Array,required:!0},multiple:{type:Boolean,default:!1},trackBy:{type:String},label:{type:String},searchable:{type:Boolean,default:!0},clearOnSelect:{type:Boolean,default:!0},hideSelected:{type:Boolean,default:!1},placeholder:{type:String,default:"Select option"},allowEmpty:{type:Boolean,default:!0},resetAfter:{type:Boolean,default:!1},closeOnSelect:{type:Boolean,default:!0},customLabel:{type:Function,default(e,t){return Hl(e)?"":t?e[t]:e}},taggable:{type:Boolean,default:!1},tagPlaceholder:{type:String,default:"Press enter to create a tag"},tagPosition:{type:String,default:"top"},max:{type:[Number,Boolean],default:!1},id:{default:null},optionsLimit:{type:Number,default:1e3},groupValues:{type:String},groupLabel:{type:String},groupSelect:{type:Boolean,default:!1},blockKeys:{type:Array,default(){return[]}},preserveSearch:{type:Boolean,default:!1},preselectFirst:{type:Boolean,default:!1},preventAutofocus:{type:Boolean,default:!1}},mounted(){!this.multiple&&this.max&&console.warn("[Vue-Multiselect warn]: Max prop should not be used when prop Multiple equals false."),this.preselectFirst&&!this.internalValue.length&&this.options.length&&this.select(this.filteredOptions[0])},computed:{internalValue(){return this.modelValue||this.modelValue===0?Array.isArray(this.modelValue)?this.modelValue:[this.modelValue]:[]},filteredOptions(){let e=this.search||"",t=e.toLowerCase().trim(),n=this.options.concat();return this.internalSearch?n=this.groupValues?this.filterAndFlat(n,t,this.label):pd(n,t,this.label,this.customLabel):n=this.groupValues?Bl(this.groupValues,this.groupLabel)(n):n,n=this.hideSelected?n.filter(cv(this.isSelected)):n,this.taggable&&t.length&&!this.isExistingOption(t)&&(this.tagPosition==="bottom"?n.push({isTag:!0,label:e}):n.unshift({isTag:!0,label:e})),n.slice(0,this.optionsLimit)},valueKeys(){return this.trackBy?this.internalValue.map(e=>e[this.trackBy]):this.internalValue},optionKeys(){return(this.groupValues?this.flatAndStrip(this.options):this.options).map(t=>this.customLabel(t,this.label).toString().toLowerCase())},currentOptionLabel(){return this.multiple?this.searchable?"":this.placeholder:this.internalValue.length?this.getOptionLabel(this.internalValue[0]):this.searchable?"":this.placeholder}},watch:{internalValue:{handler(){this.resetAfter&&this.internalValue.length&&(this.search="",this.$emit("update:modelValue",this.multiple?[]:null))},deep:!0},search(){this.$emit("search-change",this.search)}},emits:["open","search-change","close","select","update:modelValue","remove","tag"],methods:{getValue(){return this.multiple?this.internalValue:this.internalValue.length===0?null:this.internalValue[0]},filterAndFlat(e,t,n){return fd(pv(t,n,this.groupValues,this.groupLabel,this.customLabel),Bl(this.groupValues,this.groupLabel))(e)},flatAndStrip(e){return fd(Bl(this.groupValues,this.groupLabel),fv)(e)},updateSearch(e){this.search=e},isExistingOption(e){return this.options?this.optionKeys.indexOf(e)>-1:!1},isSelected(e){let t=this.trackBy?e[this.trackBy]:e;return this.valueKeys.indexOf(t)>-1},isOptionDisabled(e){return!!e.$isDisabled},getOptionLabel(e){if(Hl(e))return"";if(e.isTag)return e.label;if(e.$isLabel)return e.$groupLabel;let t=this.customLabel(e,this.label);return Hl(t)?"":t},select(e,t){if(e.$isLabel&&this.groupSelect){this.selectGroup(e);return}if(!(this.blockKeys.indexOf(t)!==-1||this.disabled||e.$isDisabled||e.$isLabel)&&!(this.max&&this.multiple&&this.internalValue.length===this.max)&&!(t==="Tab"&&!this.pointerDirty)){if(e.isTag)this.$emit("tag",e.label,this.id),this.search="",this.closeOnSelect&&!this.multiple&&this.deactivate();else{if(this.isSelected(e)){t!=="Tab"&&this.removeElement(e);return}this.multiple?this.$emit("update:modelValue",this.internalValue.concat([e])):this.$emit("update:modelValue",e),this.$emit("select",e,this.id),this.clearOnSelect&&(this.search="")}this.closeOnSelect&&this.deactivate()}},selectGroup(e){let t=this.options.find(n=>n[this.groupLabel]===e.$groupLabel);if(t){if(this.wholeGroupSelected(t)){this.$emit("remove",t[this.groupValues],this.id);let n=this.trackBy?t[this.groupValues].map(i=>i[this.trackBy]):t[this.groupValues],s=this.internalValue.filter(i=>n.indexOf(this.trackBy?i[this.trackBy]:i)===-1);this.$emit("update:modelValue",s)}else{let n=t[this.groupValues].filter(s=>!(this.isOptionDisabled(s)||this.isSelected(s)));this.max&&n.splice(this.max-this.internalValue.length),this.$emit("select",n,this.id),this.$emit("update:modelValue",this.internalValue.concat(n))}this.closeOnSelect&&this.deactivate()}},wholeGroupSelected(e){return e[this.groupValues].every(t=>this.isSelected(t)||this.isOptionDisabled(t))},wholeGroupDisabled(e){return e[this.groupValues].every(this.isOptionDisabled)},removeElement(e,t=!0){if(this.disabled||e.$isDisabled)return;if(!this.allowEmpty&&this.internalValue.length<=1){this.deactivate();return}let n=typeof e=="object"?this.valueKeys.indexOf(e[this.trackBy]):this.valueKeys.indexOf(e);if(this.multiple){let s=this.internalValue.slice(0,n).concat(this.internalValue.slice(n+1));this.$emit("update:modelValue",s)}else this.$emit("update:modelValue",null);this.$emit("remove",e,this.id),this.closeOnSelect&&t&&this.deactivate()},removeLastElement(){this.blockKeys.indexOf("Delete")===-1&&this.search.length===0&&Array.isArray(this.internalValue)&&this.internalValue.length&&this.removeElement(this.internalValue[this.internalValue.length-1],!1)},activate(){this.isOpen||this.disabled||(this.adjustPosition(),this.groupValues&&this.pointer===0&&this.filteredOptions.length&&(this.pointer=1),this.isOpen=!0,this.searchable?(this.preserveSearch||(this.search=""),this.preventAutofocus||this.$nextTick(()=>this.$refs.search&&this.$refs.search.focus())):this.preventAutofocus||typeof this.$el<"u"&&this.$el.focus(),this.$emit("open",this.id))},deactivate(){this.isOpen&&(this.isOpen=!1,this.searchable?this.$refs.search!==null&&typeof this.$refs.search<"u"&&this.$refs.search.blur():typeof this.$el<"u"&&this.$el.blur(),this.preserveSearch||(this.search=""),this.$emit("close",this.getValue(),this.id))},toggle(){this.isOpen?this.deactivate():this.activate()},adjustPosition(){if(typeof window>"u")return;let e=this.$el.getBoundingClientRect().top,t=window.innerHeight-this.$el.getBoundingClientRect().bottom;t>this.maxHeight||t>e||this.openDirection==="below"||this.openDirection==="bottom"?(this.preferredOpenDirection="below",this.optimizedHeight=Math.min(t-40,this.maxHeight)):(this.preferredOpenDirection="above",this.optimizedHeight=Math.min(e-40,this.maxHeight))}}},hv={data(){return{pointer:0,pointerDirty:!1}},props:{showPointer:{type:Boolean,default:!0},optionHeight:{type:Number,default:40}},computed:{pointerPosition(){return this.pointer*this.optionHeight},visibleElements(){return this.optimizedHeight/this.optionHeight}},watch:{filteredOptions(){this.pointerAdjust()},isOpen(){this.pointerDirty=!1},pointer(){this.$refs.search&&this.$refs.search.setAttribute("aria-activedescendant",this.id+"-"+this.pointer.toString())}},methods:{optionHighlight(e,t){return{"multiselect__option--highlight":e===this.pointer&&this.showPointer,"multiselect__option--selected":this.isSelected(t)}},groupHighlight(e,t){if(!this.groupSelect)return["multiselect__option--disabled",{"multiselect__option--group":t.$isLabel}];let n=this.options.find(s=>s[this.groupLabel]===t.$groupLabel);return n&&!this.wholeGroupDisabled(n)?["multiselect__option--group",{"multiselect__option--highlight":e===this.pointer&&this.showPointer},{"multiselect__option--group-selected":this.wholeGroupSelected(n)}]:"multiselect__option--disabled"},addPointerElement({key:e}="Enter"){this.filteredOptions.length>0&&this.select(this.filteredOptions[this.pointer],e),this.pointerReset()},pointerForward(){this.pointer<this.filteredOptions.length-1&&(this.pointer++,this.$refs.list.scrollTop<=this.pointerPosition-(this.visibleElements-1)*this.optionHeight&&(this.$refs.list.scrollTop=this.pointerPosition-(this.visibleElements-1)*this.optionHeight),this.filteredOptions[this.pointer]&&this.filteredOptions[this.pointer].$isLabel&&!this.groupSelect&&this.pointerForward()),this.pointerDirty=!0},pointerBackward(){this.pointer>0?(this.pointer--,this.$refs.list.scrollTop>=this.pointerPosition&&(this.$refs.list.scrollTop=this.pointerPosition),this.filteredOptions[this.pointer]&&this.filteredOptions[this.pointer].$isLabel&&!this.groupSelect&&this.pointerBackward()):this.filteredOptions[this.pointer]&&this.filteredOptions[0].$isLabel&&!this.groupSelect&&this.pointerForward(),this.pointerDirty=!0},pointerReset(){this.closeOnSelect&&(this.pointer=0,this.$refs.list&&(this.$refs.list.scrollTop=0))},pointerAdjust(){this.pointer>=this.filteredOptions.length-1&&(this.pointer=this.filteredOptions.length?this.filteredOptions.length-1:0),this.filteredOptions.length>0&&this.filteredOptions[this.pointer].$isLabel&&!this.groupSelect&&this.pointerForward()},pointerSet(e){this.pointer=e,this.pointerDirty=!0}}},dd={name:"vue-multiselect",mixins:[dv,hv],compatConfig:{MODE:3,ATTR_ENUMERATED_COERCION:!1},props:{name:{type:String,default:""},modelValue:{type:null,default(){return[]}},selectLabel:{type:String,default:"Press enter to select"},selectGroupLabel:{type:String,default:"Press enter to select group"},selectedLabel:{type:String,default:"Selected"},deselectLabel:{type:String,default:"Press enter to remove"},deselectGroupLabel:{type:String,default:"Press enter to deselect group"},showLabels:{type:Boolean,default:!0},limit:{type:Number,default:99999},maxHeight:{type:Number,default:300},limitText:{type:Function,default:e=>`and ${e} more`},loading:{type:Boolean,default:!1},disabled:{type:Boolean,default:!1},spellcheck:{type:Boolean,default:!1},openDirection:{type:String,default:""},showNoOptions:{type:Boolean,default:!0},showNoResults:{type:Boolean,default:!0},tabindex:{type:Number,default:0},required:{type:Boolean,default:!1}},computed:{hasOptionGroup(){return this.groupValues&&this.groupLabel&&this.groupSelect},isSingleLabelVisible(){return(this.singleValue||this.singleValue===0)&&(!this.isOpen||!this.searchable)&&!this.visibleValues.length},isPlaceholderVisible(){return!this.internalValue.length&&(!this.searchable||!this.isOpen)},visibleValues(){return this.multiple?this.internalValue.slice(0,this.limit):[]},singleValue(){return this.internalValue[0]},deselectLabelText(){return this.showLabels?this.deselectLabel:""},deselectGroupLabelText(){return this.showLabels?this.deselectGroupLabel:""},selectLabelText(){return this.showLabels?this.selectLabel:""},selectGroupLabelText(){return this.showLabels?this.selectGroupLabel:""},selectedLabelText(){return this.showLabels?this.selectedLabel:""},inputStyle(){return this.searchable||this.multiple&&this.modelValue&&this.modelValue.length?this.isOpen?{width:"100%"}:{width:"0",position:"absolute",padding:"0"}:""},contentStyle(){return this.options.length?{display:"inline-block"}:{display:"block"}},isAbove(){return this.openDirection==="above"||this.openDirection==="top"?!0:this.openDirection==="below"||this.openDirection==="bottom"?!1:this.preferredOpenDirection==="above"},showSearchInput(){return this.searchable&&(this.hasSingleSelectedSlot&&(this.visibleSingleValue||this.visibleSingleValue===0)?this.isOpen:!0)}}},mv={ref:"tags",class:"multiselect__tags"},gv={class:"multiselect__tags-wrap"},Ev={class:"multiselect__spinner"},_v={key:0},vv={class:"multiselect__option"},yv={class:"multiselect__option"},Nv=rn("No elements found. Consider changing the search query."),bv={class:"multiselect__option"},Ov=rn("List is empty.");function Sv(e,t,n,s,i,r){return He(),Ge("div",{tabindex:e.searchable?-1:n.tabindex,class:[{"multiselect--active":e.isOpen,"multiselect--disabled":n.disabled,"multiselect--above":r.isAbove,"multiselect--has-options-group":r.hasOptionGroup},"multiselect"],onFocus:t[14]||(t[14]=o=>e.activate()),onBlur:t[15]||(t[15]=o=>e.searchable?!1:e.deactivate()),onKeydown:[t[16]||(t[16]=yt(Ce(o=>e.pointerForward(),["self","prevent"]),["down"])),t[17]||(t[17]=yt(Ce(o=>e.pointerBackward(),["self","prevent"]),["up"]))],onKeypress:t[18]||(t[18]=yt(Ce(o=>e.addPointerElement(o),["stop","self"]),["enter","tab"])),onKeyup:t[19]||(t[19]=yt(o=>e.deactivate(),["esc"])),role:"combobox","aria-owns":"listbox-"+e.id},[Be(e.$slots,"caret",{toggle:e.toggle},()=>[ie("div",{onMousedown:t[1]||(t[1]=Ce(o=>e.toggle(),["prevent","stop"])),class:"multiselect__select"},null,32)]),Be(e.$slots,"clear",{search:e.search}),ie("div",mv,[Be(e.$slots,"selection",{search:e.search,remove:e.removeElement,values:r.visibleValues,isOpen:e.isOpen},()=>[Wn(ie("div",gv,[(He(!0),Ge(ye,null,Tr(r.visibleValues,(o,a)=>Be(e.$slots,"tag",{option:o,search:e.search,remove:e.removeElement},()=>[(He(),Ge("span",{class:"multiselect__tag",key:a},[ie("span",{textContent:gt(e.getOptionLabel(o))},null,8,["textContent"]),ie("i",{tabindex:"1",onKeypress:yt(Ce(l=>e.removeElement(o),["prevent"]),["enter"]),onMousedown:Ce(l=>e.removeElement(o),["prevent"]),class:"multiselect__tag-icon"},null,40,["onKeypress","onMousedown"])]))])),256))],512),[[Sn,r.visibleValues.length>0]]),e.internalValue&&e.internalValue.length>n.limit?Be(e.$slots,"limit",{key:0},()=>[ie("strong",{class:"multiselect__strong",textContent:gt(n.limitText(e.internalValue.length-n.limit))},null,8,["textContent"])]):Xt("v-if",!0)]),ie(Lr,{name:"multiselect__loading"},{default:_s(()=>[Be(e.$slots,"loading",{},()=>[Wn(ie("div",Ev,null,512),[[Sn,n.loading]])])]),_:3}),e.searchable?(He(),Ge("input",{key:0,ref:"search",name:n.name,id:e.id,type:"text",autocomplete:"off",spellcheck:n.spellcheck,placeholder:e.placeholder,required:n.required,style:r.inputStyle,value:e.search,disabled:n.disabled,tabindex:n.tabindex,onInput:t[2]||(t[2]=o=>e.updateSearch(o.target.value)),onFocus:t[3]||(t[3]=Ce(o=>e.activate(),["prevent"])),onBlur:t[4]||(t[4]=Ce(o=>e.deactivate(),["prevent"])),onKeyup:t[5]||(t[5]=yt(o=>e.deactivate(),["esc"])),onKeydown:[t[6]||(t[6]=yt(Ce(o=>e.pointerForward(),["prevent"]),["down"])),t[7]||(t[7]=yt(Ce(o=>e.pointerBackward(),["prevent"]),["up"])),t[9]||(t[9]=yt(Ce(o=>e.removeLastElement(),["stop"]),["delete"]))],onKeypress:t[8]||(t[8]=yt(Ce(o=>e.addPointerElement(o),["prevent","stop","self"]),["enter"])),class:"multiselect__input","aria-controls":"listbox-"+e.id},null,44,["name","id","spellcheck","placeholder","required","value","disabled","tabindex","aria-controls"])):Xt("v-if",!0),r.isSingleLabelVisible?(He(),Ge("span",{key:1,class:"multiselect__single",onMousedown:t[10]||(t[10]=Ce((...o)=>e.toggle&&e.toggle(...o),["prevent"]))},[Be(e.$slots,"singleLabel",{option:r.singleValue},()=>[rn(gt(e.currentOptionLabel),1)])],32)):Xt("v-if",!0),r.isPlaceholderVisible?(He(),Ge("span",{key:2,class:"multiselect__placeholder",onMousedown:t[11]||(t[11]=Ce((...o)=>e.toggle&&e.toggle(...o),["prevent"]))},[Be(e.$slots,"placeholder",{},()=>[rn(gt(e.placeholder),1)])],32)):Xt("v-if",!0)],512),ie(Lr,{name:"multiselect"},{default:_s(()=>[Wn(ie("div",{class:"multiselect__content-wrapper",onFocus:t[12]||(t[12]=(...o)=>e.activate&&e.activate(...o)),tabindex:"-1",onMousedown:t[13]||(t[13]=Ce(()=>{},["prevent"])),style:{maxHeight:e.optimizedHeight+"px"},ref:"list"},[ie("ul",{class:"multiselect__content",style:r.contentStyle,role:"listbox",id:"listbox-"+e.id,"aria-multiselectable":e.multiple},[Be(e.$slots,"beforeList"),e.multiple&&e.max===e.internalValue.length?(He(),Ge("li",_v,[ie("span",vv,[Be(e.$slots,"maxElements",{},()=>[rn("Maximum of "+gt(e.max)+" options selected. First remove a selected option to select another.",1)])])])):Xt("v-if",!0),!e.max||e.internalValue.length<e.max?(He(!0),Ge(ye,{key:1},Tr(e.filteredOptions,(o,a)=>(He(),Ge("li",{class:"multiselect__element",key:a,"aria-selected":e.isSelected(o),id:e.id+"-"+a,role:o&&(o.$isLabel||o.$isDisabled)?null:"option"},[o&&(o.$isLabel||o.$isDisabled)?Xt("v-if",!0):(He(),Ge("span",{key:0,class:[e.optionHighlight(a,o),"multiselect__option"],onClick:Ce(l=>e.select(o),["stop"]),onMouseenter:Ce(l=>e.pointerSet(a),["self"]),"data-select":o&&o.isTag?e.tagPlaceholder:r.selectLabelText,"data-selected":r.selectedLabelText,"data-deselect":r.deselectLabelText},[Be(e.$slots,"option",{option:o,search:e.search,index:a},()=>[ie("span",null,gt(e.getOptionLabel(o)),1)])],42,["onClick","onMouseenter","data-select","data-selected","data-deselect"])),o&&(o.$isLabel||o.$isDisabled)?(He(),Ge("span",{key:1,"data-select":e.groupSelect&&r.selectGroupLabelText,"data-deselect":e.groupSelect&&r.deselectGroupLabelText,class:[e.groupHighlight(a,o),"multiselect__option"],onMouseenter:Ce(l=>e.groupSelect&&e.pointerSet(a),["self"]),onMousedown:Ce(l=>e.selectGroup(o),["prevent"])},[Be(e.$slots,"option",{option:o,search:e.search,index:a},()=>[ie("span",null,gt(e.getOptionLabel(o)),1)])],42,["data-select","data-deselect","onMouseenter","onMousedown"])):Xt("v-if",!0)],8,["aria-selected","id","role"]))),128)):Xt("v-if",!0),Wn(ie("li",null,[ie("span",yv,[Be(e.$slots,"noResult",{search:e.search},()=>[Nv])])],512),[[Sn,n.showNoResults&&e.filteredOptions.length===0&&e.search&&!n.loading]]),Wn(ie("li",null,[ie("span",bv,[Be(e.$slots,"noOptions",{},()=>[Ov])])],512),[[Sn,n.showNoOptions&&(e.options.length===0||r.hasOptionGroup===!0&&e.filteredOptions.length===0)&&!e.search&&!n.loading]]),Be(e.$slots,"afterList")],12,["id","aria-multiselectable"])],36),[[Sn,e.isOpen]])]),_:3})],42,["tabindex","aria-owns"])}dd.render=Sv;var hd=dd;var wd=zl(vd()),Td=zl(Nd());function bd(e,t){return e.indexOf(t,e.length-t.length)!==-1}var xv={"&":"&amp;","<":"&lt;",">":"&gt;","/":"&#x2F;"};function Od(e){return String(e).replace(/[&<>\/]/g,function(t){return xv[t]})}var Sd={template:'<div class="log-view"></div>',props:["linesOfHistory"],setup(){let e=Ra("logview")},data:function(){return{history:[],lastSpan:null,lastSpanClasses:"",autoScroll:!0}},watch:{linesOfHistory:function(e){this.trimHistory()}},methods:{clearLines:function(){this.$el.innerHTML="",this.history=[],this.lastSpan=null},toggleWrapLines:function(e){this.$el.classList.toggle("log-view-wrapped",e)},createSpan:function(e,t){var n=document.createElement("span");return n.innerHTML=e,n.className=t,n},createLogEntrySpan:function(e){return this.createSpan(e,"log-entry")},createNoticePan:function(e){return createSpan(e,"log-entry log-notice")},trimHistory:function(){if(this.linesOfHistory!==0&&this.history.length>this.linesOfHistory)for(var e=0;e<this.history.length-this.linesOfHistory+1;e++)this.$el.removeChild(this.history.shift())},isScrolledToBottom:function(){var e=this.$el.parentElement,t=e.scrollTop-(e.scrollHeight-e.offsetHeight);return Math.abs(t)<50},scroll:function(){this.$el.parentElement.scrollTop=this.$el.parentElement.scrollHeight},write:function(e,t){var n;e==="o"&&(t=Od(t).replace(/\n$/,""),n=this.createLogEntrySpan(t),this.writeSpans([n]))},writeSpans:function(e){if(e.length!==0){for(var t=this.isScrolledToBottom(),n=document.createDocumentFragment(),s=0;s<e.length;s++){var i=e[s];this.history.push(i),n.appendChild(i)}this.lastSpan&&(this.lastSpan.className=this.lastSpanClasses),this.$el.appendChild(n),this.trimHistory(),this.autoScroll&&t&&this.scroll(),this.lastSpan=this.history[this.history.length-1],this.lastSpanClasses=this.lastSpan.className,this.lastSpan.className=this.lastSpanClasses+" log-entry-current"}}}};var bo=bd(window.relativeRoot,"/")?"ws":"/ws",bo=[window.location.protocol,"//",window.location.host,window.relativeRoot,bo].join(""),Oo=mi({delimiters:["<%","%>"],data(){return{relativeRoot,commandScripts,fileList:[],allowCommandNames,allowDownload,file:null,command:null,script:null,linesOfHistory:2e3,linesToTail:10,wrapLines:!1,hideToolbar:!1,showConfig:!1,showLoadingOverlay:!1,socket:null,isConnected:!1}},created(){this.backendConnect(),this.command=this.allowCommandNames[0]},computed:{scriptInputEnabled:function(){return this.commandScripts[this.command]!==""},downloadLink:function(){return this.file?relativeRoot+"files/?path="+this.file.path:"#"},downloadFileName:function(){return this.file?this.file.path.split("/").at(-1):"#"}},methods:{clearLogview:function(){this.$refs.logview.clearLines()},backendConnect:function(){console.log("connecting to "+bo),this.showLoadingOverlay=!0,this.socket=new Td.default(bo),this.socket.onopen=this.onBackendOpen,this.socket.onclose=this.onBackendClose,this.socket.onmessage=this.onBackendMessage},onBackendOpen:function(){console.log("connected to backend"),this.isConnected=!0,this.refreshFiles()},onBackendClose:function(){console.log("disconnected from backend"),this.isConnected=!1,backendConnect=this.backendConnect,window.setTimeout(function(){backendConnect()},1e3)},onBackendMessage:function(e){var t=JSON.parse(e.data);if(t.constructor===Object){var n=[];Object.keys(t).forEach(function(r){var o=r==="__default__"?"Ungrouped Files":r;n.push({group:o,files:t[r]})}),this.fileList=n,this.file||(this.file=n[0].files[0])}else{var s=t[0],i=t[1];this.$refs.logview.write(s,i)}},refreshFiles:function(){console.log("updating file list"),this.socket.send("list")},notifyBackend:function(){var e={command:this.command,script:this.script,entry:this.file,nlines:this.linesToTail};console.log("sending msg: ",e),this.clearLogview(),this.socket.send(JSON.stringify(e))}},watch:{isConnected:function(e){this.showLoadingOverlay=!e},wrapLines:function(e){this.$refs.logview.toggleWrapLines(e)},command:function(e){e&&this.isConnected&&(this.script=this.commandScripts[e],this.notifyBackend())},file:function(e){e&&this.isConnected&&this.notifyBackend()}}});Oo.component("multiselect",hd);Oo.component("logview",Sd);Oo.component("loading",wd.default);Oo.mount("#app");})();
+${u}`:f)}let a=new Function("Vue",r)(Mr);return a._rc=!0,ud[n]=a}Wa(lv);bs();function Hl(e){return e===0?!1:Array.isArray(e)&&e.length===0?!0:!e}function cv(e){return(...t)=>!e(...t)}function uv(e,t){return e===void 0&&(e="undefined"),e===null&&(e="null"),e===!1&&(e="false"),e.toString().toLowerCase().indexOf(t.trim())!==-1}function pd(e,t,n,s){return t?e.filter(i=>uv(s(i,n),t)).sort((i,r)=>s(i,n).length-s(r,n).length):e}function fv(e){return e.filter(t=>!t.$isLabel)}function Bl(e,t){return n=>n.reduce((s,i)=>i[e]&&i[e].length?(s.push({$groupLabel:i[t],$isLabel:!0}),s.concat(i[e])):s,[])}function pv(e,t,n,s,i){return r=>r.map(o=>{if(!o[n])return console.warn("Options passed to vue-multiselect do not contain groups, despite the config."),[];let a=pd(o[n],e,t,i);return a.length?{[s]:o[s],[n]:a}:[]})}var fd=(...e)=>t=>e.reduce((n,s)=>s(n),t),dv={data(){return{search:"",isOpen:!1,preferredOpenDirection:"below",optimizedHeight:this.maxHeight}},props:{internalSearch:{type:Boolean,default:!0},options:{type:Array,required:!0},multiple:{type:Boolean,default:!1},trackBy:{type:String},label:{type:String},searchable:{type:Boolean,default:!0},clearOnSelect:{type:Boolean,default:!0},hideSelected:{type:Boolean,default:!1},placeholder:{type:String,default:"Select option"},allowEmpty:{type:Boolean,default:!0},resetAfter:{type:Boolean,default:!1},closeOnSelect:{type:Boolean,default:!0},customLabel:{type:Function,default(e,t){return Hl(e)?"":t?e[t]:e}},taggable:{type:Boolean,default:!1},tagPlaceholder:{type:String,default:"Press enter to create a tag"},tagPosition:{type:String,default:"top"},max:{type:[Number,Boolean],default:!1},id:{default:null},optionsLimit:{type:Number,default:1e3},groupValues:{type:String},groupLabel:{type:String},groupSelect:{type:Boolean,default:!1},blockKeys:{type:Array,default(){return[]}},preserveSearch:{type:Boolean,default:!1},preselectFirst:{type:Boolean,default:!1},preventAutofocus:{type:Boolean,default:!1}},mounted(){!this.multiple&&this.max&&console.warn("[Vue-Multiselect warn]: Max prop should not be used when prop Multiple equals false."),this.preselectFirst&&!this.internalValue.length&&this.options.length&&this.select(this.filteredOptions[0])},computed:{internalValue(){return this.modelValue||this.modelValue===0?Array.isArray(this.modelValue)?this.modelValue:[this.modelValue]:[]},filteredOptions(){let e=this.search||"",t=e.toLowerCase().trim(),n=this.options.concat();return this.internalSearch?n=this.groupValues?this.filterAndFlat(n,t,this.label):pd(n,t,this.label,this.customLabel):n=this.groupValues?Bl(this.groupValues,this.groupLabel)(n):n,n=this.hideSelected?n.filter(cv(this.isSelected)):n,this.taggable&&t.length&&!this.isExistingOption(t)&&(this.tagPosition==="bottom"?n.push({isTag:!0,label:e}):n.unshift({isTag:!0,label:e})),n.slice(0,this.optionsLimit)},valueKeys(){return this.trackBy?this.internalValue.map(e=>e[this.trackBy]):this.internalValue},optionKeys(){return(this.groupValues?this.flatAndStrip(this.options):this.options).map(t=>this.customLabel(t,this.label).toString().toLowerCase())},currentOptionLabel(){return this.multiple?this.searchable?"":this.placeholder:this.internalValue.length?this.getOptionLabel(this.internalValue[0]):this.searchable?"":this.placeholder}},watch:{internalValue:{handler(){this.resetAfter&&this.internalValue.length&&(this.search="",this.$emit("update:modelValue",this.multiple?[]:null))},deep:!0},search(){this.$emit("search-change",this.search)}},emits:["open","search-change","close","select","update:modelValue","remove","tag"],methods:{getValue(){return this.multiple?this.internalValue:this.internalValue.length===0?null:this.internalValue[0]},filterAndFlat(e,t,n){return fd(pv(t,n,this.groupValues,this.groupLabel,this.customLabel),Bl(this.groupValues,this.groupLabel))(e)},flatAndStrip(e){return fd(Bl(this.groupValues,this.groupLabel),fv)(e)},updateSearch(e){this.search=e},isExistingOption(e){return this.options?this.optionKeys.indexOf(e)>-1:!1},isSelected(e){let t=this.trackBy?e[this.trackBy]:e;return this.valueKeys.indexOf(t)>-1},isOptionDisabled(e){return!!e.$isDisabled},getOptionLabel(e){if(Hl(e))return"";if(e.isTag)return e.label;if(e.$isLabel)return e.$groupLabel;let t=this.customLabel(e,this.label);return Hl(t)?"":t},select(e,t){if(e.$isLabel&&this.groupSelect){this.selectGroup(e);return}if(!(this.blockKeys.indexOf(t)!==-1||this.disabled||e.$isDisabled||e.$isLabel)&&!(this.max&&this.multiple&&this.internalValue.length===this.max)&&!(t==="Tab"&&!this.pointerDirty)){if(e.isTag)this.$emit("tag",e.label,this.id),this.search="",this.closeOnSelect&&!this.multiple&&this.deactivate();else{if(this.isSelected(e)){t!=="Tab"&&this.removeElement(e);return}this.multiple?this.$emit("update:modelValue",this.internalValue.concat([e])):this.$emit("update:modelValue",e),this.$emit("select",e,this.id),this.clearOnSelect&&(this.search="")}this.closeOnSelect&&this.deactivate()}},selectGroup(e){let t=this.options.find(n=>n[this.groupLabel]===e.$groupLabel);if(t){if(this.wholeGroupSelected(t)){this.$emit("remove",t[this.groupValues],this.id);let n=this.trackBy?t[this.groupValues].map(i=>i[this.trackBy]):t[this.groupValues],s=this.internalValue.filter(i=>n.indexOf(this.trackBy?i[this.trackBy]:i)===-1);this.$emit("update:modelValue",s)}else{let n=t[this.groupValues].filter(s=>!(this.isOptionDisabled(s)||this.isSelected(s)));this.max&&n.splice(this.max-this.internalValue.length),this.$emit("select",n,this.id),this.$emit("update:modelValue",this.internalValue.concat(n))}this.closeOnSelect&&this.deactivate()}},wholeGroupSelected(e){return e[this.groupValues].every(t=>this.isSelected(t)||this.isOptionDisabled(t))},wholeGroupDisabled(e){return e[this.groupValues].every(this.isOptionDisabled)},removeElement(e,t=!0){if(this.disabled||e.$isDisabled)return;if(!this.allowEmpty&&this.internalValue.length<=1){this.deactivate();return}let n=typeof e=="object"?this.valueKeys.indexOf(e[this.trackBy]):this.valueKeys.indexOf(e);if(this.multiple){let s=this.internalValue.slice(0,n).concat(this.internalValue.slice(n+1));this.$emit("update:modelValue",s)}else this.$emit("update:modelValue",null);this.$emit("remove",e,this.id),this.closeOnSelect&&t&&this.deactivate()},removeLastElement(){this.blockKeys.indexOf("Delete")===-1&&this.search.length===0&&Array.isArray(this.internalValue)&&this.internalValue.length&&this.removeElement(this.internalValue[this.internalValue.length-1],!1)},activate(){this.isOpen||this.disabled||(this.adjustPosition(),this.groupValues&&this.pointer===0&&this.filteredOptions.length&&(this.pointer=1),this.isOpen=!0,this.searchable?(this.preserveSearch||(this.search=""),this.preventAutofocus||this.$nextTick(()=>this.$refs.search&&this.$refs.search.focus())):this.preventAutofocus||typeof this.$el<"u"&&this.$el.focus(),this.$emit("open",this.id))},deactivate(){this.isOpen&&(this.isOpen=!1,this.searchable?this.$refs.search!==null&&typeof this.$refs.search<"u"&&this.$refs.search.blur():typeof this.$el<"u"&&this.$el.blur(),this.preserveSearch||(this.search=""),this.$emit("close",this.getValue(),this.id))},toggle(){this.isOpen?this.deactivate():this.activate()},adjustPosition(){if(typeof window>"u")return;let e=this.$el.getBoundingClientRect().top,t=window.innerHeight-this.$el.getBoundingClientRect().bottom;t>this.maxHeight||t>e||this.openDirection==="below"||this.openDirection==="bottom"?(this.preferredOpenDirection="below",this.optimizedHeight=Math.min(t-40,this.maxHeight)):(this.preferredOpenDirection="above",this.optimizedHeight=Math.min(e-40,this.maxHeight))}}},hv={data(){return{pointer:0,pointerDirty:!1}},props:{showPointer:{type:Boolean,default:!0},optionHeight:{type:Number,default:40}},computed:{pointerPosition(){return this.pointer*this.optionHeight},visibleElements(){return this.optimizedHeight/this.optionHeight}},watch:{filteredOptions(){this.pointerAdjust()},isOpen(){this.pointerDirty=!1},pointer(){this.$refs.search&&this.$refs.search.setAttribute("aria-activedescendant",this.id+"-"+this.pointer.toString())}},methods:{optionHighlight(e,t){return{"multiselect__option--highlight":e===this.pointer&&this.showPointer,"multiselect__option--selected":this.isSelected(t)}},groupHighlight(e,t){if(!this.groupSelect)return["multiselect__option--disabled",{"multiselect__option--group":t.$isLabel}];let n=this.options.find(s=>s[this.groupLabel]===t.$groupLabel);return n&&!this.wholeGroupDisabled(n)?["multiselect__option--group",{"multiselect__option--highlight":e===this.pointer&&this.showPointer},{"multiselect__option--group-selected":this.wholeGroupSelected(n)}]:"multiselect__option--disabled"},addPointerElement({key:e}="Enter"){this.filteredOptions.length>0&&this.select(this.filteredOptions[this.pointer],e),this.pointerReset()},pointerForward(){this.pointer<this.filteredOptions.length-1&&(this.pointer++,this.$refs.list.scrollTop<=this.pointerPosition-(this.visibleElements-1)*this.optionHeight&&(this.$refs.list.scrollTop=this.pointerPosition-(this.visibleElements-1)*this.optionHeight),this.filteredOptions[this.pointer]&&this.filteredOptions[this.pointer].$isLabel&&!this.groupSelect&&this.pointerForward()),this.pointerDirty=!0},pointerBackward(){this.pointer>0?(this.pointer--,this.$refs.list.scrollTop>=this.pointerPosition&&(this.$refs.list.scrollTop=this.pointerPosition),this.filteredOptions[this.pointer]&&this.filteredOptions[this.pointer].$isLabel&&!this.groupSelect&&this.pointerBackward()):this.filteredOptions[this.pointer]&&this.filteredOptions[0].$isLabel&&!this.groupSelect&&this.pointerForward(),this.pointerDirty=!0},pointerReset(){this.closeOnSelect&&(this.pointer=0,this.$refs.list&&(this.$refs.list.scrollTop=0))},pointerAdjust(){this.pointer>=this.filteredOptions.length-1&&(this.pointer=this.filteredOptions.length?this.filteredOptions.length-1:0),this.filteredOptions.length>0&&this.filteredOptions[this.pointer].$isLabel&&!this.groupSelect&&this.pointerForward()},pointerSet(e){this.pointer=e,this.pointerDirty=!0}}},dd={name:"vue-multiselect",mixins:[dv,hv],compatConfig:{MODE:3,ATTR_ENUMERATED_COERCION:!1},props:{name:{type:String,default:""},modelValue:{type:null,default(){return[]}},selectLabel:{type:String,default:"Press enter to select"},selectGroupLabel:{type:String,default:"Press enter to select group"},selectedLabel:{type:String,default:"Selected"},deselectLabel:{type:String,default:"Press enter to remove"},deselectGroupLabel:{type:String,default:"Press enter to deselect group"},showLabels:{type:Boolean,default:!0},limit:{type:Number,default:99999},maxHeight:{type:Number,default:300},limitText:{type:Function,default:e=>`and ${e} more`},loading:{type:Boolean,default:!1},disabled:{type:Boolean,default:!1},spellcheck:{type:Boolean,default:!1},openDirection:{type:String,default:""},showNoOptions:{type:Boolean,default:!0},showNoResults:{type:Boolean,default:!0},tabindex:{type:Number,default:0},required:{type:Boolean,default:!1}},computed:{hasOptionGroup(){return this.groupValues&&this.groupLabel&&this.groupSelect},isSingleLabelVisible(){return(this.singleValue||this.singleValue===0)&&(!this.isOpen||!this.searchable)&&!this.visibleValues.length},isPlaceholderVisible(){return!this.internalValue.length&&(!this.searchable||!this.isOpen)},visibleValues(){return this.multiple?this.internalValue.slice(0,this.limit):[]},singleValue(){return this.internalValue[0]},deselectLabelText(){return this.showLabels?this.deselectLabel:""},deselectGroupLabelText(){return this.showLabels?this.deselectGroupLabel:""},selectLabelText(){return this.showLabels?this.selectLabel:""},selectGroupLabelText(){return this.showLabels?this.selectGroupLabel:""},selectedLabelText(){return this.showLabels?this.selectedLabel:""},inputStyle(){return this.searchable||this.multiple&&this.modelValue&&this.modelValue.length?this.isOpen?{width:"100%"}:{width:"0",position:"absolute",padding:"0"}:""},contentStyle(){return this.options.length?{display:"inline-block"}:{display:"block"}},isAbove(){return this.openDirection==="above"||this.openDirection==="top"?!0:this.openDirection==="below"||this.openDirection==="bottom"?!1:this.preferredOpenDirection==="above"},showSearchInput(){return this.searchable&&(this.hasSingleSelectedSlot&&(this.visibleSingleValue||this.visibleSingleValue===0)?this.isOpen:!0)}}},mv={ref:"tags",class:"multiselect__tags"},gv={class:"multiselect__tags-wrap"},Ev={class:"multiselect__spinner"},_v={key:0},vv={class:"multiselect__option"},yv={class:"multiselect__option"},Nv=rn("No elements found. Consider changing the search query."),bv={class:"multiselect__option"},Ov=rn("List is empty.");function Sv(e,t,n,s,i,r){return He(),Ge("div",{tabindex:e.searchable?-1:n.tabindex,class:[{"multiselect--active":e.isOpen,"multiselect--disabled":n.disabled,"multiselect--above":r.isAbove,"multiselect--has-options-group":r.hasOptionGroup},"multiselect"],onFocus:t[14]||(t[14]=o=>e.activate()),onBlur:t[15]||(t[15]=o=>e.searchable?!1:e.deactivate()),onKeydown:[t[16]||(t[16]=yt(Ce(o=>e.pointerForward(),["self","prevent"]),["down"])),t[17]||(t[17]=yt(Ce(o=>e.pointerBackward(),["self","prevent"]),["up"]))],onKeypress:t[18]||(t[18]=yt(Ce(o=>e.addPointerElement(o),["stop","self"]),["enter","tab"])),onKeyup:t[19]||(t[19]=yt(o=>e.deactivate(),["esc"])),role:"combobox","aria-owns":"listbox-"+e.id},[Be(e.$slots,"caret",{toggle:e.toggle},()=>[ie("div",{onMousedown:t[1]||(t[1]=Ce(o=>e.toggle(),["prevent","stop"])),class:"multiselect__select"},null,32)]),Be(e.$slots,"clear",{search:e.search}),ie("div",mv,[Be(e.$slots,"selection",{search:e.search,remove:e.removeElement,values:r.visibleValues,isOpen:e.isOpen},()=>[Wn(ie("div",gv,[(He(!0),Ge(ye,null,Tr(r.visibleValues,(o,a)=>Be(e.$slots,"tag",{option:o,search:e.search,remove:e.removeElement},()=>[(He(),Ge("span",{class:"multiselect__tag",key:a},[ie("span",{textContent:gt(e.getOptionLabel(o))},null,8,["textContent"]),ie("i",{tabindex:"1",onKeypress:yt(Ce(l=>e.removeElement(o),["prevent"]),["enter"]),onMousedown:Ce(l=>e.removeElement(o),["prevent"]),class:"multiselect__tag-icon"},null,40,["onKeypress","onMousedown"])]))])),256))],512),[[Sn,r.visibleValues.length>0]]),e.internalValue&&e.internalValue.length>n.limit?Be(e.$slots,"limit",{key:0},()=>[ie("strong",{class:"multiselect__strong",textContent:gt(n.limitText(e.internalValue.length-n.limit))},null,8,["textContent"])]):Xt("v-if",!0)]),ie(Lr,{name:"multiselect__loading"},{default:_s(()=>[Be(e.$slots,"loading",{},()=>[Wn(ie("div",Ev,null,512),[[Sn,n.loading]])])]),_:3}),e.searchable?(He(),Ge("input",{key:0,ref:"search",name:n.name,id:e.id,type:"text",autocomplete:"off",spellcheck:n.spellcheck,placeholder:e.placeholder,required:n.required,style:r.inputStyle,value:e.search,disabled:n.disabled,tabindex:n.tabindex,onInput:t[2]||(t[2]=o=>e.updateSearch(o.target.value)),onFocus:t[3]||(t[3]=Ce(o=>e.activate(),["prevent"])),onBlur:t[4]||(t[4]=Ce(o=>e.deactivate(),["prevent"])),onKeyup:t[5]||(t[5]=yt(o=>e.deactivate(),["esc"])),onKeydown:[t[6]||(t[6]=yt(Ce(o=>e.pointerForward(),["prevent"]),["down"])),t[7]||(t[7]=yt(Ce(o=>e.pointerBackward(),["prevent"]),["up"])),t[9]||(t[9]=yt(Ce(o=>e.removeLastElement(),["stop"]),["delete"]))],onKeypress:t[8]||(t[8]=yt(Ce(o=>e.addPointerElement(o),["prevent","stop","self"]),["enter"])),class:"multiselect__input","aria-controls":"listbox-"+e.id},null,44,["name","id","spellcheck","placeholder","required","value","disabled","tabindex","aria-controls"])):Xt("v-if",!0),r.isSingleLabelVisible?(He(),Ge("span",{key:1,class:"multiselect__single",onMousedown:t[10]||(t[10]=Ce((...o)=>e.toggle&&e.toggle(...o),["prevent"]))},[Be(e.$slots,"singleLabel",{option:r.singleValue},()=>[rn(gt(e.currentOptionLabel),1)])],32)):Xt("v-if",!0),r.isPlaceholderVisible?(He(),Ge("span",{key:2,class:"multiselect__placeholder",onMousedown:t[11]||(t[11]=Ce((...o)=>e.toggle&&e.toggle(...o),["prevent"]))},[Be(e.$slots,"placeholder",{},()=>[rn(gt(e.placeholder),1)])],32)):Xt("v-if",!0)],512),ie(Lr,{name:"multiselect"},{default:_s(()=>[Wn(ie("div",{class:"multiselect__content-wrapper",onFocus:t[12]||(t[12]=(...o)=>e.activate&&e.activate(...o)),tabindex:"-1",onMousedown:t[13]||(t[13]=Ce(()=>{},["prevent"])),style:{maxHeight:e.optimizedHeight+"px"},ref:"list"},[ie("ul",{class:"multiselect__content",style:r.contentStyle,role:"listbox",id:"listbox-"+e.id,"aria-multiselectable":e.multiple},[Be(e.$slots,"beforeList"),e.multiple&&e.max===e.internalValue.length?(He(),Ge("li",_v,[ie("span",vv,[Be(e.$slots,"maxElements",{},()=>[rn("Maximum of "+gt(e.max)+" options selected. First remove a selected option to select another.",1)])])])):Xt("v-if",!0),!e.max||e.internalValue.length<e.max?(He(!0),Ge(ye,{key:1},Tr(e.filteredOptions,(o,a)=>(He(),Ge("li",{class:"multiselect__element",key:a,"aria-selected":e.isSelected(o),id:e.id+"-"+a,role:o&&(o.$isLabel||o.$isDisabled)?null:"option"},[o&&(o.$isLabel||o.$isDisabled)?Xt("v-if",!0):(He(),Ge("span",{key:0,class:[e.optionHighlight(a,o),"multiselect__option"],onClick:Ce(l=>e.select(o),["stop"]),onMouseenter:Ce(l=>e.pointerSet(a),["self"]),"data-select":o&&o.isTag?e.tagPlaceholder:r.selectLabelText,"data-selected":r.selectedLabelText,"data-deselect":r.deselectLabelText},[Be(e.$slots,"option",{option:o,search:e.search,index:a},()=>[ie("span",null,gt(e.getOptionLabel(o)),1)])],42,["onClick","onMouseenter","data-select","data-selected","data-deselect"])),o&&(o.$isLabel||o.$isDisabled)?(He(),Ge("span",{key:1,"data-select":e.groupSelect&&r.selectGroupLabelText,"data-deselect":e.groupSelect&&r.deselectGroupLabelText,class:[e.groupHighlight(a,o),"multiselect__option"],onMouseenter:Ce(l=>e.groupSelect&&e.pointerSet(a),["self"]),onMousedown:Ce(l=>e.selectGroup(o),["prevent"])},[Be(e.$slots,"option",{option:o,search:e.search,index:a},()=>[ie("span",null,gt(e.getOptionLabel(o)),1)])],42,["data-select","data-deselect","onMouseenter","onMousedown"])):Xt("v-if",!0)],8,["aria-selected","id","role"]))),128)):Xt("v-if",!0),Wn(ie("li",null,[ie("span",yv,[Be(e.$slots,"noResult",{search:e.search},()=>[Nv])])],512),[[Sn,n.showNoResults&&e.filteredOptions.length===0&&e.search&&!n.loading]]),Wn(ie("li",null,[ie("span",bv,[Be(e.$slots,"noOptions",{},()=>[Ov])])],512),[[Sn,n.showNoOptions&&(e.options.length===0||r.hasOptionGroup===!0&&e.filteredOptions.length===0)&&!e.search&&!n.loading]]),Be(e.$slots,"afterList")],12,["id","aria-multiselectable"])],36),[[Sn,e.isOpen]])]),_:3})],42,["tabindex","aria-owns"])}dd.render=Sv;var hd=dd;var wd=zl(vd()),Td=zl(Nd());function bd(e,t){return e.indexOf(t,e.length-t.length)!==-1}var xv={"&":"&amp;","<":"&lt;",">":"&gt;","/":"&#x2F;"};function Od(e){return String(e).replace(/[&<>\/]/g,function(t){return xv[t]})}var Sd={template:'<div class="log-view"></div>',props:["linesOfHistory"],setup(){let e=Ra("logview")},data:function(){return{history:[],lastSpan:null,lastSpanClasses:"",autoScroll:!0}},watch:{linesOfHistory:function(e){this.trimHistory()}},methods:{clearLines:function(){this.$el.innerHTML="",this.history=[],this.lastSpan=null},toggleWrapLines:function(e){this.$el.classList.toggle("log-view-wrapped",e)},createSpan:function(e,t){var n=document.createElement("span");return n.innerHTML=e,n.className=t,n},createLogEntrySpan:function(e){return this.createSpan(e,"log-entry")},createNoticePan:function(e){return createSpan(e,"log-entry log-notice")},trimHistory:function(){if(this.linesOfHistory!==0&&this.history.length>this.linesOfHistory)for(var e=0;e<this.history.length-this.linesOfHistory+1;e++)this.$el.removeChild(this.history.shift())},isScrolledToBottom:function(){var e=this.$el.parentElement,t=e.scrollTop-(e.scrollHeight-e.offsetHeight);return Math.abs(t)<50},scroll:function(){this.$el.parentElement.scrollTop=this.$el.parentElement.scrollHeight},write:function(e,t){var n;e==="o"&&(t=Od(t).replace(/\n$/,""),n=this.createLogEntrySpan(t),this.writeSpans([n]))},writeSpans:function(e){if(e.length!==0){for(var t=this.isScrolledToBottom(),n=document.createDocumentFragment(),s=0;s<e.length;s++){var i=e[s];this.history.push(i),n.appendChild(i)}this.lastSpan&&(this.lastSpan.className=this.lastSpanClasses),this.$el.appendChild(n),this.trimHistory(),this.autoScroll&&t&&this.scroll(),this.lastSpan=this.history[this.history.length-1],this.lastSpanClasses=this.lastSpan.className,this.lastSpan.className=this.lastSpanClasses+" log-entry-current"}}}};var bo=bd(window.relativeRoot,"/")?"ws":"/ws",bo=[window.location.protocol,"//",window.location.host,window.relativeRoot,bo].join(""),Oo=mi({delimiters:["<%","%>"],data(){return{relativeRoot,commandScripts,fileList:[],fileGroups:{},allowCommandNames,allowDownload,file:null,command:null,script:null,linesOfHistory:2e3,linesToTail:tailLinesInitial,wrapLines:wrapLinesInitial,hideToolbar:!1,showConfig:!1,showLoadingOverlay:!1,socket:null,isConnected:!1}},created(){this.backendConnect(),this.command=this.allowCommandNames[0]},mounted(){this.$refs.logview.toggleWrapLines(this.wrapLines)},computed:{scriptInputEnabled:function(){return this.commandScripts[this.command]!==""},downloadLink:function(){return this.file?relativeRoot+"files/?path="+this.file.path:"#"},downloadFileName:function(){return this.file?this.file.path.split("/").at(-1):"#"}},methods:{clearLogview:function(){this.$refs.logview.clearLines()},backendConnect:function(){console.log("connecting to "+bo),this.showLoadingOverlay=!0,this.socket=new Td.default(bo),this.socket.onopen=this.onBackendOpen,this.socket.onclose=this.onBackendClose,this.socket.onmessage=this.onBackendMessage},onBackendOpen:function(){console.log("connected to backend"),this.isConnected=!0,this.refreshFiles(),this.socket.send("watch")},onBackendClose:function(){console.log("disconnected from backend"),this.isConnected=!1,backendConnect=this.backendConnect,window.setTimeout(function(){backendConnect()},1e3)},onBackendMessage:function(e){var t=JSON.parse(e.data);if(t.constructor===Object&&t.type==="listing-changed")this.applyListingChanges(t.changes),this.updateFileList();else if(t.constructor===Object&&t.type==="commands-changed")this.allowCommandNames=t.commands,this.commandScripts=t.scripts,this.allowCommandNames.includes(this.command)||(this.command=this.allowCommandNames[0]);else if(t.constructor===Object)this.fileGroups=t,this.updateFileList();else{var n=t[0],s=t[1];this.$refs.logview.write(n,s)}},applyListingChanges:function(e){var t=this.fileGroups;e.forEach(function(n){var s=t[n.group]||[],i=s.findIndex(function(r){return r.path===n.entry.path&&r.alias===n.entry.alias});n.op==="added"&&i===-1?s.push(n.entry):n.op==="removed"&&i!==-1?s.splice(i,1):n.op==="changed"&&i!==-1&&Object.assign(s[i],n.entry),t[n.group]=s})},updateFileList:function(){var e=this.fileGroups,t=[];Object.keys(e).forEach(function(n){if(e[n].length!==0){var s=n==="__default__"?"Ungrouped Files":n;t.push({group:s,files:e[n]})}}),this.fileList=t,!this.file&&t.length>0&&(this.file=t[0].files[0])},refreshFiles:function(){console.log("updating file list"),this.socket.send("list")},notifyBackend:function(){var e={command:this.command,script:this.script,entry:this.file,nlines:this.linesToTail};console.log("sending msg: ",e),this.clearLogview(),this.socket.send(JSON.stringify(e))}},watch:{isConnected:function(e){this.showLoadingOverlay=!e},wrapLines:function(e){this.$refs.logview.toggleWrapLines(e)},command:function(e){e&&this.isConnected&&(this.script=this.commandScripts[e],this.notifyBackend())},file:function(e){e&&this.isConnected&&this.notifyBackend()}}});Oo.component("multiselect",hd);Oo.component("logview",Sd);Oo.component("loading",wd.default);Oo.mount("#app");})();
 /*! Bundled license information:
 
 @vue/shared/dist/shared.esm-bundler.js:
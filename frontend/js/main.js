@@ -17,6 +17,7 @@ const app = createApp({
             commandScripts: commandScripts,
 
             fileList: [],
+            fileGroups: {},
             allowCommandNames: allowCommandNames,
             allowDownload: allowDownload,
 
@@ -25,8 +26,8 @@ const app = createApp({
             script: null,
 
             linesOfHistory: 2000, // 0 for infinite history
-            linesToTail: 10,
-            wrapLines: false,
+            linesToTail: tailLinesInitial,
+            wrapLines: wrapLinesInitial,
 
             hideToolbar: false,
             showConfig: false,
@@ -40,6 +41,9 @@ const app = createApp({
         this.backendConnect();
         this.command = this.allowCommandNames[0];
     },
+    mounted() {
+        this.$refs.logview.toggleWrapLines(this.wrapLines);
+    },
     computed: {
         scriptInputEnabled: function () {
             return this.commandScripts[this.command] !== "";
@@ -73,6 +77,7 @@ const app = createApp({
             console.log("connected to backend");
             this.isConnected = true;
             this.refreshFiles();
+            this.socket.send("watch");
         },
         onBackendClose: function () {
             console.log("disconnected from backend");
@@ -85,29 +90,65 @@ const app = createApp({
         onBackendMessage: function (message) {
             var data = JSON.parse(message.data);
 
-            if (data.constructor === Object) {
-                // Reshape into something that vue-multiselect :group-select can use.
-                var fileList = [];
-                Object.keys(data).forEach(function (key) {
-                    var group = "__default__" === key ? "Ungrouped Files" : key;
-                    fileList.push({
-                        group: group,
-                        files: data[key],
-                    });
-                });
-
-                this.fileList = fileList;
-
-                // Set file input to first entry in list.
-                if (!this.file) {
-                    this.file = fileList[0].files[0];
+            if (data.constructor === Object && data.type === "listing-changed") {
+                this.applyListingChanges(data.changes);
+                this.updateFileList();
+            } else if (data.constructor === Object && data.type === "commands-changed") {
+                this.allowCommandNames = data.commands;
+                this.commandScripts = data.scripts;
+                if (!this.allowCommandNames.includes(this.command)) {
+                    this.command = this.allowCommandNames[0];
                 }
+            } else if (data.constructor === Object) {
+                this.fileGroups = data;
+                this.updateFileList();
             } else {
                 var stream = data[0];
                 var line = data[1];
                 this.$refs.logview.write(stream, line);
             }
         },
+        applyListingChanges: function (changes) {
+            var groups = this.fileGroups;
+            changes.forEach(function (change) {
+                var entries = groups[change.group] || [];
+                var index = entries.findIndex(function (entry) {
+                    return entry.path === change.entry.path && entry.alias === change.entry.alias;
+                });
+
+                if (change.op === "added" && index === -1) {
+                    entries.push(change.entry);
+                } else if (change.op === "removed" && index !== -1) {
+                    entries.splice(index, 1);
+                } else if (change.op === "changed" && index !== -1) {
+                    // Update in place, so that the selected file stays selected.
+                    Object.assign(entries[index], change.entry);
+                }
+                groups[change.group] = entries;
+            });
+        },
+        updateFileList: function () {
+            // Reshape into something that vue-multiselect :group-select can use.
+            var groups = this.fileGroups;
+            var fileList = [];
+            Object.keys(groups).forEach(function (key) {
+                if (groups[key].length === 0) {
+                    return;
+                }
+                var group = "__default__" === key ? "Ungrouped Files" : key;
+                fileList.push({
+                    group: group,
+                    files: groups[key],
+                });
+            });
+
+            this.fileList = fileList;
+
+            // Set file input to first entry in list.
+            if (!this.file && fileList.length > 0) {
+                this.file = fileList[0].files[0];
+            }
+        },
         refreshFiles: function () {
             console.log("updating file list");
             this.socket.send("list");
@@ -3,18 +3,19 @@ module github.com/gvalkov/tailon
 go 1.23.2
 
 require (
-	github.com/gorilla/handlers v1.5.2
+	github.com/fsnotify/fsnotify v1.8.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/igm/sockjs-go/v3 v3.0.3
 	github.com/mitchellh/mapstructure v1.5.0
 	github.com/pelletier/go-toml v1.9.5
 	github.com/shurcooL/httpfs v0.0.0-20230704072500-f1e31cf0ba5c
 	github.com/shurcooL/httpgzip v0.0.0-20230704072819-d1585fc322fa
 	github.com/spf13/pflag v1.0.5
+	golang.org/x/crypto v0.28.0
+	golang.org/x/sys v0.26.0
 )
 
 require (
-	github.com/felixge/httpsnoop v1.0.3 // indirect
-	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/shurcooL/vfsgen v0.0.0-20230704071429-0000e147ea92 // indirect
 	golang.org/x/net v0.30.0 // indirect
 	golang.org/x/text v0.19.0 // indirect
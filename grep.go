@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The name of the builtin action that filters lines with a Go regular
+// expression instead of running grep. It must read its stdin from another
+// command, for example:
+//
+//	[commands.grep]
+//	stdin = "tail"
+//	action = ["@grep", "-i", "-C", "2", "-e", "$script"]
+const builtinGrep = "@grep"
+
+// grepFilter is an in-process implementation of "grep --line-buffered". The
+// pattern uses the RE2 syntax of the regexp package, which is close to that
+// of "grep -E".
+type grepFilter struct {
+	pattern *regexp.Regexp
+	invert  bool
+	before  int
+	after   int
+}
+
+// Parse the arguments of a "@grep" action into a filter. The supported
+// options are a subset of those of grep:
+//
+//	-i, --ignore-case    ignore case distinctions in the pattern
+//	-v, --invert-match   select non-matching lines
+//	-A N                 print N lines of trailing context
+//	-B N                 print N lines of leading context
+//	-C N                 print N lines of leading and trailing context
+//	-e PATTERN           use PATTERN for matching
+//
+// The pattern can also be given as the only positional argument.
+func parseGrepArgs(args []string) (*grepFilter, error) {
+	filter := &grepFilter{}
+	ignoreCase := false
+	var pattern *string
+
+	context := func(n int, arg string) (int, error) {
+		if n >= len(args) {
+			return 0, fmt.Errorf("option requires an argument -- '%s'", strings.TrimLeft(arg, "-"))
+		}
+		value, err := strconv.Atoi(args[n])
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("%s: invalid context length argument", args[n])
+		}
+		return value, nil
+	}
+
+	for n := 0; n < len(args); n++ {
+		var err error
+		switch arg := args[n]; arg {
+		case "-i", "--ignore-case":
+			ignoreCase = true
+		case "-v", "--invert-match":
+			filter.invert = true
+		case "-A":
+			n++
+			filter.after, err = context(n, arg)
+		case "-B":
+			n++
+			filter.before, err = context(n, arg)
+		case "-C":
+			n++
+			filter.after, err = context(n, arg)
+			filter.before = filter.after
+		case "-e":
+			n++
+			if n >= len(args) {
+				return nil, fmt.Errorf("option requires an argument -- 'e'")
+			}
+			pattern = &args[n]
+		default:
+			if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+				return nil, fmt.Errorf("invalid option -- '%s'", strings.TrimLeft(arg, "-"))
+			}
+			if pattern != nil {
+				return nil, fmt.Errorf("unexpected argument '%s'", arg)
+			}
+			pattern = &args[n]
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if pattern == nil {
+		return nil, fmt.Errorf("no pattern given")
+	}
+
+	expr := *pattern
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	filter.pattern = re
+	return filter, nil
+}
+
+func (g *grepFilter) match(line string) bool {
+	return g.pattern.MatchString(line) != g.invert
+}
+
+// Filter lines from in to out until in is closed, after which out is closed.
+// Like grep, non-adjacent groups of context lines are separated by "--".
+func (g *grepFilter) run(in <-chan string, out chan<- string) {
+	defer close(out)
+
+	var before []string
+	afterLeft := 0
+	lineno, lastPrinted := 0, 0
+
+	for line := range in {
+		lineno++
+
+		if g.match(line) {
+			first := lineno - len(before)
+			if (g.before > 0 || g.after > 0) && lastPrinted > 0 && first > lastPrinted+1 {
+				out <- "--"
+			}
+			for _, line := range before {
+				out <- line
+			}
+			before = before[:0]
+
+			out <- line
+			lastPrinted = lineno
+			afterLeft = g.after
+		} else if afterLeft > 0 {
+			out <- line
+			lastPrinted = lineno
+			afterLeft--
+		} else if g.before > 0 {
+			before = append(before, line)
+			if len(before) > g.before {
+				before = before[1:]
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func runGrep(t *testing.T, args []string, input string) string {
+	filter, err := parseGrepArgs(args)
+	if err != nil {
+		t.Fatalf("%q: %s", args, err)
+	}
+
+	in := make(chan string)
+	out := make(chan string)
+	go func() {
+		for _, line := range strings.Split(input, " ") {
+			in <- line
+		}
+		close(in)
+	}()
+	go filter.run(in, out)
+
+	var res []string
+	for line := range out {
+		res = append(res, line)
+	}
+	return strings.Join(res, " ")
+}
+
+func TestGrepFilter(t *testing.T) {
+	input := "a1 b2 a3 b4 b5 b6 b7 a8 b9"
+	tests := []struct {
+		args   []string
+		expect string
+	}{
+		{[]string{"a"}, "a1 a3 a8"},
+		{[]string{"-e", "^b[0-5]$"}, "b2 b4 b5"},
+		{[]string{"-v", "a"}, "b2 b4 b5 b6 b7 b9"},
+		{[]string{"-i", "A"}, "a1 a3 a8"},
+		{[]string{"-A", "1", "a"}, "a1 b2 a3 b4 -- a8 b9"},
+		{[]string{"-B", "1", "a8"}, "b7 a8"},
+		{[]string{"-C", "1", "a"}, "a1 b2 a3 b4 -- b7 a8 b9"},
+		{[]string{"-e", ".*"}, input},
+	}
+
+	for _, test := range tests {
+		if res := runGrep(t, test.args, input); res != test.expect {
+			t.Fatalf("%q: %q != %q", test.args, res, test.expect)
+		}
+	}
+
+	for _, args := range [][]string{{}, {"-e"}, {"-C", "x", "a"}, {"-x", "a"}, {"a", "b"}, {"("}} {
+		if _, err := parseGrepArgs(args); err == nil {
+			t.Fatalf("expected error for %q", args)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gvalkov/tailon/cmd"
+)
+
+// limitEvent reports that a command exceeded one of its resource limits.
+type limitEvent struct {
+	Argv    []string `json:"argv"`
+	Limit   string   `json:"limit"`
+	Message string   `json:"message"`
+}
+
+// Limits that are reported in addition to cmd.LimitTimeout and
+// cmd.LimitCPUTime. The memory limit is not reported, since a command that
+// exceeds it fails like any command that runs out of memory.
+const (
+	limitLinesPerSecond = "max-lines-per-second"
+	limitBytesPerSecond = "max-bytes-per-second"
+)
+
+// Parse the resource limits of a command spec.
+func (spec *CommandSpec) parseLimits() error {
+	var err error
+	limits := cmd.Limits{Nice: spec.Nice}
+
+	if spec.Timeout != "" {
+		if limits.Timeout, err = time.ParseDuration(spec.Timeout); err != nil || limits.Timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q", spec.Timeout)
+		}
+	}
+	if spec.CPUTime != "" {
+		if limits.CPUTime, err = time.ParseDuration(spec.CPUTime); err != nil || limits.CPUTime <= 0 {
+			return fmt.Errorf("invalid cpu-time %q", spec.CPUTime)
+		}
+	}
+	if spec.Memory != "" {
+		if limits.Memory, err = parseSize(spec.Memory); err != nil {
+			return fmt.Errorf("invalid memory %q", spec.Memory)
+		}
+	}
+	if spec.Nice < -20 || spec.Nice > 19 {
+		return fmt.Errorf("invalid nice %d (expected -20 to 19)", spec.Nice)
+	}
+	if spec.IONice != "" {
+		if limits.IOClass, limits.IOLevel, err = parseIONice(spec.IONice); err != nil {
+			return err
+		}
+	}
+	if spec.MaxLinesPerSecond < 0 || spec.MaxBytesPerSecond < 0 {
+		return fmt.Errorf("max-lines-per-second and max-bytes-per-second cannot be negative")
+	}
+
+	if limits != (cmd.Limits{}) || spec.MaxLinesPerSecond > 0 || spec.MaxBytesPerSecond > 0 {
+		if len(spec.Action) > 0 && (spec.Action[0] == builtinTail || spec.Action[0] == builtinGrep) {
+			return fmt.Errorf("resource limits cannot be set for %s", spec.Action[0])
+		}
+	}
+
+	spec.limits = limits
+	return nil
+}
+
+// Parse a size in bytes with an optional K, M, G or T suffix (powers of 1024),
+// which can be followed by "B" or "iB". For example: "512M", "1GiB", "4096".
+func parseSize(value string) (uint64, error) {
+	number := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(value), "B"), "I")
+	shift := 0
+	if n := len(number); n > 0 {
+		if i := strings.IndexByte("KMGT", number[n-1]); i >= 0 {
+			number, shift = number[:n-1], 10*(i+1)
+		}
+	}
+	size, err := strconv.ParseUint(number, 10, 64)
+	if err != nil || size == 0 || size > (1<<64-1)>>shift {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return size << shift, nil
+}
+
+// The I/O scheduling classes of the ionice option.
+var ioClasses = map[string]int{
+	"realtime":    cmd.IOClassRealtime,
+	"best-effort": cmd.IOClassBestEffort,
+	"idle":        cmd.IOClassIdle,
+}
+
+// Parse an I/O scheduling class with an optional priority, such as "idle" or
+// "best-effort:7". The priority defaults to 4, as with ionice(1).
+func parseIONice(value string) (class, level int, err error) {
+	name, prio, hasPrio := strings.Cut(value, ":")
+	class, ok := ioClasses[name]
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid ionice %q (expected realtime, best-effort or idle)", value)
+	}
+
+	level = 4
+	if hasPrio {
+		level, err = strconv.Atoi(prio)
+		if err != nil || level < 0 || level > 7 || class == cmd.IOClassIdle {
+			return 0, 0, fmt.Errorf("invalid ionice priority %q (expected 0 to 7)", prio)
+		}
+	}
+	if class == cmd.IOClassIdle {
+		level = 0
+	}
+	return class, level, nil
+}
+
+// --------------------------------------------------------------------------
+
+// outputRate limits the lines and bytes per second that a command outputs.
+// Zero means no limit.
+type outputRate struct {
+	lines int
+	bytes int
+
+	// The current one-second window and what was output in it.
+	start  time.Time
+	nlines int
+	nbytes int
+}
+
+// Count a line of size bytes and return how long to wait before it can be
+// output, along with the limit that was exceeded.
+func (r *outputRate) take(size int) (time.Duration, string) {
+	if r.lines == 0 && r.bytes == 0 {
+		return 0, ""
+	}
+
+	now := time.Now()
+	if now.Sub(r.start) >= time.Second {
+		r.start, r.nlines, r.nbytes = now, 0, 0
+	}
+	r.nlines++
+	r.nbytes += size
+
+	limit := ""
+	switch {
+	case r.lines > 0 && r.nlines > r.lines:
+		limit = limitLinesPerSecond
+	case r.bytes > 0 && r.nbytes > r.bytes && r.nlines > 1:
+		limit = limitBytesPerSecond
+	default:
+		return 0, ""
+	}
+
+	// The line is the first of the next window.
+	wait := r.start.Add(time.Second).Sub(now)
+	r.start, r.nlines, r.nbytes = r.start.Add(time.Second), 1, size
+	return wait, limit
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gvalkov/tailon/cmd"
+)
+
+func TestParseLimits(t *testing.T) {
+	sizes := map[string]uint64{"4096": 4096, "1k": 1 << 10, "512M": 512 << 20, "1GiB": 1 << 30, "2TB": 2 << 40}
+	for value, expect := range sizes {
+		if size, err := parseSize(value); err != nil || size != expect {
+			t.Fatalf("%s: %d %v != %d", value, size, err, expect)
+		}
+	}
+	for _, value := range []string{"", "M", "0", "-1", "1.5G", "1P", "99999999999T"} {
+		if _, err := parseSize(value); err == nil {
+			t.Fatalf("%s: expected an error", value)
+		}
+	}
+
+	spec := CommandSpec{
+		Action:            []string{"awk", "$script"},
+		Timeout:           "1h",
+		CPUTime:           "90s",
+		Memory:            "256M",
+		Nice:              10,
+		IONice:            "best-effort:7",
+		MaxLinesPerSecond: 100,
+	}
+	if err := spec.parseLimits(); err != nil {
+		t.Fatal(err)
+	}
+	expect := cmd.Limits{
+		Timeout: time.Hour,
+		CPUTime: 90 * time.Second,
+		Memory:  256 << 20,
+		Nice:    10,
+		IOClass: cmd.IOClassBestEffort,
+		IOLevel: 7,
+	}
+	if spec.limits != expect {
+		t.Fatalf("%+v != %+v", spec.limits, expect)
+	}
+
+	errors := map[string]CommandSpec{
+		`invalid timeout "1"`:                                            {Timeout: "1"},
+		`invalid cpu-time "-1s"`:                                         {CPUTime: "-1s"},
+		`invalid memory "lots"`:                                          {Memory: "lots"},
+		`invalid nice 20 (expected -20 to 19)`:                           {Nice: 20},
+		`invalid ionice "slow" (expected realtime, best-effort or idle)`: {IONice: "slow"},
+		`invalid ionice priority "8" (expected 0 to 7)`:                  {IONice: "realtime:8"},
+		`invalid ionice priority "1" (expected 0 to 7)`:                  {IONice: "idle:1"},
+		`resource limits cannot be set for @grep`:                        {Action: []string{"@grep"}, MaxLinesPerSecond: 1},
+	}
+	for expect, spec := range errors {
+		if err := spec.parseLimits(); err == nil || err.Error() != expect {
+			t.Fatalf("%v != %s", err, expect)
+		}
+	}
+}
+
+func TestOutputRate(t *testing.T) {
+	rate := outputRate{lines: 3}
+	for n := 0; n < 3; n++ {
+		if wait, limit := rate.take(10); wait != 0 || limit != "" {
+			t.Fatalf("line %d was limited", n)
+		}
+	}
+	if wait, limit := rate.take(10); wait <= 0 || wait > time.Second || limit != limitLinesPerSecond {
+		t.Fatalf("%s %s", wait, limit)
+	}
+
+	// A single line that is larger than the byte limit is not held back.
+	rate = outputRate{bytes: 100}
+	if wait, _ := rate.take(200); wait != 0 {
+		t.Fatalf("%s", wait)
+	}
+	if wait, limit := rate.take(10); wait <= 0 || limit != limitBytesPerSecond {
+		t.Fatalf("%s %s", wait, limit)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/igm/sockjs-go/v3/sockjs"
+)
+
+// The values of the log-level and log-format options.
+var (
+	logLevels  = []string{"debug", "info", "warn", "error"}
+	logFormats = []string{"text", "json"}
+)
+
+// Create a logger from the logging options of a config. The log file can also
+// be "stdout" or "stderr".
+func newLogger(config *Config) (*slog.Logger, error) {
+	var level slog.Level
+	if !slices.Contains(logLevels, config.LogLevel) || level.UnmarshalText([]byte(config.LogLevel)) != nil {
+		return nil, fmt.Errorf("invalid log level %q (expected %s)", config.LogLevel, strings.Join(logLevels, ", "))
+	}
+	if !slices.Contains(logFormats, config.LogFormat) {
+		return nil, fmt.Errorf("invalid log format %q (expected %s)", config.LogFormat, strings.Join(logFormats, ", "))
+	}
+
+	var out io.Writer
+	switch config.LogFile {
+	case "stdout":
+		out = os.Stdout
+	case "stderr", "":
+		out = os.Stderr
+	default:
+		file, err := os.OpenFile(config.LogFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+		if err != nil {
+			return nil, err
+		}
+		out = file
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	if config.LogFormat == "json" {
+		return slog.New(slog.NewJSONHandler(out, options)), nil
+	}
+	return slog.New(slog.NewTextHandler(out, options)), nil
+}
+
+// --------------------------------------------------------------------------
+
+type loggerKey struct{}
+
+// The logger of a request, which adds its id to every line.
+func requestLogger(r *http.Request) *slog.Logger {
+	if logger, ok := r.Context().Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// The logger of a websocket session, which adds the session id to the lines
+// of the request logger.
+func sessionLogger(session sockjs.Session) *slog.Logger {
+	return requestLogger(session.Request()).With("session", session.ID())
+}
+
+// logHandler gives every request an id and a logger, and logs the request once
+// it is served.
+func logHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		logger := slog.Default().With("request", fmt.Sprintf("%016x", rand.Uint64()), "remote", r.RemoteAddr)
+		counter := &countingWriter{ResponseWriter: w, code: http.StatusOK}
+
+		next.ServeHTTP(counter, r.WithContext(context.WithValue(r.Context(), loggerKey{}, logger)))
+
+		logger.Info("Request",
+			"method", r.Method,
+			"uri", r.URL.RequestURI(),
+			"status", counter.code,
+			"bytes", counter.n,
+			"duration", time.Since(start))
+	})
+}
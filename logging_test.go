@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogHandler(t *testing.T) {
+	var out bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&out, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	handler := logHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestLogger(r).Info("Handling")
+		http.Error(w, "teapot", http.StatusTeapot)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/files/?path=x", nil))
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, fields)
+	}
+	if len(lines) != 2 || lines[0]["msg"] != "Handling" || lines[1]["msg"] != "Request" {
+		t.Fatal(lines)
+	}
+	if id := lines[0]["request"]; id == nil || id != lines[1]["request"] {
+		t.Fatal("request ids differ:", lines)
+	}
+	if lines[1]["status"] != float64(http.StatusTeapot) || lines[1]["uri"] != "/files/?path=x" {
+		t.Fatal(lines[1])
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	if _, err := newLogger(&Config{LogLevel: "debug", LogFormat: "json", LogFile: "stdout"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, config := range []Config{
+		{LogLevel: "verbose", LogFormat: "text"},
+		{LogLevel: "info", LogFormat: "xml"},
+		{LogLevel: "info", LogFormat: "text", LogFile: "/nonexistent/tailon.log"},
+	} {
+		if _, err := newLogger(&config); err == nil {
+			t.Fatalf("%+v: expected an error", config)
+		}
+	}
+}
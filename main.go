@@ -2,27 +2,48 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"github.com/gvalkov/tailon/cmd"
+	"github.com/gvalkov/tailon/tail"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pelletier/go-toml"
 	flag "github.com/spf13/pflag"
-	"io/ioutil"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
-	"sync"
+	"syscall"
 )
 
 const scriptDescription = `
 Usage: tailon -c <config file>
 Usage: tailon [options] <filespec> [<filespec> ...]
+Usage: tailon check [options] [<filespec> ...]
 
 Tailon is a webapp for searching through files and streams.
 `
 
 const scriptEpilog = `
-Tailon can be configured via a TOML config file or command-line flags.
+Tailon can be configured via TOML config files, TAILON_* environment
+variables and command-line flags. Each overrides the ones before it: the
+built-in defaults, the config files in the order they are given with "-c",
+the environment and the flags. The environment variable of an option is its
+name in upper case with underscores, such as TAILON_LISTEN_ADDR or
+TAILON_TAIL_LINES. Arrays are given as comma-separated values. Use
+"--print-config" to see the resulting configuration.
+
+On SIGHUP, tailon reads the config files again and applies them without
+dropping connected clients: files, commands, access control, limits and
+listeners are updated, and streams whose file or command was removed are
+stopped. The relative root, refresh interval, logging and audit settings
+still need a restart. A config with errors is logged and not applied.
 
 The command-line interface expects one or more <filespec> arguments, which
 specify the files to serve. The format is:
@@ -44,14 +65,33 @@ specifier overwrites the parent directory of each matched file in the UI.
 
   tailon "/var/log/apache/*.log" "alias=nginx,/var/log/nginx/*.log"
 
-If a directory is given, all files under it are served recursively.
+If a directory is given, all files under it are served recursively. An
+"alias=" specifier replaces the directory in the display name of each file.
+Directory filespecs also accept the following specifiers:
+
+  maxdepth=N        descend at most N levels (1 serves only the top level)
+  symlinks=policy   one of "files" (default), "follow" or "skip"
+  include=pattern   serve only files matching a shell pattern (repeatable)
+  exclude=pattern   skip files and directories matching a pattern (repeatable)
+
+Patterns are matched against both the base name and the path relative to the
+directory.
 
   tailon /var/log/apache/ /var/log/nginx/
+  tailon "maxdepth=2,include=*.log,exclude=*.gz,/var/log/"
 
 Example usage:
   tailon file1.txt file2.txt file3.txt
   tailon alias=messages,/var/log/messages "/var/log/*.log"
   tailon -b localhost:8080,localhost:8081 -c config.toml
+  tailon -c /etc/tailon/tailon.toml -c local.toml --print-config
+
+"tailon check" loads the configuration like tailon would, without serving it.
+It resolves the binaries of the allowed commands, checks their placeholders
+and stdin chains, expands the filespecs and prints what would be served. It
+exits with a non-zero status if there are errors.
+
+  tailon check -c /etc/tailon/tailon.toml
 
 See "--help-config" for configuration file usage.
 `
@@ -59,6 +99,13 @@ See "--help-config" for configuration file usage.
 const configFileHelp = `
 The following options can be set through the config file:
 
+  # Other config files to read after this one, as glob patterns relative to
+  # the directory of this file. Files matched by a pattern are read in lexical
+  # order. Commands are added to the existing ones, and so are [files] and
+  # [[listen]] tables; other keys and tables replace the earlier values. A
+  # file that is included several times is read only once.
+  include = ["conf.d/*.toml"]
+
   # The <title> element of the of the webapp.
   title = "Tailon file viewer"
 
@@ -68,18 +115,191 @@ The following options can be set through the config file:
   # The addresses to listen on. Can be an address:port combination or an unix socket.
   listen-addr = [":8080"]
 
+  # Additional addresses to listen on, with optional TLS. Certificates are
+  # reloaded on SIGHUP and when the files change. If client-ca-file is set,
+  # clients must present a certificate signed by one of the CAs in it
+  # (client-auth = "require", the default) or may present one ("request").
+  [[listen]]
+  addr = ":8443"
+  cert-file = "/etc/tailon/cert.pem"
+  key-file = "/etc/tailon/key.pem"
+  min-version = "1.2"
+  client-ca-file = "/etc/tailon/clients.pem"
+  client-auth = "require"
+
   # Allow downloading of known files (i.e those matched by a filespec).
   allow-download = true
 
+  # Serve Prometheus metrics on <relative-root>metrics.
+  metrics = false
+
+  # Reload the config when one of the config files changes, as on SIGHUP.
+  watch-config = false
+
+  # The server log: the minimum level ("debug", "info", "warn" or "error"),
+  # the format ("text" or "json") and where it is written ("stderr", "stdout"
+  # or a file). Every line about a request or a websocket session carries its
+  # request and session id.
+  log-level = "info"
+  log-format = "text"
+  log-file = "stderr"
+
   # Commands that will appear in the UI.
   allow-commands = ["tail", "grep", "sed", "awk"]
 
+  # Whether long lines are wrapped and how many lines are tailed when the UI
+  # is opened.
+  wrap-lines = false
+  tail-lines = 10
+
+  # How often globs and directories are expanded again and the size and
+  # modification time of files are updated. The directories of all filespecs
+  # are also watched for changes, which are pushed to connected clients.
+  refresh-interval = "10s"
+
+  # The number of lines that can be queued for a client that cannot keep up
+  # with the output of its commands. When the queue is full, the oldest lines
+  # are dropped ("drop-oldest") or the commands are paused until the client
+  # catches up ("pause"). Clients of the typed websocket protocol receive up
+  # to send-batch-size lines per message.
+  send-queue-size = 1000
+  send-queue-policy = "drop-oldest"
+  send-batch-size = 100
+
+  # Limits on websocket sessions and on running commands (a merged stream
+  # runs one per file), in total and per client address. A value of 0 means
+  # no limit. The per-client limits are off by default, since behind a
+  # reverse proxy all clients share the address of the proxy. With an
+  # idle-timeout, which is also off by default ("0s"), the commands of a
+  # client that does not read their output for that long are stopped and its
+  # session is closed.
+  max-sessions = 100
+  max-sessions-per-client = 20
+  max-commands = 500
+  max-commands-per-client = 100
+  idle-timeout = "5m"
+
+  # Authentication for all routes, including the websocket and downloads. A
+  # request is accepted if any of the configured methods succeeds. There is no
+  # authentication if this table is missing.
+  [auth]
+
+    # HTTP basic auth against bcrypt password hashes, given inline or in a
+    # htpasswd file (e.g. created with "htpasswd -B").
+    [auth.basic]
+    realm = "tailon"
+    htpasswd = "/etc/tailon/htpasswd"
+    users = { alice = "$2y$10$..." }
+
+    # Static "Authorization: Bearer <token>" tokens. The key is the user name.
+    [auth.token]
+    tokens = { ci = "a-long-random-token" }
+
+    # The user name (and optionally a comma-separated list of groups) set by a
+    # reverse proxy. Headers are only trusted from these networks and from
+    # connections through a unix socket.
+    [auth.proxy]
+    header = "X-Forwarded-User"
+    groups-header = "X-Forwarded-Groups"
+    trusted = ["127.0.0.1/32", "::1/128"]
+
+    # The verified client certificate of a mutual TLS listener. The common
+    # name is the user name and the organizational units are the groups.
+    [auth.client-cert]
+
+  # Log every command that is run and every download as a line of JSON, with
+  # the time, the remote address, the user, the command, its arguments, the
+  # file, and how long the command ran and how it exited. The file is rotated
+  # when it reaches max-size, keeping max-backups old files (file.1, file.2).
+  [audit]
+  file = "/var/log/tailon/audit.log"
+  max-size = "100M"
+  max-backups = 5
+
+  # Group memberships, in addition to the groups that come from a reverse
+  # proxy or a client certificate.
+  [groups]
+  ops = ["alice", "bob"]
+
+  # Access control. If there are any [[acl]] rules, users can only see the
+  # filespec groups ("__default__" for ungrouped files) and run the commands
+  # granted to them or to one of their groups. "*" matches anything.
+  [[acl]]
+  groups = ["ops"]
+  files = ["*"]
+  commands = ["*"]
+
+  [[acl]]
+  users = ["*"]
+  files = ["web"]
+  commands = ["tail"]
+
   # A table of commands that the backend can execute. This is best illustrated by
   # the default configuration listed below.
   [commands]
 
+  # The builtin "@tail" action follows files without starting a "tail -F"
+  # process. All sessions that follow the same file share a single reader.
+  # It takes the file and the number of lines from the UI.
+  [commands.tail]
+  action = ["@tail"]
+
+  # The builtin "@grep" action filters lines with a Go regular expression
+  # instead of running grep. It understands the -i, -v, -A, -B, -C and -e
+  # options of grep. Together with "@tail", it needs no external binaries.
+  [commands.grep]
+  stdin = "tail"
+  action = ["@grep", "-i", "-e", "$script"]
+  default = ".*"
+
+  # Scripts come from the browser, so external commands can be limited. The
+  # command is stopped after the timeout and killed after using cpu-time of
+  # CPU. Memory limits the address space (K, M, G or T suffix). Nice and
+  # ionice ("realtime", "best-effort" or "idle", with an optional ":0" to
+  # ":7" priority) apply to the command and its children. Output beyond
+  # max-lines-per-second or max-bytes-per-second is held back, which slows
+  # down the command. Clients are told when a limit is exceeded.
+  [commands.awk]
+  stdin = "tail"
+  action = ["awk", "--sandbox", "$script"]
+  timeout = "1h"
+  cpu-time = "5m"
+  memory = "512M"
+  nice = 10
+  ionice = "idle"
+  max-lines-per-second = 1000
+  max-bytes-per-second = 1048576
+
+  # Actions can use the placeholders $lines, $path, $script, $alias, $group,
+  # $dir, $basename and $user, as whole arguments or within them ("${path}.1").
+  # "$$" is a literal "$", and other names are passed as they are. Params are
+  # placeholders that clients can set, of type "string" (optionally limited
+  # to values), "int" or "bool". Values are passed as arguments without a
+  # shell, so do not put $script or string params into "sh -c" scripts.
+  [commands.head]
+  action = ["head", "--lines=$count", "$path"]
+  params.count = { type = "int", default = 100 }
+
   # File, glob and dir filespecs are similar in principle to their
-  # command-line counterparts.
+  # command-line counterparts. The type is inferred from the path if it is
+  # not set. Files given on the command-line are served in addition to these.
+  [[files]]
+  path = "/var/log/nginx/"
+  type = "dir"
+  alias = "nginx"
+  group = "web"
+  maxdepth = 2
+  symlinks = "files"
+  include = ["*.log"]
+  exclude = ["*.gz"]
+
+  [[files]]
+  path = "/var/log/apache/*.log"
+  group = "web"
+
+  # Files can also be given as named tables. The name is used as the alias.
+  [files.messages]
+  path = "/var/log/messages"
 
 At startup tailon loads the following default configuration:
 `
@@ -90,15 +310,31 @@ const defaultTomlConfig = `
   listen-addr = [":8080"]
   allow-download = true
   allow-commands = ["tail", "grep", "sed", "awk"]
+  wrap-lines = false
+  tail-lines = 10
+  metrics = false
+  watch-config = false
+  log-level = "info"
+  log-format = "text"
+  log-file = "stderr"
+  refresh-interval = "10s"
+  send-queue-size = 1000
+  send-queue-policy = "drop-oldest"
+  send-batch-size = 100
+  max-sessions = 100
+  max-sessions-per-client = 0
+  max-commands = 500
+  max-commands-per-client = 0
+  idle-timeout = "0s"
 
   [commands]
 
     [commands.tail]
-    action = ["tail", "-n", "$lines", "-F", "$path"]
+    action = ["@tail"]
 
     [commands.grep]
     stdin = "tail"
-    action = ["grep", "--text", "--line-buffered", "--color=never", "-e", "$script"]
+    action = ["@grep", "-e", "$script"]
     default = ".*"
 
     [commands.sed]
@@ -112,32 +348,33 @@ const defaultTomlConfig = `
     default = "{print $0; fflush()}"
 `
 
+// The name of the builtin action that follows files natively instead of
+// running "tail -F". All sessions following the same file share one reader.
+const builtinTail = "@tail"
+
+// The hub that follows files for the builtin "@tail" action.
+var tailHub = tail.NewHub(tail.DefaultOptions)
+
 // CommandSpec defines a command that the server can execute.
 type CommandSpec struct {
 	Stdin   string
 	Action  []string
 	Default string
-}
 
-func parseTomlConfig(config string) (*toml.Tree, map[string]CommandSpec) {
-	cfg, err := toml.Load(config)
-	if err != nil {
-		log.Fatal("Error parsing config: ", err)
-	}
+	// Resource limits of the command, as given in the config file. They are
+	// parsed into limits by parseCommandTables.
+	Timeout           string `mapstructure:"timeout"`
+	CPUTime           string `mapstructure:"cpu-time"`
+	Memory            string `mapstructure:"memory"`
+	Nice              int    `mapstructure:"nice"`
+	IONice            string `mapstructure:"ionice"`
+	MaxLinesPerSecond int    `mapstructure:"max-lines-per-second"`
+	MaxBytesPerSecond int    `mapstructure:"max-bytes-per-second"`
 
-	commands := make(map[string]CommandSpec)
+	// Parameters that clients can set, keyed by the name of their placeholder.
+	Params map[string]ParamSpec
 
-	cfgCommands := cfg.Get("commands").(*toml.Tree).ToMap()
-	for key, value := range cfgCommands {
-		command := CommandSpec{}
-		err := mapstructure.Decode(value, &command)
-		if err != nil {
-			log.Fatal(err)
-		}
-		commands[key] = command
-	}
-
-	return cfg, commands
+	limits cmd.Limits
 }
 
 // FileSpec is an instance of a file to be monitored. These are mapped to
@@ -147,13 +384,28 @@ type FileSpec struct {
 	Type  string
 	Alias string
 	Group string
+
+	// The following options apply only to "dir" filespecs. A MaxDepth of 0
+	// means that the directory is walked without a depth limit.
+	MaxDepth int
+	Symlinks string
+	Include  []string
+	Exclude  []string
 }
 
+// Symlink policies for "dir" filespecs:
+//
+//	files  - serve symlinks to regular files, but do not descend into symlinked directories (default)
+//	follow - serve symlinks to regular files and descend into symlinked directories
+//	skip   - ignore all symlinks
+var symlinkPolicies = []string{"files", "follow", "skip"}
+
 // Parse a string into a filespec. Example inputs are:
 //
 //	alias=1,group=2,/var/log/messages
 //	/var/log/
 //	/var/log/*
+//	maxdepth=2,include=*.log,exclude=*.gz,/var/log/
 func parseFileSpec(spec string) (FileSpec, error) {
 	var filespec FileSpec
 	var path string
@@ -167,16 +419,7 @@ func parseFileSpec(spec string) (FileSpec, error) {
 		path, parts = parts[len(parts)-1], parts[:len(parts)-1]
 	}
 
-	if strings.ContainsAny(path, "*?[]") {
-		filespec.Type = "glob"
-	} else {
-		stat, err := os.Lstat(path)
-		if os.IsNotExist(err) || stat.Mode().IsRegular() {
-			filespec.Type = "file"
-		} else if stat.Mode().IsDir() {
-			filespec.Type = "dir"
-		}
-	}
+	filespec.Type = fileSpecType(path)
 
 	for _, part := range parts {
 		if strings.HasPrefix(part, "group=") {
@@ -185,66 +428,202 @@ func parseFileSpec(spec string) (FileSpec, error) {
 			filespec.Group = group
 		} else if strings.HasPrefix(part, "alias=") {
 			filespec.Alias = strings.SplitN(part, "=", 2)[1]
+		} else if strings.HasPrefix(part, "maxdepth=") {
+			depth, err := strconv.Atoi(strings.SplitN(part, "=", 2)[1])
+			if err != nil || depth < 0 {
+				return filespec, fmt.Errorf("invalid maxdepth: %s", part)
+			}
+			filespec.MaxDepth = depth
+		} else if strings.HasPrefix(part, "symlinks=") {
+			filespec.Symlinks = strings.SplitN(part, "=", 2)[1]
+		} else if strings.HasPrefix(part, "include=") {
+			filespec.Include = append(filespec.Include, strings.SplitN(part, "=", 2)[1])
+		} else if strings.HasPrefix(part, "exclude=") {
+			filespec.Exclude = append(filespec.Exclude, strings.SplitN(part, "=", 2)[1])
 		}
 	}
 
-	if filespec.Type == "" {
-		filespec.Type = "file"
-	}
 	filespec.Path = path
+
+	if err := validateDirOptions(filespec); err != nil {
+		return filespec, err
+	}
 	return filespec, nil
 
 }
 
-// Config contains all backend and frontend configuration options and relevant state.
-type Config struct {
-	RelativeRoot      string
-	BindAddr          []string
-	ConfigPath        string
-	WrapLinesInitial  bool
-	TailLinesInitial  int
-	AllowCommandNames []string
-	AllowDownload     bool
-
-	CommandSpecs   map[string]CommandSpec
-	CommandScripts map[string]string
-	FileSpecs      []FileSpec
+// Determine if a path is a "file", "glob" or "dir" filespec.
+func fileSpecType(path string) string {
+	if strings.ContainsAny(path, "*?[]") {
+		return "glob"
+	}
+
+	stat, err := os.Lstat(path)
+	if err == nil && stat.Mode().IsDir() {
+		return "dir"
+	}
+	return "file"
+}
+
+// fileSpecTable is the config file counterpart of FileSpec.
+type fileSpecTable struct {
+	Path     string
+	Type     string
+	Alias    string
+	Group    string
+	MaxDepth int `mapstructure:"maxdepth"`
+	Symlinks string
+	Include  []string
+	Exclude  []string
+}
+
+// Parse the [[files]] array of tables or the [files.<name>] tables of a config
+// file into filespecs. The name of a [files.<name>] table is used as the alias
+// if one is not set explicitly. For example:
+//
+//	[[files]]
+//	path = "/var/log/nginx/"
+//	group = "nginx"
+//	include = ["*.log"]
+//
+//	[files.messages]
+//	path = "/var/log/messages"
+func parseFileSpecTables(cfg *toml.Tree) ([]FileSpec, error) {
+	var filespecs []FileSpec
+
+	parse := func(table *toml.Tree, name string) error {
+		entry := fileSpecTable{}
+		decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			ErrorUnused:      true,
+			WeaklyTypedInput: true,
+			Result:           &entry,
+		})
+		if err := decoder.Decode(table.ToMap()); err != nil {
+			return fmt.Errorf("%s at line %d: %s", name, table.Position().Line, err)
+		}
+
+		filespec, err := entry.fileSpec()
+		if err != nil {
+			return fmt.Errorf("%s at line %d: %s", name, table.Position().Line, err)
+		}
+		filespecs = append(filespecs, filespec)
+		return nil
+	}
+
+	switch files := cfg.Get("files").(type) {
+	case nil:
+	case []*toml.Tree:
+		for n, table := range files {
+			if err := parse(table, fmt.Sprintf("[[files]] entry %d", n+1)); err != nil {
+				return nil, err
+			}
+		}
+	case *toml.Tree:
+		// Keep the order of the tables in the file.
+		for _, key := range sortedKeys(files) {
+			table, ok := files.Get(key).(*toml.Tree)
+			if !ok {
+				return nil, fmt.Errorf("[files.%s] at line %d: expected a table", key, files.GetPosition(key).Line)
+			}
+			if !table.Has("alias") {
+				table.Set("alias", key)
+			}
+			if err := parse(table, fmt.Sprintf("[files.%s]", key)); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("files at line %d: expected a table or an array of tables", cfg.GetPosition("files").Line)
+	}
+
+	return filespecs, nil
+}
+
+// Convert a config file table into a filespec, inferring the type from the
+// path if it is not given.
+func (entry fileSpecTable) fileSpec() (FileSpec, error) {
+	filespec := FileSpec{
+		Path:     entry.Path,
+		Type:     entry.Type,
+		Alias:    entry.Alias,
+		Group:    entry.Group,
+		MaxDepth: entry.MaxDepth,
+		Symlinks: entry.Symlinks,
+		Include:  entry.Include,
+		Exclude:  entry.Exclude,
+	}
+
+	if filespec.Path == "" {
+		return filespec, fmt.Errorf("missing path")
+	}
+
+	switch filespec.Type {
+	case "":
+		filespec.Type = fileSpecType(filespec.Path)
+	case "file", "glob", "dir":
+	default:
+		return filespec, fmt.Errorf("invalid type %q (expected one of file, glob, dir)", filespec.Type)
+	}
+
+	if filespec.MaxDepth < 0 {
+		return filespec, fmt.Errorf("invalid maxdepth: %d", filespec.MaxDepth)
+	}
+
+	return filespec, validateDirOptions(filespec)
 }
 
-func makeConfig(configContent string) *Config {
-	defaults, commandSpecs := parseTomlConfig(configContent)
+// Check that the "dir" options of a filespec are valid.
+func validateDirOptions(spec FileSpec) error {
+	if spec.Symlinks != "" && !slices.Contains(symlinkPolicies, spec.Symlinks) {
+		return fmt.Errorf("invalid symlink policy %q (expected one of %s)", spec.Symlinks, strings.Join(symlinkPolicies, ", "))
+	}
 
-	// Convert the list of bind addresses from []interface{} to []string.
-	addrsA := defaults.Get("listen-addr").([]interface{})
-	addrsB := make([]string, len(addrsA))
-	for i := range addrsA {
-		addrsB[i] = addrsA[i].(string)
+	for _, pattern := range append(spec.Include, spec.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %s", pattern, err)
+		}
 	}
 
-	config := Config{
-		BindAddr:      addrsB,
-		RelativeRoot:  defaults.Get("relative-root").(string),
-		AllowDownload: defaults.Get("allow-download").(bool),
-		CommandSpecs:  commandSpecs,
+	if spec.Type != "dir" && (spec.MaxDepth != 0 || spec.Symlinks != "" || len(spec.Include) > 0 || len(spec.Exclude) > 0) {
+		return fmt.Errorf("maxdepth, symlinks, include and exclude apply only to directories")
 	}
 
-	mapstructure.Decode(defaults.Get("allow-commands"), &config.AllowCommandNames)
-	return &config
+	return nil
 }
 
-var config = &Config{}
+// The files matched by the filespecs in config.
+var registry = newFileRegistry(nil)
 
 func main() {
-	config = makeConfig(defaultTomlConfig)
+	defaults := defaultConfig()
 
 	printHelp := flag.BoolP("help", "h", false, "Show this help message and exit")
 	printConfigHelp := flag.BoolP("help-config", "e", false, "Show configuration file help and exit")
-	bindAddr := flag.StringP("bind", "b", strings.Join(config.BindAddr, ","), "Address and port to listen on")
-
-	flag.StringVarP(&config.RelativeRoot, "relative-root", "r", config.RelativeRoot, "Webapp relative root")
-	flag.BoolVarP(&config.AllowDownload, "allow-download", "a", config.AllowDownload, "Allow file downloads")
-	flag.StringVarP(&config.ConfigPath, "config", "c", "", "Path to TOML configuration file")
-	flag.Parse()
+	printConfig := flag.Bool("print-config", false, "Print the effective configuration and exit")
+	configPaths := flag.StringArrayP("config", "c", nil, "Path to TOML configuration file (can be repeated)")
+
+	// The flags that set config options are applied on top of the config
+	// files and the environment by loadConfig.
+	flag.StringP("bind", "b", strings.Join(defaults.BindAddr, ","), "Address and port to listen on")
+	flag.StringP("relative-root", "r", defaults.RelativeRoot, "Webapp relative root")
+	flag.String("title", defaults.Title, "Title of the webapp")
+	flag.Bool("wrap-lines", defaults.WrapLinesInitial, "Wrap long lines initially")
+	flag.Int("tail-lines", defaults.TailLinesInitial, "Number of lines to tail initially")
+	flag.BoolP("allow-download", "a", defaults.AllowDownload, "Allow file downloads")
+	flag.Bool("metrics", defaults.Metrics, "Serve Prometheus metrics on /metrics")
+	flag.Duration("refresh-interval", defaults.RefreshInterval, "How often the file listing is refreshed")
+	flag.Bool("watch-config", defaults.WatchConfig, "Reload the config when a config file changes")
+	flag.String("log-level", defaults.LogLevel, "Minimum log level: debug, info, warn or error")
+	flag.String("log-format", defaults.LogFormat, "Log format: text or json")
+	flag.String("log-file", defaults.LogFile, "Log to a file, stdout or stderr")
+
+	// "tailon check" takes the same flags and arguments.
+	args := os.Args[1:]
+	check := len(args) > 0 && args[0] == "check"
+	if check {
+		args = args[1:]
+	}
+	flag.CommandLine.Parse(args)
 
 	flag.Usage = func() {
 		fmt.Fprintln(os.Stderr, strings.TrimLeft(scriptDescription, "\n"))
@@ -263,24 +642,14 @@ func main() {
 		os.Exit(0)
 	}
 
-	config.BindAddr = strings.Split(*bindAddr, ",")
-
-	// If a configuration file is specified, read all options from it. This discards all options set on the command-line.
-	if config.ConfigPath != "" {
-		if b, err := ioutil.ReadFile(config.ConfigPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading config file '%s': %s\n", config.ConfigPath, err)
-			os.Exit(1)
-		} else {
-			config = makeConfig(string(b))
-		}
+	config, err := loadConfig(*configPaths, os.Environ(), flag.CommandLine)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %s\n", err)
+		os.Exit(1)
 	}
 
-	// Ensure that relative root is always '/' or '/$arg/'.
-	config.RelativeRoot = "/" + strings.TrimLeft(config.RelativeRoot, "/")
-	config.RelativeRoot = strings.TrimRight(config.RelativeRoot, "/") + "/"
-
-	// Handle command-line file specs.
-	filespecs := make([]FileSpec, len(flag.Args()))
+	// Handle command-line file specs. These are served in addition to the ones in the config file.
+	var filespecs []FileSpec
 	for _, spec := range flag.Args() {
 		if filespec, err := parseFileSpec(spec); err != nil {
 			fmt.Fprintf(os.Stderr, "Error parsing argument '%s': %s\n", spec, err)
@@ -289,50 +658,199 @@ func main() {
 			filespecs = append(filespecs, filespec)
 		}
 	}
-	config.FileSpecs = filespecs
+	finishConfig(config, filespecs)
+
+	if *printConfig {
+		out, err := config.marshal()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error printing config: %s\n", err)
+			os.Exit(1)
+		}
+		for _, warning := range config.warnings {
+			fmt.Fprintln(os.Stderr, "Warning:", warning)
+		}
+		fmt.Print(out)
+		os.Exit(0)
+	}
+
+	if check {
+		if !checkConfig(config, os.Stdout) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 
 	if len(config.FileSpecs) == 0 {
 		fmt.Fprintln(os.Stderr, "No files specified on command-line or in config file")
 		os.Exit(2)
 	}
 
+	logger, err := newLogger(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error setting up logging: %s\n", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+	for _, warning := range config.warnings {
+		slog.Warn("Config: " + warning)
+	}
+
+	if config.Audit != nil {
+		if auditLog, err = openAuditLog(config.Audit); err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening audit log: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	setConfig(config)
+
+	slog.Info("Generating initial file listing")
+	registry = newFileRegistry(config.FileSpecs)
+	go registry.run(config.RefreshInterval, nil)
+
+	servers := make(map[ListenSpec]*runningServer)
+	if !updateServers(servers, config) {
+		os.Exit(1)
+	}
+
+	// Reload the config on SIGHUP and, with watch-config, shortly after one
+	// of the config files changes.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	changed := make(chan struct{}, 1)
+	rewatch := make(chan []string, 1)
+	if config.WatchConfig {
+		go watchConfigFiles(config.files, rewatch, changed)
+	}
+	for {
+		select {
+		case <-hup:
+		case <-changed:
+		}
+		if !reloadConfig(flag.CommandLine, filespecs) {
+			continue
+		}
+		config := currentConfig()
+		updateServers(servers, config)
+		if config.WatchConfig {
+			// Only the latest files are of interest.
+			select {
+			case <-rewatch:
+			default:
+			}
+			rewatch <- config.files
+		}
+	}
+}
+
+// Complete a loaded config: normalize the relative root, add the filespecs of
+// the command-line and collect the default scripts of the commands.
+func finishConfig(config *Config, filespecs []FileSpec) {
+	// Ensure that relative root is always '/' or '/$arg/'.
+	config.RelativeRoot = "/" + strings.TrimLeft(config.RelativeRoot, "/")
+	config.RelativeRoot = strings.TrimRight(config.RelativeRoot, "/") + "/"
+
+	config.FileSpecs = append(config.FileSpecs, filespecs...)
+
 	config.CommandScripts = make(map[string]string)
 	for cmd, values := range config.CommandSpecs {
 		config.CommandScripts[cmd] = values.Default
 	}
+}
 
-	log.Print("Generate initial file listing")
-	createListing(config.FileSpecs)
+// runningServer is a server and the listener that it serves.
+type runningServer struct {
+	server   *http.Server
+	listener net.Listener
+
+	// Closed when the server is stopped, which stops the certificate reloader
+	// of a TLS listener.
+	stop chan struct{}
+}
+
+// Close the listener of a server and let its connections finish.
+func (running *runningServer) shutdown() {
+	running.listener.Close()
+	close(running.stop)
+	go running.server.Shutdown(context.Background())
+}
 
-	var wg sync.WaitGroup
+// The listeners of a config. The [[listen]] tables are served in addition to
+// the listen-addr addresses.
+func (config *Config) listenSpecs() []ListenSpec {
+	listenspecs := slices.Clone(config.ListenSpecs)
 	for _, addr := range config.BindAddr {
-		wg.Add(1)
-		go startServer(config, addr)
+		listenspecs = append(listenspecs, ListenSpec{Addr: addr})
+	}
+	return listenspecs
+}
+
+// Start a server for every listener of config that is not running yet and stop
+// the servers of the listeners that are no longer in config. Stopping a server
+// closes its listener, but not the websocket connections that it accepted.
+// Return false if a server could not be started.
+func updateServers(servers map[ListenSpec]*runningServer, config *Config) bool {
+	listenspecs := config.listenSpecs()
+
+	for spec, running := range servers {
+		if !slices.Contains(listenspecs, spec) {
+			slog.Info("Stopping server", "addr", spec.Addr)
+			running.shutdown()
+			delete(servers, spec)
+		}
 	}
-	wg.Wait()
 
+	ok := true
+	for _, spec := range listenspecs {
+		if servers[spec] != nil {
+			continue
+		}
+		running, err := startServer(config, spec)
+		if err != nil {
+			slog.Error("Cannot start server", "addr", spec.Addr, "error", err)
+			ok = false
+			continue
+		}
+		servers[spec] = running
+	}
+	return ok
 }
 
-func startServer(config *Config, bindAddr string) {
-	loggerHTML := log.New(os.Stdout, "", log.LstdFlags)
-	loggerHTML.Printf("Server start, relative-root: %s, bind-addr: %s\n", config.RelativeRoot, bindAddr)
+func startServer(config *Config, spec ListenSpec) (*runningServer, error) {
+	bindAddr := spec.Addr
+	slog.Info("Starting server", "relative-root", config.RelativeRoot, "addr", bindAddr, "tls", spec.isTLS())
 
-	server := setupServer(config, bindAddr, loggerHTML)
+	server := setupServer(config, bindAddr)
 
+	var listener net.Listener
 	if strings.Contains(bindAddr, ":") {
-		server.ListenAndServe()
+		tcpListener, err := net.Listen("tcp", bindAddr)
+		if err != nil {
+			return nil, err
+		}
+		listener = tcpListener
 	} else {
 		os.Remove(bindAddr)
 
 		unixAddr, _ := net.ResolveUnixAddr("unix", bindAddr)
 		unixListener, err := net.ListenUnix("unix", unixAddr)
+		if err != nil {
+			return nil, err
+		}
+
 		unixListener.SetUnlinkOnClose(true)
+		listener = unixListener
+	}
 
+	stop := make(chan struct{})
+	if spec.isTLS() {
+		tlsConfig, err := newTLSConfig(spec, stop)
 		if err != nil {
-			panic(err)
+			listener.Close()
+			return nil, fmt.Errorf("cannot load certificate: %w", err)
 		}
-
-		defer unixListener.Close()
-		server.Serve(unixListener)
+		listener = tls.NewListener(listener, tlsConfig)
 	}
+
+	go server.Serve(listener)
+	return &runningServer{server: server, listener: listener, stop: stop}, nil
 }
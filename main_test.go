@@ -1,29 +1,108 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml"
 )
 
 func TestCliFileSpec(t *testing.T) {
-	a, b := "/a/b/c", FileSpec{"/a/b/c", "file", "", ""}
-	if res, err := parseFileSpec(a); err != nil || res != b {
-		t.Fatalf("%s != %s", b, res)
+	a, b := "/a/b/c", FileSpec{Path: "/a/b/c", Type: "file"}
+	if res, err := parseFileSpec(a); err != nil || !reflect.DeepEqual(res, b) {
+		t.Fatalf("%v != %v", b, res)
+	}
+
+	a, b = "alias=1,/a/b/c", FileSpec{Path: "/a/b/c", Type: "file", Alias: "1"}
+	if res, err := parseFileSpec(a); err != nil || !reflect.DeepEqual(res, b) {
+		t.Fatalf("%v != %v", b, res)
+	}
+
+	a, b = "alias=2,/var/log/*.log", FileSpec{Path: "/var/log/*.log", Type: "glob", Alias: "2"}
+	if res, err := parseFileSpec(a); err != nil || !reflect.DeepEqual(res, b) {
+		t.Fatalf("%v != %v", b, res)
 	}
 
-	a, b = "alias=1,/a/b/c", FileSpec{"/a/b/c", "file", "1", ""}
-	if res, err := parseFileSpec(a); err != nil || res != b {
-		t.Fatalf("%s != %s", b, res)
+	a, b = "alias=1,group=\"a b\",/var/log/", FileSpec{Path: "/var/log/", Type: "dir", Alias: "1", Group: "a b"}
+	if res, err := parseFileSpec(a); err != nil || !reflect.DeepEqual(res, b) {
+		t.Fatalf("%v != %v", b, res)
 	}
 
-	a, b = "alias=2,/var/log/*.log", FileSpec{"/var/log/*.log", "glob", "2", ""}
-	if res, err := parseFileSpec(a); err != nil || res != b {
-		t.Fatalf("%s != %s", b, res)
+	a = "maxdepth=2,symlinks=skip,include=*.log,include=*.txt,exclude=old,/var/log/"
+	b = FileSpec{
+		Path: "/var/log/", Type: "dir", MaxDepth: 2, Symlinks: "skip",
+		Include: []string{"*.log", "*.txt"}, Exclude: []string{"old"},
+	}
+	if res, err := parseFileSpec(a); err != nil || !reflect.DeepEqual(res, b) {
+		t.Fatalf("%v != %v", b, res)
 	}
 
-	a, b = "alias=1,group=\"a b\",/var/log/", FileSpec{"/var/log/", "dir", "1", "a b"}
-	if res, err := parseFileSpec(a); err != nil || res != b {
-		t.Fatalf("%s != %s", b, res)
+	for _, spec := range []string{"maxdepth=x,/var/log/", "symlinks=always,/var/log/", "include=[,/var/log/", "maxdepth=1,/a/b/c"} {
+		if _, err := parseFileSpec(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestConfigFileSpec(t *testing.T) {
+	cfg, _ := toml.Load(`
+	[[files]]
+	path = "/var/log/messages"
+	group = "system"
+
+	[[files]]
+	path = "/var/log/nginx/"
+	type = "dir"
+	maxdepth = 2
+	include = "*.log"
+	`)
+
+	expect := []FileSpec{
+		{Path: "/var/log/messages", Type: "file", Group: "system"},
+		{Path: "/var/log/nginx/", Type: "dir", MaxDepth: 2, Include: []string{"*.log"}},
+	}
+	if res, err := parseFileSpecTables(cfg); err != nil || !reflect.DeepEqual(res, expect) {
+		t.Fatalf("%v != %v (%v)", expect, res, err)
+	}
+
+	cfg, _ = toml.Load(`
+	[files.messages]
+	path = "/var/log/messages"
+
+	[files.nginx]
+	path = "/var/log/nginx/*.log"
+	alias = "web"
+	`)
+
+	expect = []FileSpec{
+		{Path: "/var/log/messages", Type: "file", Alias: "messages"},
+		{Path: "/var/log/nginx/*.log", Type: "glob", Alias: "web"},
+	}
+	if res, err := parseFileSpecTables(cfg); err != nil || !reflect.DeepEqual(res, expect) {
+		t.Fatalf("%v != %v (%v)", expect, res, err)
+	}
+
+	errors := map[string]string{
+		"[[files]]\ngroup = 'a'":                                   "[[files]] entry 1 at line 1: missing path",
+		"[[files]]\npath = 'a'\n[[files]]\npath = 'b'\ntype = 'x'": "[[files]] entry 2 at line 3: invalid type",
+		"[[files]]\npath = 'a'\ncolour = 'red'":                    "[[files]] entry 1 at line 1: 1 error(s) decoding",
+		"[files.a]\npath = 'a'\nmaxdepth = 1":                      "[files.a] at line 1: maxdepth, symlinks",
+		"files = 1":                                                "files at line 1: expected a table",
+	}
+	for content, prefix := range errors {
+		cfg, _ = toml.Load(content)
+		if _, err := parseFileSpecTables(cfg); err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("%q: %v does not start with %q", content, err, prefix)
+		}
 	}
 }
 
@@ -37,7 +116,7 @@ func getAliases(entries []*ListEntry) []string {
 
 func TestListingWildcard(t *testing.T) {
 	spec, _ := parseFileSpec("testdata/ex1/var/log/*.log")
-	lst := createListing([]FileSpec{spec})
+	lst := createListing([]FileSpec{spec}).visible(nil)
 
 	if len(lst["__default__"]) != 4 {
 		t.Fatalf("len(%#v) != 4\n", lst)
@@ -49,7 +128,7 @@ func TestListingWildcard(t *testing.T) {
 	}
 
 	spec, _ = parseFileSpec("alias=logs,testdata/ex1/var/log/*.log")
-	lst = createListing([]FileSpec{spec})
+	lst = createListing([]FileSpec{spec}).visible(nil)
 
 	aliases = getAliases(lst["__default__"])
 	expect := `["logs/1.log" "logs/2.log" "logs/3.log" "logs/4.log"]`
@@ -62,7 +141,7 @@ func TestListingWildcard(t *testing.T) {
 func TestListingFile(t *testing.T) {
 	spec1, _ := parseFileSpec("testdata/ex1/var/log/1.log")
 	spec2, _ := parseFileSpec("testdata/ex1/var/log/2.log")
-	lst := createListing([]FileSpec{spec1, spec2})
+	lst := createListing([]FileSpec{spec1, spec2}).visible(nil)
 
 	aliases := getAliases(lst["__default__"])
 	repr := fmt.Sprintf("%#q", aliases)
@@ -73,7 +152,7 @@ func TestListingFile(t *testing.T) {
 	spec1, _ = parseFileSpec("group=a,alias=a.log,testdata/ex1/var/log/1.log")
 	spec2, _ = parseFileSpec("group=b,alias=b.log,testdata/ex1/var/log/2.log")
 
-	lst = createListing([]FileSpec{spec1, spec2})
+	lst = createListing([]FileSpec{spec1, spec2}).visible(nil)
 	if lst["a"][0].Alias != "a.log" || !lst["a"][0].Exists {
 		t.Fatal()
 	}
@@ -81,3 +160,161 @@ func TestListingFile(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestFileInfoStatError(t *testing.T) {
+	// Stat fails with ENOTDIR rather than ENOENT.
+	entry := fileInfo("testdata/ex1/var/log/1.log/child")
+	if entry.Exists || entry.Size != 0 {
+		t.Fatalf("%+v", entry)
+	}
+}
+
+func TestListingDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.log", "b.txt", "sub/c.log", "sub/deep/d.log", "old/e.log"} {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+		os.WriteFile(filepath.Join(dir, name), []byte("line\n"), 0644)
+	}
+	os.Symlink(filepath.Join(dir, "sub"), filepath.Join(dir, "link"))
+	os.Symlink(dir, filepath.Join(dir, "sub", "loop"))
+
+	spec, _ := parseFileSpec("alias=logs,exclude=old," + dir)
+	lst := createListing([]FileSpec{spec}).visible(nil)
+
+	aliases := getAliases(lst["__default__"])
+	expect := `["logs/a.log" "logs/b.txt" "logs/sub/c.log" "logs/sub/deep/d.log"]`
+	if fmt.Sprintf("%q", aliases) != expect {
+		t.Fatalf("%q != %q", aliases, expect)
+	}
+	listing := createListing([]FileSpec{spec})
+	if !listing.allowed(filepath.Join(dir, "sub/deep/d.log"), nil) || listing.allowed(filepath.Join(dir, "old/e.log"), nil) {
+		t.Fatal()
+	}
+
+	spec, _ = parseFileSpec("maxdepth=2,include=*.log,symlinks=follow,exclude=old,alias=logs," + dir)
+	lst = createListing([]FileSpec{spec}).visible(nil)
+
+	aliases = getAliases(lst["__default__"])
+	expect = `["logs/a.log" "logs/link/c.log" "logs/sub/c.log"]`
+	if fmt.Sprintf("%q", aliases) != expect {
+		t.Fatalf("%q != %q", aliases, expect)
+	}
+}
+
+func TestFileRegistryConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.log"), []byte("a\n"), 0644)
+
+	spec, _ := parseFileSpec(filepath.Join(dir, "*.log"))
+	setConfig(&Config{AllowDownload: true})
+	registry = newFileRegistry([]FileSpec{spec})
+	defer func() { setConfig(&Config{}); registry = newFileRegistry(nil) }()
+
+	stop := make(chan struct{})
+	go registry.run(time.Millisecond, stop)
+	defer close(stop)
+
+	// Many sessions list files and download while the registry is refreshed
+	// and files come and go. Run with -race.
+	var wg sync.WaitGroup
+	for n := 0; n < 32; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			name := filepath.Join(dir, fmt.Sprintf("%d.log", n))
+			for i := 0; i < 50; i++ {
+				os.WriteFile(name, []byte("x\n"), 0644)
+				if _, err := json.Marshal(registry.listing(nil)); err != nil {
+					t.Error(err)
+				}
+
+				rec := httptest.NewRecorder()
+				downloadHandler(rec, httptest.NewRequest("GET", "/files/?path="+filepath.Join(dir, "a.log"), nil))
+				if rec.Code != http.StatusOK {
+					t.Errorf("download failed: %d", rec.Code)
+				}
+				registry.refresh()
+				os.Remove(name)
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	// New files show up after a refresh and removed ones disappear.
+	registry.refresh()
+	os.WriteFile(filepath.Join(dir, "b.log"), []byte("b\n"), 0644)
+	os.Remove(filepath.Join(dir, "a.log"))
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		if registry.fileAllowed(filepath.Join(dir, "b.log"), nil) && !registry.fileAllowed(filepath.Join(dir, "a.log"), nil) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("listing not refreshed: %v", registry.listing(nil))
+		}
+	}
+	if lst := registry.listing(nil); len(lst["__default__"]) != 1 {
+		t.Fatalf("unexpected listing: %v", lst)
+	}
+
+	// Sessions can subscribe and unsubscribe while a refresh walks the
+	// filesystem.
+	registry.refreshing.Lock()
+	subscribed := make(chan struct{})
+	go func() {
+		_, unsubscribe := registry.subscribe()
+		unsubscribe()
+		close(subscribed)
+	}()
+	select {
+	case <-subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscribe blocked by a refresh")
+	}
+	registry.refreshing.Unlock()
+}
+
+func TestListingChanges(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.log"), []byte("a\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.log"), []byte("b\n"), 0644)
+
+	spec, _ := parseFileSpec("alias=logs," + filepath.Join(dir, "*.log"))
+	reg := newFileRegistry([]FileSpec{spec})
+	changed, unsubscribe := reg.subscribe()
+	defer unsubscribe()
+
+	// Only fsnotify can trigger a refresh within the test.
+	stop := make(chan struct{})
+	go reg.run(time.Hour, stop)
+	defer close(stop)
+
+	old := reg.listing(nil)
+	time.Sleep(50 * time.Millisecond)
+	os.WriteFile(filepath.Join(dir, "a.log"), []byte("a\na\n"), 0644)
+	os.Remove(filepath.Join(dir, "b.log"))
+	os.WriteFile(filepath.Join(dir, "c.log"), []byte("c\n"), 0644)
+
+	// The changes may arrive in more than one refresh.
+	expect := []string{"removed __default__ logs/b.log 2", "changed __default__ logs/a.log 4", "added __default__ logs/c.log 2"}
+	var res []string
+	for !reflect.DeepEqual(res, expect) {
+		select {
+		case <-changed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%q != %q", res, expect)
+		}
+
+		res = nil
+		for _, change := range diffListings(old, reg.listing(nil)) {
+			res = append(res, fmt.Sprintf("%s %s %s %d", change.Op, change.Group, change.Entry.Alias, change.Entry.Size))
+		}
+	}
+
+	// Refreshes that change nothing do not notify.
+	reg.refresh()
+	select {
+	case <-changed:
+		t.Fatal("unexpected change notification")
+	default:
+	}
+}
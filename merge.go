@@ -0,0 +1,321 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// The maximum number of files in a merged stream.
+const maxMergedFiles = 32
+
+// The reorder window of interleaved streams, unless the client asks for
+// another one, and the largest window that a client can ask for.
+const (
+	defaultReorderWindow = 500 * time.Millisecond
+	maxReorderWindow     = 5 * time.Second
+)
+
+// The number of lines that an interleaved stream holds back. Once it is
+// reached, lines are released before their window ends.
+const maxHeldLines = 10000
+
+// sourcedLine is a line of a merged stream and the alias of the file that it
+// came from.
+type sourcedLine struct {
+	Source string
+	Line   string
+}
+
+// merger runs the same command for several files and merges their output.
+// Every line is tagged with the alias of its file. With a reorder window, the
+// lines are held back for that long and released in the order of the
+// timestamps at their start, so that files which are written at the same time
+// interleave in the order of their events.
+type merger struct {
+	pipes   []*pipeline
+	aliases []string
+	window  time.Duration
+	log     *slog.Logger
+
+	// The merged output of all pipelines. Lines on stderr and the messages of
+	// limit events are prefixed with the alias of their file. All are closed
+	// after all pipelines finish.
+	Stdout <-chan sourcedLine
+	Stderr <-chan string
+	Limits <-chan limitEvent
+}
+
+// Create the pipelines of a merged stream. The command and script of fc are
+// run for every entry.
+func newMerger(specs map[string]CommandSpec, fc FrontendCommand, entries []ListEntry, window time.Duration) (*merger, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no files to merge")
+	}
+	if len(entries) > maxMergedFiles {
+		return nil, fmt.Errorf("too many files to merge (at most %d)", maxMergedFiles)
+	}
+
+	m := &merger{window: window, log: slog.Default()}
+	for _, entry := range entries {
+		fc.Entry = entry
+		pipe, err := newPipeline(specs, fc)
+		if err != nil {
+			return nil, err
+		}
+		alias := entry.Alias
+		if alias == "" {
+			alias = entry.Path
+		}
+		m.pipes = append(m.pipes, pipe)
+		m.aliases = append(m.aliases, alias)
+	}
+	return m, nil
+}
+
+// Start all pipelines. If one fails to start, the others are stopped.
+func (m *merger) start() error {
+	for n, pipe := range m.pipes {
+		pipe.log = m.log.With("source", m.aliases[n])
+		if err := pipe.start(); err != nil {
+			for _, started := range m.pipes[:n] {
+				started.stop()
+			}
+			return fmt.Errorf("%s: %s", m.aliases[n], err)
+		}
+	}
+
+	stdout := make(chan sourcedLine)
+	stderr := make(chan string)
+	limits := make(chan limitEvent)
+
+	var wg sync.WaitGroup
+	for n, pipe := range m.pipes {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for line := range pipe.Stdout {
+				stdout <- sourcedLine{m.aliases[n], line}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for line := range pipe.Stderr {
+				stderr <- m.aliases[n] + ": " + line
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for event := range pipe.Limits {
+				event.Message = m.aliases[n] + ": " + event.Message
+				limits <- event
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(stdout)
+		close(stderr)
+		close(limits)
+	}()
+
+	m.Stdout = stdout
+	if m.window > 0 {
+		m.Stdout = reorderLines(stdout, m.window)
+	}
+	m.Stderr = stderr
+	m.Limits = limits
+	return nil
+}
+
+func (m *merger) stop() {
+	for _, pipe := range m.pipes {
+		pipe.stop()
+	}
+}
+
+func (m *merger) statuses() []processStatus {
+	var res []processStatus
+	for _, pipe := range m.pipes {
+		res = append(res, pipe.statuses()...)
+	}
+	return res
+}
+
+// The arguments of all commands, in the order of the files.
+func (m *merger) argv() [][]string {
+	var res [][]string
+	for _, pipe := range m.pipes {
+		res = append(res, pipe.argv...)
+	}
+	return res
+}
+
+// --------------------------------------------------------------------------
+
+// A line that is held back by reorderLines.
+type heldLine struct {
+	sourcedLine
+	timestamp time.Time
+	arrived   time.Time
+	seq       int
+	released  bool
+}
+
+// A min-heap of held lines, ordered by timestamp and then by arrival.
+type lineHeap []*heldLine
+
+func (h lineHeap) Len() int { return len(h) }
+func (h lineHeap) Less(i, j int) bool {
+	if !h[i].timestamp.Equal(h[j].timestamp) {
+		return h[i].timestamp.Before(h[j].timestamp)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h lineHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *lineHeap) Push(x any)   { *h = append(*h, x.(*heldLine)) }
+func (h *lineHeap) Pop() any {
+	old := *h
+	line := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return line
+}
+
+// Hold back every line for at most window and release the held lines in the
+// order of their timestamps. Once the line that arrived first has been held for
+// window, it is released along with the lines whose timestamps are earlier than
+// its own, even if they arrived later. Lines without a timestamp (e.g. the continuation lines of
+// a stack trace) get the timestamp of the previous line of the same source,
+// so that they stay with it. All held lines are released when in is closed.
+//
+// No lines are read while a line is waiting to be released, so that a client
+// which does not keep up (or a paused stream) stops the pipelines, as it does
+// for streams that are not interleaved.
+func reorderLines(in <-chan sourcedLine, window time.Duration) <-chan sourcedLine {
+	out := make(chan sourcedLine)
+
+	go func() {
+		defer close(out)
+
+		var held lineHeap
+		// The held lines in the order in which they arrived. Lines that were
+		// released are removed from the front.
+		var arrivals []*heldLine
+		last := make(map[string]time.Time)
+		seq := 0
+
+		timer := time.NewTimer(window)
+		defer timer.Stop()
+
+		for in != nil || len(held) > 0 {
+			for len(arrivals) > 0 && arrivals[0].released {
+				arrivals = arrivals[1:]
+			}
+
+			// Release the earliest line once the line that arrived first has
+			// been held long enough, or as soon as possible if too many lines
+			// are held.
+			input := in
+			var release chan<- sourcedLine
+			var next sourcedLine
+			if len(held) > 0 {
+				wait := time.Until(arrivals[0].arrived.Add(window))
+				if wait <= 0 || in == nil || len(held) >= maxHeldLines {
+					input, release, next = nil, out, held[0].sourcedLine
+				} else {
+					timer.Reset(wait)
+				}
+			}
+
+			select {
+			case line, ok := <-input:
+				if !ok {
+					in = nil
+					continue
+				}
+				now := time.Now()
+				timestamp, ok := parseTimestamp(line.Line)
+				if !ok {
+					timestamp, ok = last[line.Source]
+				}
+				if !ok {
+					timestamp = now
+				}
+				last[line.Source] = timestamp
+				h := &heldLine{line, timestamp, now, seq, false}
+				heap.Push(&held, h)
+				arrivals = append(arrivals, h)
+				seq++
+			case release <- next:
+				heap.Pop(&held).(*heldLine).released = true
+			case <-timer.C:
+			}
+		}
+	}()
+
+	return out
+}
+
+// Timestamp formats that are recognized at the start of a line (or after an
+// opening bracket), with the layout that parses them. Formats without a time
+// zone are in local time and formats without a year are in the current year.
+var timestampFormats = []struct {
+	re     *regexp.Regexp
+	layout string
+}{
+	// 2024-05-01T12:34:56.789Z, 2024-05-01 12:34:56,789+02:00
+	{regexp.MustCompile(`^\d{4}-\d\d-\d\d[T ]\d\d:\d\d:\d\d(?:[.,]\d+)?(?:Z|[+-]\d\d:?\d\d)?`), ""},
+	// May  1 12:34:56 (syslog)
+	{regexp.MustCompile(`^[A-Z][a-z]{2} [ \d]\d \d\d:\d\d:\d\d`), time.Stamp},
+	// 01/May/2024:12:34:56 +0200 (common log format)
+	{regexp.MustCompile(`^\d\d/[A-Z][a-z]{2}/\d{4}:\d\d:\d\d:\d\d [+-]\d{4}`), "02/Jan/2006:15:04:05 -0700"},
+}
+
+// The layouts of the first timestamp format, which vary in their separators.
+var isoLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// Parse the timestamp at the start of a line.
+func parseTimestamp(line string) (time.Time, bool) {
+	if len(line) > 0 && line[0] == '[' {
+		line = line[1:]
+	}
+
+	for n, format := range timestampFormats {
+		match := format.re.FindString(line)
+		if match == "" {
+			continue
+		}
+
+		if n > 0 {
+			t, err := time.ParseInLocation(format.layout, match, time.Local)
+			if err != nil {
+				return time.Time{}, false
+			}
+			if t.Year() == 0 {
+				t = t.AddDate(time.Now().Year(), 0, 0)
+			}
+			return t, true
+		}
+
+		// Normalize "2024-05-01 12:34:56,789" to "2024-05-01T12:34:56.789".
+		match = match[:10] + "T" + match[11:]
+		if len(match) > 19 && match[19] == ',' {
+			match = match[:19] + "." + match[20:]
+		}
+		for _, layout := range isoLayouts {
+			if t, err := time.ParseInLocation(layout, match, time.Local); err == nil {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	year := time.Now().Year()
+	tests := map[string]time.Time{
+		"2024-05-01T12:34:56Z GET /":                  time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC),
+		"2024-05-01T12:34:56.250+02:00 x":             time.Date(2024, 5, 1, 10, 34, 56, 250e6, time.UTC),
+		"2024-05-01 12:34:56,5 INFO start":            time.Date(2024, 5, 1, 12, 34, 56, 500e6, time.Local),
+		"[2024-05-01 12:34:56] error":                 time.Date(2024, 5, 1, 12, 34, 56, 0, time.Local),
+		"May  1 12:34:56 host sshd[1]: ok":            time.Date(year, 5, 1, 12, 34, 56, 0, time.Local),
+		"[01/May/2024:12:34:56 +0000] \"GET / HTTP\"": time.Date(2024, 5, 1, 12, 34, 56, 0, time.UTC),
+	}
+	for line, expect := range tests {
+		if res, ok := parseTimestamp(line); !ok || !res.Equal(expect) {
+			t.Fatalf("%q: %v != %v", line, res, expect)
+		}
+	}
+
+	for _, line := range []string{"", "at main.go:12", "12:34:56 no date", "2024-13-01T00:00:00Z"} {
+		if res, ok := parseTimestamp(line); ok {
+			t.Fatalf("%q: unexpected timestamp %v", line, res)
+		}
+	}
+}
+
+func TestReorderLines(t *testing.T) {
+	in := make(chan sourcedLine)
+	out := reorderLines(in, 50*time.Millisecond)
+
+	go func() {
+		in <- sourcedLine{"b", "2024-05-01T00:00:02Z b1"}
+		in <- sourcedLine{"b", "  continuation of b1"}
+		in <- sourcedLine{"a", "2024-05-01T00:00:01Z a1"}
+		in <- sourcedLine{"a", "2024-05-01T00:00:03Z a2"}
+		close(in)
+	}()
+
+	var res []string
+	for line := range out {
+		res = append(res, line.Source+":"+strings.Fields(line.Line)[len(strings.Fields(line.Line))-1])
+	}
+	if strings.Join(res, " ") != "a:a1 b:b1 b:b1 a:a2" {
+		t.Fatalf("unexpected order %q", res)
+	}
+
+	// Lines are released after the window, even if more may follow.
+	in = make(chan sourcedLine)
+	out = reorderLines(in, 50*time.Millisecond)
+	defer close(in)
+
+	start := time.Now()
+	in <- sourcedLine{"a", "a1"}
+	select {
+	case line := <-out:
+		if line.Line != "a1" || time.Since(start) < 50*time.Millisecond {
+			t.Fatalf("unexpected line %v after %s", line, time.Since(start))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("line not released")
+	}
+
+	// A line is released after the window, even if lines with earlier
+	// timestamps keep arriving.
+	steady := make(chan sourcedLine)
+	steadyOut := reorderLines(steady, 50*time.Millisecond)
+	steadyStart := time.Now()
+	go func() {
+		defer close(steady)
+		steady <- sourcedLine{"a", "2024-05-01T00:01:00Z late"}
+		for n := 0; time.Since(steadyStart) < 500*time.Millisecond; n++ {
+			steady <- sourcedLine{"b", fmt.Sprintf("2024-05-01T00:00:%02dZ b", n%60)}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	var released time.Duration
+	for line := range steadyOut {
+		if strings.HasSuffix(line.Line, "late") {
+			released = time.Since(steadyStart)
+		}
+	}
+	if released == 0 || released > 250*time.Millisecond {
+		t.Fatalf("line released after %s", released)
+	}
+
+	// Lines are not read while a line that is due cannot be sent.
+	in <- sourcedLine{"a", "a2"}
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case in <- sourcedLine{"a", "a3"}:
+		t.Fatal("line read while a2 is not sent")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if line := <-out; line.Line != "a2" {
+		t.Fatalf("unexpected line %v", line)
+	}
+	in <- sourcedLine{"a", "a3"}
+
+	// Nor once too many lines are held back.
+	in2 := make(chan sourcedLine)
+	out = reorderLines(in2, time.Hour)
+	defer close(in2)
+	for n := range maxHeldLines {
+		in2 <- sourcedLine{"a", strconv.Itoa(n)}
+	}
+	select {
+	case in2 <- sourcedLine{"a", "full"}:
+		t.Fatal("line read while too many lines are held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if line := <-out; line.Line != "0" {
+		t.Fatalf("unexpected line %v", line)
+	}
+}
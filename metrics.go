@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics of the server that are exported in the Prometheus text format on
+// /metrics if it is enabled. The counters of the send queues are kept in
+// sendQueueMetrics.
+var metrics = &serverMetrics{
+	Processes:       labeledValues{labels: []string{"command"}},
+	ProcessExits:    labeledValues{labels: []string{"command", "code"}},
+	RefreshDuration: histogram{buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}},
+}
+
+type serverMetrics struct {
+	Sessions      atomic.Int64
+	StreamedLines atomic.Int64
+	StreamedBytes atomic.Int64
+	Downloads     atomic.Int64
+	DownloadBytes atomic.Int64
+
+	// Running processes by command name, and exited processes by command
+	// name and exit code (or signal).
+	Processes    labeledValues
+	ProcessExits labeledValues
+
+	RefreshDuration histogram
+}
+
+// labeledValues are the values of a metric with labels, keyed by the values
+// of the labels.
+type labeledValues struct {
+	labels []string
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func (v *labeledValues) add(delta int64, labels ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.values == nil {
+		v.values = make(map[string]int64)
+	}
+	v.values[strings.Join(labels, "\x00")] += delta
+}
+
+func (v *labeledValues) write(w io.Writer, name string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	keys := make([]string, 0, len(v.values))
+	for key := range v.values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		var pairs []string
+		for n, value := range strings.Split(key, "\x00") {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", v.labels[n], labelEscaper.Replace(value)))
+		}
+		fmt.Fprintf(w, "%s{%s} %d\n", name, strings.Join(pairs, ","), v.values[key])
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// histogram counts observations in cumulative buckets.
+type histogram struct {
+	buckets []float64
+	mu      sync.Mutex
+	counts  []int64
+	count   int64
+	sum     float64
+}
+
+func (h *histogram) observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.counts == nil {
+		h.counts = make([]int64, len(h.buckets))
+	}
+	for n, bound := range h.buckets {
+		if value <= bound {
+			h.counts[n]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) write(w io.Writer, name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for n, bound := range h.buckets {
+		var count int64
+		if h.counts != nil {
+			count = h.counts[n]
+		}
+		fmt.Fprintf(w, "%s_bucket{le=\"%g\"} %d\n", name, bound, count)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %g\n", name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// Time a call of f in a histogram.
+func (h *histogram) time(f func()) {
+	start := time.Now()
+	f()
+	h.observe(time.Since(start).Seconds())
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if !currentConfig().Metrics {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	write := func(name, typ, help string, value int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, typ, name, value)
+	}
+	header := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	write("tailon_sessions", "gauge", "Active websocket sessions.", metrics.Sessions.Load())
+	header("tailon_processes", "gauge", "Running processes by command name.")
+	metrics.Processes.write(w, "tailon_processes")
+	header("tailon_process_exits_total", "counter", "Exited processes by command name and exit code or signal.")
+	metrics.ProcessExits.write(w, "tailon_process_exits_total")
+	write("tailon_streamed_lines_total", "counter", "Lines of command output streamed to clients.", metrics.StreamedLines.Load())
+	write("tailon_streamed_bytes_total", "counter", "Bytes of command output streamed to clients.", metrics.StreamedBytes.Load())
+	write("tailon_dropped_lines_total", "counter", "Lines dropped because a client could not keep up.", sendQueueMetrics.DroppedLines.Load())
+	write("tailon_paused_commands_total", "counter", "Times a command was paused because a client could not keep up.", sendQueueMetrics.Pauses.Load())
+	write("tailon_sent_messages_total", "counter", "Websocket messages sent to clients.", sendQueueMetrics.SentMessages.Load())
+	write("tailon_downloads_total", "counter", "File downloads.", metrics.Downloads.Load())
+	write("tailon_download_bytes_total", "counter", "Bytes of downloaded files.", metrics.DownloadBytes.Load())
+	header("tailon_listing_refresh_duration_seconds", "histogram", "Time taken to create the file listing.")
+	metrics.RefreshDuration.write(w, "tailon_listing_refresh_duration_seconds")
+}
+
+// countingWriter counts the bytes written to a response and records its code.
+// The websocket and streaming transports of sockjs need it to be a Hijacker
+// and a Flusher.
+type countingWriter struct {
+	http.ResponseWriter
+	n    int64
+	code int
+}
+
+func (w *countingWriter) WriteHeader(code int) {
+	w.code = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *countingWriter) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *countingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.code = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	server := startTestServer(t, map[string]CommandSpec{
+		"metrics-cat": {Action: []string{"cat", "$path"}},
+	}, func(config *Config) {
+		config.AllowDownload = true
+		config.Metrics = true
+	})
+
+	get := func(path string) string {
+		res, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return string(body)
+	}
+
+	client := dialWS(t, server)
+	client.send(`{"v": 1, "type": "stream", "id": "1", "payload": {"command": "metrics-cat", "entry": {"path": "testdata/ex1/var/log/1.log"}}}`)
+	client.recvUntil("1", "ended")
+	get("/files/?path=testdata/ex1/var/log/1.log")
+
+	body := get("/metrics")
+	for _, expect := range []string{
+		"# TYPE tailon_sessions gauge\ntailon_sessions ",
+		`tailon_processes{command="metrics-cat"} 0`,
+		`tailon_process_exits_total{command="metrics-cat",code="0"} `,
+		"tailon_downloads_total ",
+		"tailon_download_bytes_total ",
+		"tailon_streamed_lines_total ",
+		`tailon_listing_refresh_duration_seconds_bucket{le="+Inf"} `,
+	} {
+		if !strings.Contains(body, expect) {
+			t.Fatalf("%q not in:\n%s", expect, body)
+		}
+	}
+
+	values := labeledValues{labels: []string{"a", "b"}}
+	values.add(2, `x"y`, "a\\b\nc")
+	var out strings.Builder
+	values.write(&out, "m")
+	if out.String() != `m{a="x\"y",b="a\\b\nc"} 2`+"\n" {
+		t.Fatal(out.String())
+	}
+
+	config := *currentConfig()
+	config.Metrics = false
+	setConfig(&config)
+	if body := get("/metrics"); !strings.Contains(body, "404") {
+		t.Fatal(body)
+	}
+}
@@ -0,0 +1,451 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gvalkov/tailon/cmd"
+	"github.com/gvalkov/tailon/tail"
+)
+
+// How long to wait for a process to exit after SIGTERM before sending SIGKILL.
+const stopTimeout = 2 * time.Second
+
+// Resolve the chain of commands that provide stdin to a command, starting with
+// the command that has no stdin source. For example, with the default config:
+//
+//	commandChain(specs, "grep") -> ["tail", "grep"]
+func commandChain(specs map[string]CommandSpec, name string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+
+	for name != "" {
+		if seen[name] {
+			return nil, fmt.Errorf("stdin cycle: %s -> %s", strings.Join(reversed(chain), " -> "), name)
+		}
+		spec, ok := specs[name]
+		if !ok {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("unknown command %q", name)
+			}
+			return nil, fmt.Errorf("command %q reads stdin from unknown command %q", chain[len(chain)-1], name)
+		}
+		seen[name] = true
+		chain = append(chain, name)
+		name = spec.Stdin
+	}
+
+	return reversed(chain), nil
+}
+
+func reversed(items []string) []string {
+	res := make([]string, len(items))
+	for i, item := range items {
+		res[len(items)-1-i] = item
+	}
+	return res
+}
+
+// A stage is a single command in a pipeline. Stages exchange lines: every stage
+// reads the stdout of the previous one and the first stage reads nothing.
+type stage interface {
+	// Start the stage and return the channel on which it sends its output.
+	// The channel is closed when the stage finishes. Lines written by the
+	// stage to stderr are sent to the stderr channel.
+	start(stdin <-chan string, stderr chan<- string) (<-chan string, error)
+
+	// Stop the stage and wait for it to finish. Stopping a stage that has
+	// finished does nothing.
+	stop()
+}
+
+// pipeline is a chain of stages, in which the stdout of each stage is
+// connected to the stdin of the next.
+type pipeline struct {
+	stages []stage
+	argv   [][]string
+	log    *slog.Logger
+
+	// The output of the last stage, the combined stderr of all stages and the
+	// limits that they exceeded. All are closed after all stages finish.
+	Stdout <-chan string
+	Stderr <-chan string
+	Limits <-chan limitEvent
+}
+
+// Create the pipeline for a frontend command. The arguments of all stages are
+// expanded and builtins are validated, but nothing is started.
+func newPipeline(specs map[string]CommandSpec, fc FrontendCommand) (*pipeline, error) {
+	chain, err := commandChain(specs, fc.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every param must belong to one of the commands of the chain.
+	for param := range fc.Params {
+		if !slices.ContainsFunc(chain, func(name string) bool { _, ok := specs[name].Params[param]; return ok }) {
+			return nil, fmt.Errorf("unknown param %q", param)
+		}
+	}
+
+	p := &pipeline{log: slog.Default()}
+	for n, name := range chain {
+		values, err := commandValues(specs[name], fc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		action := expandCommandArgs(specs[name].Action, values)
+		if len(action) == 0 {
+			return nil, fmt.Errorf("command %q has an empty action", name)
+		}
+
+		var st stage
+		switch action[0] {
+		case builtinTail:
+			if n != 0 {
+				return nil, fmt.Errorf("%s: %s cannot read from stdin", name, builtinTail)
+			}
+			st = &tailStage{path: fc.Entry.Path, nlines: fc.Nlines}
+		case builtinGrep:
+			if n == 0 {
+				return nil, fmt.Errorf("%s: %s needs a stdin command", name, builtinGrep)
+			}
+			filter, err := parseGrepArgs(action[1:])
+			if err != nil {
+				return nil, fmt.Errorf("grep: %s", err)
+			}
+			st = &grepStage{filter: filter}
+		default:
+			spec := specs[name]
+			st = &execStage{
+				command: name,
+				name:    action[0],
+				args:    action[1:],
+				argv:    action,
+				limits:  spec.limits,
+				rate:    outputRate{lines: spec.MaxLinesPerSecond, bytes: spec.MaxBytesPerSecond},
+			}
+		}
+
+		p.stages = append(p.stages, st)
+		p.argv = append(p.argv, action)
+	}
+
+	return p, nil
+}
+
+// Start all stages of the pipeline. If a stage fails to start, the ones that
+// have already been started are stopped.
+func (p *pipeline) start() error {
+	var stdout <-chan string
+	stderr := make(chan string)
+	limits := make(chan limitEvent)
+
+	var wg sync.WaitGroup
+	for n, st := range p.stages {
+		if st, ok := st.(*execStage); ok {
+			st.limitEvents, st.log = limits, p.log
+		}
+		stageStderr := make(chan string)
+		out, err := st.start(stdout, stageStderr)
+		if err != nil {
+			close(stageStderr)
+			for _, started := range p.stages[:n] {
+				started.stop()
+			}
+			return err
+		}
+		p.log.Info("Running command", "argv", p.argv[n])
+		stdout = out
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for line := range stageStderr {
+				stderr <- line
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(stderr)
+		close(limits)
+	}()
+
+	// Once the last stage finishes, nothing reads the output of the others,
+	// which would otherwise run until the pipeline is stopped.
+	last := stdout
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for line := range last {
+			out <- line
+		}
+		for _, st := range p.stages[:len(p.stages)-1] {
+			st.stop()
+		}
+	}()
+
+	p.Stdout = out
+	p.Stderr = stderr
+	p.Limits = limits
+	return nil
+}
+
+// Stop all stages, starting with the first one. Stages can be stopped more than
+// once and concurrently.
+func (p *pipeline) stop() {
+	for _, st := range p.stages {
+		st.stop()
+	}
+}
+
+// processStatus describes how an external command of a pipeline finished.
+// Signal is set if the process was killed by a signal and Stopped if that
+// happened because the pipeline was stopped.
+type processStatus struct {
+	Argv    []string `json:"argv"`
+	Code    int      `json:"code"`
+	Signal  string   `json:"signal,omitempty"`
+	Stopped bool     `json:"stopped"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Return the status of the external commands of a finished pipeline.
+func (p *pipeline) statuses() []processStatus {
+	var res []processStatus
+	for n, st := range p.stages {
+		if st, ok := st.(*execStage); ok && st.proc != nil {
+			res = append(res, st.status(p.argv[n]))
+		}
+	}
+	return res
+}
+
+// --------------------------------------------------------------------------
+
+// execStage runs an external command.
+type execStage struct {
+	command string // the name of the command spec
+	name    string
+	args    []string
+	argv    []string
+	proc    *cmd.Cmd
+	stopped atomic.Bool
+	log     *slog.Logger
+
+	// The resource limits of the command. Exceeded limits are reported on
+	// limitEvents, which is set by the pipeline.
+	limits      cmd.Limits
+	rate        outputRate
+	limitEvents chan<- limitEvent
+}
+
+func (s *execStage) start(stdin <-chan string, stderr chan<- string) (<-chan string, error) {
+	options := cmd.Options{Buffered: false, Streaming: true, Limits: s.limits}
+	s.proc = cmd.NewCmdOptions(options, s.name, s.args...)
+	if stdin != nil {
+		reader, err := linesReader(stdin)
+		if err != nil {
+			return nil, err
+		}
+		s.proc.Stdin = reader
+	}
+
+	stdout := make(chan string)
+	statusChan := s.proc.Start()
+	metrics.Processes.add(1, s.command)
+
+	// Forward output until the command finishes, then drain what is left.
+	go func() {
+		defer close(stderr)
+		defer close(stdout)
+
+		throttled := false
+		send := func(line string) {
+			wait, limit := s.rate.take(len(line) + 1)
+			if limit != "" && !throttled {
+				throttled = true
+				s.reportLimit(limit)
+			}
+			// A stopped command is not throttled, so that it can exit.
+			if wait > 0 && !s.stopped.Load() {
+				time.Sleep(wait)
+			}
+			stdout <- line
+		}
+
+		for {
+			select {
+			case line := <-s.proc.Stdout:
+				send(line)
+			case line := <-s.proc.Stderr:
+				stderr <- line
+			case status := <-statusChan:
+				for len(s.proc.Stdout) > 0 {
+					send(<-s.proc.Stdout)
+				}
+				for len(s.proc.Stderr) > 0 {
+					stderr <- <-s.proc.Stderr
+				}
+				if status.Error != nil && status.PID == 0 {
+					stderr <- fmt.Sprintf("%s: %s", s.name, status.Error)
+				}
+				if status.Limit != "" {
+					s.reportLimit(status.Limit)
+				}
+				metrics.Processes.add(-1, s.command)
+				if status.PID != 0 {
+					metrics.ProcessExits.add(1, s.command, s.status(nil).exitCode())
+				}
+				// Nothing reads stdin anymore, which releases the previous stage.
+				if s.proc.Stdin != nil {
+					s.proc.Stdin.Close()
+				}
+				return
+			}
+		}
+	}()
+
+	return stdout, nil
+}
+
+func (s *execStage) stop() {
+	if s.proc == nil {
+		return
+	}
+
+	if s.proc.Stdin != nil {
+		s.proc.Stdin.Close()
+	}
+
+	select {
+	case <-s.proc.Done():
+		return
+	default:
+	}
+
+	s.stopped.Store(true)
+	pid := s.proc.Status().PID
+	s.log.Debug("Stopping command", "pid", pid, "argv", s.argv)
+	s.proc.Stop()
+
+	select {
+	case <-s.proc.Done():
+	case <-time.After(stopTimeout):
+		if pid := s.proc.Status().PID; pid > 0 {
+			syscall.Kill(-pid, syscall.SIGKILL)
+		}
+		<-s.proc.Done()
+	}
+}
+
+func (s *execStage) reportLimit(limit string) {
+	var message string
+	switch limit {
+	case cmd.LimitTimeout:
+		message = fmt.Sprintf("%s: stopped after the timeout of %s", s.name, s.limits.Timeout)
+	case cmd.LimitCPUTime:
+		message = fmt.Sprintf("%s: killed after the CPU time limit of %s", s.name, s.limits.CPUTime)
+	case limitLinesPerSecond:
+		message = fmt.Sprintf("%s: output limited to %d lines per second", s.name, s.rate.lines)
+	case limitBytesPerSecond:
+		message = fmt.Sprintf("%s: output limited to %d bytes per second", s.name, s.rate.bytes)
+	}
+	if s.limitEvents != nil {
+		s.limitEvents <- limitEvent{Argv: s.argv, Limit: limit, Message: message}
+	}
+}
+
+// The exit code of a process, or the signal that killed it, as a metric label.
+func (status processStatus) exitCode() string {
+	if status.Signal != "" {
+		return status.Signal
+	}
+	return strconv.Itoa(status.Code)
+}
+
+func (s *execStage) status(argv []string) processStatus {
+	status := s.proc.Status()
+	res := processStatus{Argv: argv, Code: status.Exit, Stopped: s.stopped.Load()}
+	if status.Error != nil {
+		if signal, ok := strings.CutPrefix(status.Error.Error(), "signal: "); ok {
+			res.Signal = signal
+		} else {
+			res.Error = status.Error.Error()
+		}
+	}
+	return res
+}
+
+// tailStage follows a file with the builtin "@tail".
+type tailStage struct {
+	path   string
+	nlines int
+	follow *tail.Subscription
+}
+
+func (s *tailStage) start(stdin <-chan string, stderr chan<- string) (<-chan string, error) {
+	s.follow = tailHub.Follow(s.path, s.nlines)
+	go func() {
+		defer close(stderr)
+		for line := range s.follow.Stderr {
+			stderr <- line
+		}
+	}()
+	return s.follow.Stdout, nil
+}
+
+func (s *tailStage) stop() {
+	if s.follow != nil {
+		s.follow.Close()
+	}
+}
+
+// grepStage filters lines with the builtin "@grep".
+type grepStage struct {
+	filter *grepFilter
+}
+
+func (s *grepStage) start(stdin <-chan string, stderr chan<- string) (<-chan string, error) {
+	close(stderr)
+	stdout := make(chan string)
+	go s.filter.run(stdin, stdout)
+	return stdout, nil
+}
+
+// The filter stops when the previous stage closes its stdout.
+func (s *grepStage) stop() {}
+
+// Convert a channel of lines into a pipe that can be used as the stdin of a
+// command. The pipe reaches EOF when the channel is closed. Once the command
+// has exited and the pipe is closed, the remaining lines are discarded so that
+// the previous stage does not block. The pipe is a file, so that the command
+// can be waited for without waiting for the next line.
+func linesReader(lines <-chan string) (*os.File, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for line := range lines {
+			if _, err := io.WriteString(writer, line+"\n"); err != nil {
+				break
+			}
+		}
+		writer.Close()
+		for range lines {
+		}
+	}()
+	return reader, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandChain(t *testing.T) {
+	specs := map[string]CommandSpec{
+		"tail": {Action: []string{"tail"}},
+		"grep": {Stdin: "tail", Action: []string{"grep"}},
+		"sed":  {Stdin: "grep", Action: []string{"sed"}},
+		"a":    {Stdin: "b"},
+		"b":    {Stdin: "a"},
+		"c":    {Stdin: "missing"},
+	}
+
+	if chain, err := commandChain(specs, "sed"); err != nil || strings.Join(chain, " ") != "tail grep sed" {
+		t.Fatalf("%q %v", chain, err)
+	}
+
+	errors := map[string]string{
+		"a":       "stdin cycle: b -> a -> a",
+		"c":       `command "c" reads stdin from unknown command "missing"`,
+		"missing": `unknown command "missing"`,
+	}
+	for name, expect := range errors {
+		if _, err := commandChain(specs, name); err == nil || err.Error() != expect {
+			t.Fatalf("%s: %v != %s", name, err, expect)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	os.WriteFile(path, []byte("a1\nb2\na3\nb4\n"), 0644)
+
+	specs := map[string]CommandSpec{
+		"tail": {Action: []string{"@tail"}},
+		"grep": {Stdin: "tail", Action: []string{"@grep", "-e", "$script"}},
+		"sed":  {Stdin: "grep", Action: []string{"sed", "-u", "-e", "s/a/x/"}},
+		"bad":  {Stdin: "tail", Action: []string{"/nonexistent"}},
+	}
+
+	fc := FrontendCommand{Command: "sed", Script: "a", Entry: ListEntry{Path: path}, Nlines: 10}
+	pipe, err := newPipeline(specs, fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pipe.start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for len(lines) < 2 {
+		select {
+		case line := <-pipe.Stdout:
+			lines = append(lines, line)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout: %q", lines)
+		}
+	}
+	if strings.Join(lines, " ") != "x1 x3" {
+		t.Fatalf("%q != x1 x3", lines)
+	}
+
+	pipe.stop()
+	for range pipe.Stdout {
+	}
+	for range pipe.Stderr {
+	}
+
+	fc.Command = "bad"
+	pipe, _ = newPipeline(specs, fc)
+	pipe.start()
+	go func() {
+		for range pipe.Stdout {
+		}
+	}()
+	if line := <-pipe.Stderr; !strings.HasPrefix(line, "/nonexistent: ") {
+		t.Fatalf("unexpected stderr: %q", line)
+	}
+	pipe.stop()
+
+	fc.Script = "("
+	fc.Command = "grep"
+	if _, err := newPipeline(specs, fc); err == nil {
+		t.Fatal("expected an error for an invalid pattern")
+	}
+
+	fc.Script, fc.Params = "a", map[string]any{"count": 1.0}
+	if _, err := newPipeline(specs, fc); err == nil || err.Error() != `unknown param "count"` {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// The placeholders that every command can use in its action, in addition to
+// the names of its params:
+//
+//	lines     the number of lines to tail
+//	path      the path of the file
+//	script    the script from the UI
+//	alias     the name of the file in the UI
+//	group     the filespec group of the file
+//	dir       the directory of the file
+//	basename  the name of the file without its directory
+//	user      the name of the authenticated user
+var commandPlaceholders = []string{"lines", "path", "script", "alias", "group", "dir", "basename", "user"}
+
+// A placeholder in an argument of an action: $name or ${name}. "$$" is a
+// literal "$".
+var placeholderPattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// The name of a param, which can be used as a placeholder.
+var paramNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// The types of params.
+var paramTypes = []string{"string", "int", "bool"}
+
+// ParamSpec is a parameter of a command, which clients can set in the params
+// of a stream request. For example:
+//
+//	[commands.grep]
+//	action = ["grep", "--max-count=$count", "-e", "$script", "$path"]
+//	params.count = { type = "int", default = 1000 }
+type ParamSpec struct {
+	Type    string
+	Default any
+	Values  []string
+
+	// The default as it is expanded.
+	value string
+}
+
+// Parse the params of a command spec.
+func (spec *CommandSpec) parseParams() error {
+	for name, param := range spec.Params {
+		if !paramNamePattern.MatchString(name) || slices.Contains(commandPlaceholders, name) {
+			return fmt.Errorf("invalid param name %q", name)
+		}
+		if param.Type == "" {
+			param.Type = "string"
+		}
+		if !slices.Contains(paramTypes, param.Type) {
+			return fmt.Errorf("param %s: invalid type %q (expected string, int or bool)", name, param.Type)
+		}
+		if len(param.Values) > 0 && param.Type != "string" {
+			return fmt.Errorf("param %s: values require type string", name)
+		}
+
+		var err error
+		switch {
+		case param.Default != nil:
+			param.value, err = param.parse(param.Default)
+		case param.Type == "int":
+			param.value = "0"
+		case param.Type == "bool":
+			param.value = "false"
+		case len(param.Values) > 0:
+			param.value = param.Values[0]
+		}
+		if err != nil {
+			return fmt.Errorf("param %s: invalid default: %s", name, err)
+		}
+		spec.Params[name] = param
+	}
+	return nil
+}
+
+// Convert the value of a param, as given by a client or in the config file,
+// to the string that it is expanded to.
+func (param ParamSpec) parse(value any) (string, error) {
+	switch param.Type {
+	case "int":
+		switch v := value.(type) {
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case float64:
+			if v == float64(int64(v)) {
+				return strconv.FormatInt(int64(v), 10), nil
+			}
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return strconv.FormatInt(n, 10), nil
+			}
+		}
+		return "", fmt.Errorf("expected an integer, got %v", value)
+	case "bool":
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return strconv.FormatBool(b), nil
+			}
+		}
+		return "", fmt.Errorf("expected a boolean, got %v", value)
+	default:
+		v, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("expected a string, got %v", value)
+		}
+		if len(param.Values) > 0 && !slices.Contains(param.Values, v) {
+			return "", fmt.Errorf("%q is not one of %q", v, param.Values)
+		}
+		return v, nil
+	}
+}
+
+// The values of the placeholders of a command for fc.
+func commandValues(spec CommandSpec, fc FrontendCommand) (map[string]string, error) {
+	path := fc.Entry.Path
+	values := map[string]string{
+		"lines":    strconv.Itoa(fc.Nlines),
+		"path":     path,
+		"script":   fc.Script,
+		"alias":    fc.Entry.Alias,
+		"group":    fc.Group,
+		"dir":      filepath.Dir(path),
+		"basename": filepath.Base(path),
+		"user":     identityUser(fc.identity),
+	}
+	if values["alias"] == "" {
+		values["alias"] = path
+	}
+	if fc.Group == "" {
+		values["group"] = registry.fileGroup(path, fc.identity)
+	}
+
+	for name, param := range spec.Params {
+		values[name] = param.value
+		if value, ok := fc.Params[name]; ok {
+			var err error
+			if values[name], err = param.parse(value); err != nil {
+				return nil, fmt.Errorf("param %s: %s", name, err)
+			}
+		}
+	}
+	return values, nil
+}
+
+// Expand the placeholders in the arguments of an action. A placeholder can be
+// a whole argument or a part of one. Names that are not placeholders are left
+// as they are, so that shell and awk variables still work. For example:
+//
+//	["tail", "-n", "$lines", "-F", "$path"] -> ["tail", "-n", "10", "-F", "f1.txt"]
+//	["cat", "${path}.1", "$$HOME"] -> ["cat", "f1.txt.1", "$HOME"]
+func expandCommandArgs(action []string, values map[string]string) []string {
+	res := make([]string, 0, len(action))
+	for _, arg := range action {
+		res = append(res, placeholderPattern.ReplaceAllStringFunc(arg, func(match string) string {
+			if match == "$$" {
+				return "$"
+			}
+			submatches := placeholderPattern.FindStringSubmatch(match)
+			if value, ok := values[submatches[1]+submatches[2]]; ok {
+				return value
+			}
+			return match
+		}))
+	}
+	return res
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandCommandArgs(t *testing.T) {
+	values := map[string]string{"path": "/var/log/a.log", "lines": "10", "script": "$1 ~ /x/", "count": "5"}
+
+	tests := []struct {
+		action []string
+		expect []string
+	}{
+		{[]string{"tail", "-n", "$lines", "-F", "$path"}, []string{"tail", "-n", "10", "-F", "/var/log/a.log"}},
+		{[]string{"--lines=$lines", "$path.1", "${path}x"}, []string{"--lines=10", "/var/log/a.log.1", "/var/log/a.logx"}},
+		{[]string{"$pathx", "${nope}", "$HOME", "$1", "$", "a$"}, []string{"$pathx", "${nope}", "$HOME", "$1", "$", "a$"}},
+		{[]string{"$$", "$$path", "$$$path", "$$$$"}, []string{"$", "$path", "$/var/log/a.log", "$$"}},
+		{[]string{"${path", "$script", "-m$count"}, []string{"${path", "$1 ~ /x/", "-m5"}},
+		{[]string{}, []string{}},
+	}
+	for _, test := range tests {
+		if res := expandCommandArgs(test.action, values); !reflect.DeepEqual(res, test.expect) {
+			t.Errorf("%q: %q != %q", test.action, res, test.expect)
+		}
+	}
+}
+
+func TestCommandValues(t *testing.T) {
+	spec := CommandSpec{Params: map[string]ParamSpec{
+		"count":  {Type: "int", Default: int64(100)},
+		"level":  {Values: []string{"info", "error"}},
+		"follow": {Type: "bool"},
+	}}
+	if err := spec.parseParams(); err != nil {
+		t.Fatal(err)
+	}
+
+	fc := FrontendCommand{
+		Entry:    ListEntry{Path: "/var/log/app/a.log"},
+		Nlines:   10,
+		identity: &Identity{User: "alice"},
+	}
+	values, err := commandValues(spec, fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"lines": "10", "path": "/var/log/app/a.log", "script": "", "alias": "/var/log/app/a.log", "group": "",
+		"dir": "/var/log/app", "basename": "a.log", "user": "alice", "count": "100", "level": "info", "follow": "false",
+	}
+	if !reflect.DeepEqual(values, expect) {
+		t.Fatalf("%q != %q", values, expect)
+	}
+
+	tests := []struct {
+		params map[string]any
+		expect string
+	}{
+		{map[string]any{"count": 5.0}, "count=5"},
+		{map[string]any{"count": "7"}, "count=7"},
+		{map[string]any{"follow": true}, "follow=true"},
+		{map[string]any{"follow": "1"}, "follow=true"},
+		{map[string]any{"level": "error"}, "level=error"},
+		{map[string]any{"count": 1.5}, "param count: expected an integer, got 1.5"},
+		{map[string]any{"count": "many"}, "param count: expected an integer, got many"},
+		{map[string]any{"follow": "maybe"}, "param follow: expected a boolean, got maybe"},
+		{map[string]any{"level": "debug"}, `param level: "debug" is not one of ["info" "error"]`},
+		{map[string]any{"level": 1.0}, "param level: expected a string, got 1"},
+	}
+	for _, test := range tests {
+		fc.Params = test.params
+		values, err := commandValues(spec, fc)
+		var res string
+		if err != nil {
+			res = err.Error()
+		} else {
+			for name := range test.params {
+				res = name + "=" + values[name]
+			}
+		}
+		if res != test.expect {
+			t.Errorf("%v: %q != %q", test.params, res, test.expect)
+		}
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	errors := map[string]string{
+		`[commands.x]
+		 action = ["x"]
+		 params.path = {}`: `invalid param name "path"`,
+		`[commands.x]
+		 action = ["x"]
+		 params.a-b = {}`: `invalid param name "a-b"`,
+		`[commands.x]
+		 action = ["x"]
+		 params.n = { type = "float" }`: `param n: invalid type "float"`,
+		`[commands.x]
+		 action = ["x"]
+		 params.n = { type = "int", default = "ten" }`: "param n: invalid default: expected an integer, got ten",
+		`[commands.x]
+		 action = ["x"]
+		 params.n = { type = "int", values = ["1"] }`: "param n: values require type string",
+		`[commands.x]
+		 action = ["x"]
+		 params.s = { values = ["a"], default = "b" }`: `param s: invalid default: "b" is not one of ["a"]`,
+	}
+	for content, expect := range errors {
+		if _, err := makeConfig(content); err == nil || !strings.Contains(err.Error(), expect) {
+			t.Errorf("%s: %v does not contain %q", content, err, expect)
+		}
+	}
+}
@@ -0,0 +1,735 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/igm/sockjs-go/v3/sockjs"
+)
+
+// The version of the typed websocket protocol.
+//
+// Clients of the typed protocol send JSON envelopes with a "v", "type", "id"
+// and "payload" key. All replies and events that result from a request carry
+// the id of the request. The following requests are understood:
+//
+//	{"v": 1, "type": "list", "id": "1"}
+//	{"v": 1, "type": "watch", "id": "2"}
+//	{"v": 1, "type": "stream", "id": "3", "stream": "left", "payload": {"command": "grep",
+//	 "script": "error", "entry": {"path": "/var/log/messages"}, "nlines": 10}}
+//	{"v": 1, "type": "stream", "id": "4", "stream": "all", "payload": {"command": "tail",
+//	 "group": "app", "interleave": true, "nlines": 10}}
+//	{"v": 1, "type": "stream", "id": "5", "stream": "head", "payload": {"command": "head",
+//	 "entry": {"path": "/var/log/messages"}, "params": {"count": 20}}}
+//	{"v": 1, "type": "pause", "id": "6", "stream": "left"}
+//	{"v": 1, "type": "resume", "id": "7", "stream": "left"}
+//	{"v": 1, "type": "close", "id": "8", "stream": "left"}
+//
+// The server replies with "listing" to "list" and pushes "listing-changed"
+// after "watch". A "stream" request opens a stream, or replaces the command
+// of an existing stream with the same name. Its "params" set the params of
+// the command, which are checked against their types. A session can have
+// several streams, which are identified by the "stream" key of requests and
+// events. The events of a stream carry the id of the request that opened it,
+// while "pause" and "resume" are answered with "paused" and "resumed" events
+// that carry their own id.
+// Each stream sends a "started" event, "stdout" and "stderr" events with a
+// line, or an array of lines if several were waiting to be sent, an "exited"
+// or "killed" event for every process of the command and finally an "ended"
+// event. A "skipped" event with a count takes the place of lines that were
+// dropped because the client could not keep up. A "limit" event reports that
+// a process exceeded one of the resource limits of its command. A paused stream
+// stops reading the output of its command until it is resumed.
+//
+// A merged stream runs the command for several files, given as "entries" or
+// as a "group", and tags every "stdout" event with the alias of its file in
+// the "source" key. With "interleave", lines are ordered by their timestamps
+// within a reorder window of "window" milliseconds (500 by default). Requests
+// that cannot be served result in an "error" reply with a code and a message.
+//
+// When the config is reloaded, streams whose file or command is no longer
+// allowed are stopped with an "error" event on the stream, and clients are
+// pushed "commands-changed" with the commands that they can now run and their
+// default scripts. Other streams keep running.
+//
+// The legacy protocol, in which the client sends "list" or a bare
+// FrontendCommand and the server replies with ["o", line] and ["e", line]
+// arrays, is still supported. It has a single stream.
+const protocolVersion = 1
+
+// Message is the envelope of the typed websocket protocol.
+type Message struct {
+	V       int             `json:"v"`
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Stream  string          `json:"stream,omitempty"`
+	Source  string          `json:"source,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// ErrorPayload is the payload of an "error" reply.
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error codes of the typed protocol.
+const (
+	errBadJSON            = "bad-json"
+	errBadRequest         = "bad-request"
+	errUnsupportedVersion = "unsupported-version"
+	errUnknownType        = "unknown-type"
+	errUnknownFile        = "unknown-file"
+	errUnknownCommand     = "unknown-command"
+	errForbidden          = "forbidden"
+	errStartFailed        = "start-failed"
+	errUnknownStream      = "unknown-stream"
+	errTooManyStreams     = "too-many-streams"
+	errTooManySessions    = "too-many-sessions"
+)
+
+// The sockjs close codes of sessions that are rejected by admission control
+// and of sessions whose client stopped reading.
+const (
+	closeRejected = 4000
+	closeIdle     = 4001
+)
+
+// How long a rejected session is kept open to tell the client why.
+const rejectTimeout = 5 * time.Second
+
+// The maximum number of streams of a session.
+const maxStreams = 16
+
+// ListingChangedMessage is pushed to legacy clients that watch the file
+// listing. It is a JSON object, like the reply to "list", but has a "type" key.
+type ListingChangedMessage struct {
+	Type    string          `json:"type"`
+	Changes []ListingChange `json:"changes"`
+}
+
+// CommandsChangedMessage is pushed to legacy clients that watch the file
+// listing when a reloaded config changes the commands that they can run.
+type CommandsChangedMessage struct {
+	Type     string            `json:"type"`
+	Commands []string          `json:"commands"`
+	Scripts  map[string]string `json:"scripts"`
+}
+
+// wsSession is the state of a single websocket connection.
+type wsSession struct {
+	queue    *sendQueue
+	identity *Identity
+	client   string
+	remote   string
+	log      *slog.Logger
+
+	// Cancelled when the connection closes, which stops all streams.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Set once the client sends a message of the typed protocol. Replies that
+	// are not tied to a request use the protocol of the last request.
+	typed bool
+
+	// The commands that are streaming to the client, keyed by stream name.
+	streams map[string]*wsStream
+
+	// Clients that send "watch" are pushed the changes to the file listing
+	// since the last listing that they received.
+	listingChanged <-chan struct{}
+	unsubscribe    func()
+	lastListing    map[string][]*ListEntry
+	watchID        string
+
+	// The commands and scripts that the client last knew about.
+	commands []string
+	scripts  map[string]string
+}
+
+// Goroutine handling received messages and streaming of file contents.
+func wsWriter(session sockjs.Session, messages chan string, done <-chan struct{}) {
+	config := currentConfig()
+	s := &wsSession{
+		queue:    newSendQueue(session, config.SendQueuePolicy, config.SendQueueSize, config.SendBatchSize),
+		identity: requestIdentity(session.Request()),
+		client:   clientAddr(session.Request()),
+		remote:   session.Request().RemoteAddr,
+		log:      sessionLogger(session),
+		streams:  make(map[string]*wsStream),
+	}
+	s.commands, s.scripts = allowedCommands(s.identity), config.CommandScripts
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	defer s.close()
+
+	configReloaded, unsubscribe := reloads.subscribe()
+	defer unsubscribe()
+
+	var idleCheck <-chan time.Time
+	idleTimeout := config.IdleTimeout
+	if idleTimeout > 0 {
+		ticker := time.NewTicker(idleTimeout / 4)
+		defer ticker.Stop()
+		idleCheck = ticker.C
+	}
+
+	for {
+		select {
+		case msg := <-messages:
+			s.handle(msg)
+		case <-s.listingChanged:
+			s.pushListingChanges()
+		case <-configReloaded:
+			s.reload()
+		case <-idleCheck:
+			if s.queue.stalled() >= idleTimeout {
+				s.log.Warn("Closing idle session", "idle-timeout", idleTimeout)
+				// Closing waits until the client reads or its connection is lost.
+				go session.Close(closeIdle, "idle timeout")
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// Tell a client whose session was not admitted why, in the protocol of its
+// first message, and close the session.
+func rejectSession(session sockjs.Session, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), rejectTimeout)
+	defer cancel()
+
+	if msg, err := session.RecvCtx(ctx); err == nil {
+		var envelope Message
+		if json.Unmarshal([]byte(msg), &envelope) == nil && envelope.Type != "" {
+			session.Send(formatMessage(Message{Type: "error", ID: envelope.ID}, ErrorPayload{Code: errTooManySessions, Message: reason}))
+		} else {
+			data, _ := json.Marshal([]string{"e", reason})
+			session.Send(string(data))
+		}
+	}
+	session.Close(closeRejected, reason)
+}
+
+// Stop all streams and wait until their commands have exited.
+func (s *wsSession) close() {
+	s.queue.close()
+	s.cancel()
+	for _, st := range s.streams {
+		<-st.done
+	}
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+	if dropped := s.queue.droppedLines(); dropped > 0 {
+		s.log.Warn("Dropped lines that the client could not keep up with", "lines", dropped)
+	}
+}
+
+// Dispatch a message of either protocol.
+func (s *wsSession) handle(msg string) {
+	switch {
+	case msg == "list":
+		s.typed = false
+		s.list("")
+		return
+	case msg == "watch":
+		s.typed = false
+		s.watch("")
+		return
+	case !strings.HasPrefix(msg, "{"):
+		s.sendError("", errBadJSON, fmt.Sprintf("unknown message %q", msg))
+		return
+	}
+
+	var envelope Message
+	if err := json.Unmarshal([]byte(msg), &envelope); err != nil {
+		s.sendError("", errBadJSON, "bad message: "+err.Error())
+		return
+	}
+
+	// Legacy clients send a FrontendCommand, which has no "type" key.
+	if envelope.Type == "" {
+		s.typed = false
+		fc := FrontendCommand{}
+		if err := json.Unmarshal([]byte(msg), &fc); err != nil {
+			s.sendError("", errBadJSON, "bad message: "+err.Error())
+			return
+		}
+		s.stream("", "", fc)
+		return
+	}
+
+	s.typed = true
+	if envelope.V != protocolVersion {
+		s.sendError(envelope.ID, errUnsupportedVersion, fmt.Sprintf("unsupported protocol version %d (expected %d)", envelope.V, protocolVersion))
+		return
+	}
+
+	switch envelope.Type {
+	case "list":
+		s.list(envelope.ID)
+	case "watch":
+		s.watch(envelope.ID)
+	case "stream":
+		fc := FrontendCommand{}
+		if len(envelope.Payload) == 0 {
+			s.sendError(envelope.ID, errBadRequest, "missing payload")
+			return
+		}
+		if err := json.Unmarshal(envelope.Payload, &fc); err != nil {
+			s.sendError(envelope.ID, errBadJSON, "bad payload: "+err.Error())
+			return
+		}
+		s.stream(envelope.ID, envelope.Stream, fc)
+	case "pause", "resume", "close":
+		st := s.streams[envelope.Stream]
+		if st == nil {
+			s.sendError(envelope.ID, errUnknownStream, fmt.Sprintf("unknown stream %q", envelope.Stream))
+			return
+		}
+		switch envelope.Type {
+		case "pause":
+			st.setPaused(true)
+			s.sendEvent("paused", envelope.ID, envelope.Stream, nil)
+		case "resume":
+			st.setPaused(false)
+			s.sendEvent("resumed", envelope.ID, envelope.Stream, nil)
+		case "close":
+			st.close()
+			delete(s.streams, envelope.Stream)
+		}
+	default:
+		s.sendError(envelope.ID, errUnknownType, fmt.Sprintf("unknown message type %q", envelope.Type))
+	}
+}
+
+func (s *wsSession) list(id string) {
+	lst := registry.listing(s.identity)
+	s.lastListing = lst
+
+	if s.typed {
+		s.send("listing", id, lst)
+		return
+	}
+	b, err := json.Marshal(lst)
+	if err != nil {
+		s.log.Error("Cannot encode listing", "error", err)
+	}
+	s.queue.send(string(b))
+}
+
+func (s *wsSession) watch(id string) {
+	if s.listingChanged == nil {
+		s.listingChanged, s.unsubscribe = registry.subscribe()
+	}
+	if s.lastListing == nil {
+		s.lastListing = registry.listing(s.identity)
+	}
+	s.watchID = id
+}
+
+func (s *wsSession) pushListingChanges() {
+	lst := registry.listing(s.identity)
+	changes := diffListings(s.lastListing, lst)
+	s.lastListing = lst
+	if len(changes) == 0 {
+		return
+	}
+
+	if s.typed {
+		s.send("listing-changed", s.watchID, map[string]any{"changes": changes})
+		return
+	}
+	b, _ := json.Marshal(ListingChangedMessage{Type: "listing-changed", Changes: changes})
+	s.queue.send(string(b))
+}
+
+// Apply a reloaded config. Streams whose files or command are no longer allowed
+// are stopped, and the client is told about changes to its commands and, if it
+// watches the listing, to its files.
+func (s *wsSession) reload() {
+	config := currentConfig()
+
+	for _, name := range slices.Sorted(maps.Keys(s.streams)) {
+		st := s.streams[name]
+		code, message := s.revoked(st, config)
+		if code == "" {
+			continue
+		}
+		select {
+		case <-st.done:
+			continue
+		default:
+		}
+
+		s.log.Info("Stopping stream after config reload", "stream", name, "reason", message)
+		if st.typed {
+			st.send("error", ErrorPayload{Code: code, Message: message})
+		} else {
+			data, _ := json.Marshal([]string{"e", "tailon: " + message})
+			s.queue.send(string(data))
+		}
+		st.close()
+		delete(s.streams, name)
+	}
+
+	commands := allowedCommands(s.identity)
+	if !slices.Equal(commands, s.commands) || !maps.Equal(config.CommandScripts, s.scripts) {
+		s.commands, s.scripts = commands, config.CommandScripts
+		if s.typed {
+			s.send("commands-changed", "", map[string]any{"commands": commands, "scripts": config.CommandScripts})
+		} else if s.listingChanged != nil {
+			b, _ := json.Marshal(CommandsChangedMessage{Type: "commands-changed", Commands: commands, Scripts: config.CommandScripts})
+			s.queue.send(string(b))
+		}
+	}
+
+	// Access control may hide files from the client without changing the
+	// listing itself.
+	if s.listingChanged != nil {
+		s.pushListingChanges()
+	}
+}
+
+// Return why a stream may no longer run under config, or empty strings.
+func (s *wsSession) revoked(st *wsStream, config *Config) (code, message string) {
+	if !slices.Contains(config.AllowCommandNames, st.command) || !config.ACL.AllowCommand(s.identity, st.command) {
+		return errUnknownCommand, "command no longer allowed: " + st.command
+	}
+	for _, path := range st.paths {
+		if !registry.fileAllowed(path, s.identity) {
+			return errUnknownFile, "file no longer available: " + path
+		}
+	}
+	return "", ""
+}
+
+// Start streaming the output of a command. An existing stream with the same
+// name is closed first.
+func (s *wsSession) stream(id, name string, fc FrontendCommand) {
+	config := currentConfig()
+	fc.identity = s.identity
+	merged := fc.Entries != nil || fc.Group != ""
+	entries := fc.Entries
+	if fc.Group != "" {
+		entries = append(entries, derefEntries(registry.listing(s.identity)[fc.Group])...)
+	}
+	if !merged {
+		entries = []ListEntry{fc.Entry}
+	}
+
+	for _, entry := range entries {
+		if !registry.fileAllowed(entry.Path, s.identity) {
+			s.log.Warn("Unknown file", "path", entry.Path)
+			s.sendError(id, errUnknownFile, "unknown file: "+entry.Path)
+			return
+		}
+	}
+
+	if !slices.Contains(config.AllowCommandNames, fc.Command) {
+		s.log.Warn("Command not allowed", "command", fc.Command)
+		s.sendError(id, errUnknownCommand, "command not allowed: "+fc.Command)
+		return
+	}
+	if !config.ACL.AllowCommand(s.identity, fc.Command) {
+		s.log.Warn("Command not allowed", "command", fc.Command)
+		s.sendError(id, errForbidden, "command not allowed: "+fc.Command)
+		return
+	}
+
+	if st := s.streams[name]; st != nil {
+		st.close()
+		delete(s.streams, name)
+	}
+
+	// Streams whose command has finished still count until they are closed.
+	if len(s.streams) >= maxStreams {
+		s.sendError(id, errTooManyStreams, fmt.Sprintf("too many streams (at most %d)", maxStreams))
+		return
+	}
+
+	// Running commands count against the limits of all sessions of a client.
+	commands := 1
+	if merged {
+		commands = len(entries)
+	}
+	if err := admissions.admitCommands(s.client, commands); err != nil {
+		s.log.Warn("Rejected command", "error", err)
+		s.sendError(id, errTooManyStreams, err.Error())
+		return
+	}
+
+	st := &wsStream{
+		queue:    s.queue,
+		typed:    s.typed,
+		id:       id,
+		name:     name,
+		client:   s.client,
+		commands: commands,
+		command:  fc.Command,
+		control:  make(chan bool),
+		done:     make(chan struct{}),
+	}
+	st.ctx, st.cancel = context.WithCancel(s.ctx)
+	started := map[string]any{"command": fc.Command}
+
+	// Builtins are validated before anything is started.
+	if merged {
+		window := time.Duration(0)
+		if fc.Interleave {
+			window = defaultReorderWindow
+			if fc.Window > 0 {
+				window = min(time.Duration(fc.Window)*time.Millisecond, maxReorderWindow)
+			}
+		}
+		m, err := newMerger(config.CommandSpecs, fc, entries, window)
+		if err == nil {
+			m.log = s.log.With("stream", name)
+			err = m.start()
+		}
+		if err != nil {
+			st.cancel()
+			admissions.releaseCommands(s.client, commands)
+			s.sendError(id, errStartFailed, err.Error())
+			return
+		}
+		st.cmd, st.merged, st.stderr, st.limits = m, m.Stdout, m.Stderr, m.Limits
+		started["argv"], started["sources"] = m.argv(), m.aliases
+	} else {
+		pipe, err := newPipeline(config.CommandSpecs, fc)
+		if err == nil {
+			pipe.log = s.log.With("stream", name)
+			err = pipe.start()
+		}
+		if err != nil {
+			st.cancel()
+			admissions.releaseCommands(s.client, commands)
+			s.sendError(id, errStartFailed, err.Error())
+			return
+		}
+		st.cmd, st.stdout, st.stderr, st.limits = pipe, pipe.Stdout, pipe.Stderr, pipe.Limits
+		started["argv"] = pipe.argv
+	}
+
+	if s.typed {
+		s.sendEvent("started", id, name, started)
+	}
+	s.streams[name] = st
+
+	st.audit = AuditEvent{
+		Remote:  s.remote,
+		User:    identityUser(s.identity),
+		Command: fc.Command,
+		Argv:    started["argv"].([][]string),
+	}
+	for _, entry := range entries {
+		st.paths = append(st.paths, entry.Path)
+	}
+	if merged {
+		st.audit.Paths = st.paths
+	} else {
+		st.audit.Path = fc.Entry.Path
+	}
+	st.started = time.Now()
+	st.audit.Event = auditCommandStarted
+	auditLog.log(st.audit)
+
+	// The command is stopped when the stream or the session is closed. Its
+	// remaining output is still read, so that the forwarder can return.
+	context.AfterFunc(st.ctx, st.cmd.stop)
+	go st.forward()
+}
+
+// Send a message of the typed protocol.
+func (s *wsSession) send(typ, id string, payload any) {
+	s.queue.send(formatMessage(Message{Type: typ, ID: id}, payload))
+}
+
+// Send an event of a stream.
+func (s *wsSession) sendEvent(typ, id, stream string, payload any) {
+	s.queue.send(formatMessage(Message{Type: typ, ID: id, Stream: stream}, payload))
+}
+
+// Send an error reply. Legacy clients receive the message on stderr.
+func (s *wsSession) sendError(id, code, message string) {
+	if s.typed {
+		s.send("error", id, ErrorPayload{Code: code, Message: message})
+		return
+	}
+	data, _ := json.Marshal([]string{"e", message})
+	s.queue.send(string(data))
+}
+
+func formatMessage(msg Message, payload any) string {
+	msg.V = protocolVersion
+	if payload != nil {
+		msg.Payload, _ = json.Marshal(payload)
+	}
+	data, _ := json.Marshal(msg)
+	return string(data)
+}
+
+// wsStream is a command whose output is streamed to the client. Every stream
+// is forwarded by its own goroutine, so that a paused stream or a command that
+// floods its output does not hold up the other streams of the session.
+type wsStream struct {
+	queue *sendQueue
+	typed bool
+
+	// The id of the request that started the stream and the name of the stream.
+	id   string
+	name string
+
+	// The client and the number of commands that were admitted for the stream.
+	client   string
+	commands int
+
+	// The name of the command and the files that it runs for.
+	command string
+	paths   []string
+
+	// The command of the stream and its output. Merged streams send their
+	// output on merged instead of stdout.
+	cmd    streamCommand
+	stdout <-chan string
+	merged <-chan sourcedLine
+	stderr <-chan string
+	limits <-chan limitEvent
+
+	// Cancelled when the stream is closed or replaced, or the session ends.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// When the command started and what is written to the audit log about it.
+	started time.Time
+	audit   AuditEvent
+
+	control chan bool
+	done    chan struct{}
+}
+
+// streamCommand is the command behind a stream: a pipeline or a merger.
+type streamCommand interface {
+	stop()
+	statuses() []processStatus
+}
+
+// Goroutine that streams the stdout and stderr of a pipeline to the client.
+// It returns after all stages of the pipeline have finished. Clients of the
+// typed protocol are then told how each process finished.
+func (st *wsStream) forward() {
+	defer close(st.done)
+	defer st.cancel()
+	defer admissions.releaseCommands(st.client, st.commands)
+
+	stdout, merged, stderr, limits := st.stdout, st.merged, st.stderr, st.limits
+	paused := false
+	cancelled := st.ctx.Done()
+
+	for stdout != nil || merged != nil || stderr != nil || limits != nil {
+		// While paused, the output is left in the pipeline, which eventually
+		// blocks the command.
+		out, mergedOut, errs := stdout, merged, stderr
+		if paused {
+			out, mergedOut, errs = nil, nil, nil
+		}
+
+		select {
+		case paused = <-st.control:
+		case <-cancelled:
+			// Drain the output of the stopped command.
+			paused, cancelled = false, nil
+		case line, ok := <-mergedOut:
+			if !ok {
+				merged = nil
+				continue
+			}
+			st.sendLine("stdout", line.Source, line.Line)
+		case line, ok := <-out:
+			if !ok {
+				stdout = nil
+				continue
+			}
+			st.sendLine("stdout", "", line)
+		case line, ok := <-errs:
+			if !ok {
+				stderr = nil
+				continue
+			}
+			st.sendLine("stderr", "", line)
+		case event, ok := <-limits:
+			if !ok {
+				limits = nil
+				continue
+			}
+			if st.typed {
+				st.send("limit", event)
+			} else {
+				st.sendLine("stderr", "", "tailon: "+event.Message)
+			}
+		}
+	}
+
+	statuses := st.cmd.statuses()
+	st.audit.Event = auditCommandEnded
+	st.audit.Duration = time.Since(st.started).Seconds()
+	st.audit.Status = statuses
+	auditLog.log(st.audit)
+
+	if !st.typed {
+		return
+	}
+	for _, status := range statuses {
+		switch {
+		case status.Signal != "":
+			st.send("killed", status)
+		case status.Error != "":
+			st.send("error", ErrorPayload{Code: errStartFailed, Message: status.Error})
+		default:
+			st.send("exited", status)
+		}
+	}
+	st.send("ended", nil)
+}
+
+func (st *wsStream) send(typ string, payload any) {
+	st.queue.send(formatMessage(Message{Type: typ, ID: st.id, Stream: st.name}, payload))
+}
+
+func (st *wsStream) sendLine(kind, source, text string) {
+	metrics.StreamedLines.Add(1)
+	metrics.StreamedBytes.Add(int64(len(text)))
+	line := queuedLine{typed: st.typed, id: st.id, stream: st.name, source: source, kind: kind, text: text}
+	st.queue.sendLine(line, st.ctx.Done())
+}
+
+// Pause or resume forwarding the output of the stream.
+func (st *wsStream) setPaused(paused bool) {
+	select {
+	case st.control <- paused:
+	case <-st.done:
+	}
+}
+
+// Stop the command of the stream and wait until it has exited and its
+// remaining output has been forwarded.
+func (st *wsStream) close() {
+	st.cancel()
+	<-st.done
+}
+
+func derefEntries(entries []*ListEntry) []ListEntry {
+	res := make([]ListEntry, len(entries))
+	for n, entry := range entries {
+		res[n] = *entry
+	}
+	return res
+}
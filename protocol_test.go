@@ -0,0 +1,587 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// A sockjs client that talks to the websocket transport of a test server.
+type wsClient struct {
+	t        *testing.T
+	conn     *websocket.Conn
+	received []string
+
+	// The stdout lines of the typed protocol, keyed by stream.
+	lines map[string][]string
+}
+
+func dialWS(t *testing.T, server *httptest.Server) *wsClient {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/0/0/websocket"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &wsClient{t: t, conn: conn, lines: make(map[string][]string)}
+}
+
+func (c *wsClient) send(msgs ...string) {
+	data, _ := json.Marshal(msgs)
+	if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// Return the next message sent by the server.
+func (c *wsClient) recv() string {
+	for len(c.received) == 0 {
+		c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, frame, err := c.conn.ReadMessage()
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		if frame[0] == 'a' {
+			json.Unmarshal(frame[1:], &c.received)
+		}
+	}
+	msg := c.received[0]
+	c.received = c.received[1:]
+	return msg
+}
+
+// Return the next message of the typed protocol.
+func (c *wsClient) recvMessage() (Message, map[string]any) {
+	msg := Message{}
+	if err := json.Unmarshal([]byte(c.recv()), &msg); err != nil {
+		c.t.Fatal(err)
+	}
+	if msg.Type == "stdout" {
+		var lines []string
+		if json.Unmarshal(msg.Payload, &lines) != nil {
+			lines = make([]string, 1)
+			json.Unmarshal(msg.Payload, &lines[0])
+		}
+		c.lines[msg.Stream] = append(c.lines[msg.Stream], lines...)
+	}
+
+	var payload map[string]any
+	json.Unmarshal(msg.Payload, &payload)
+	return msg, payload
+}
+
+// Receive messages until a stream has sent n lines.
+func (c *wsClient) waitLines(stream string, n int) {
+	for len(c.lines[stream]) < n {
+		c.recvMessage()
+	}
+}
+
+// Skip messages until one with the given request id and type arrives and
+// return its payload.
+func (c *wsClient) recvUntil(id, typ string) map[string]any {
+	for {
+		msg, payload := c.recvMessage()
+		if msg.ID == id && msg.Type == typ {
+			return payload
+		}
+	}
+}
+
+// Start a test server for the files in testdata and the given commands, with
+// options applied to the config.
+func startTestServer(t *testing.T, commands map[string]CommandSpec, options ...func(*Config)) *httptest.Server {
+	spec, _ := parseFileSpec("testdata/ex1/var/log/1.log")
+	config := &Config{CommandSpecs: commands}
+	for name := range commands {
+		config.AllowCommandNames = append(config.AllowCommandNames, name)
+	}
+	for _, option := range options {
+		option(config)
+	}
+	setConfig(config)
+	registry = newFileRegistry([]FileSpec{spec})
+
+	server := httptest.NewServer(setupRoutes("/"))
+	t.Cleanup(func() {
+		server.Close()
+		setConfig(&Config{})
+		registry = newFileRegistry(nil)
+	})
+	return server
+}
+
+func TestProtocol(t *testing.T) {
+	server := startTestServer(t, map[string]CommandSpec{
+		"cat":   {Action: []string{"cat", "$path"}},
+		"exit":  {Action: []string{"sh", "-c", "exit 3"}},
+		"sleep": {Action: []string{"sleep", "60"}},
+	})
+	client := dialWS(t, server)
+	stream := func(id, command, path string) string {
+		return `{"v": 1, "type": "stream", "id": "` + id + `", "payload": {"command": "` + command + `", "entry": {"path": "` + path + `"}}}`
+	}
+
+	errors := []struct {
+		request string
+		code    string
+	}{
+		{`{"v": 2, "type": "list", "id": "1"}`, errUnsupportedVersion},
+		{`{"v": 1, "type": "nope", "id": "1"}`, errUnknownType},
+		{`{"v": 1, "type": "stream", "id": "1"}`, errBadRequest},
+		{`{"v": 1, "type": "stream", "id": "1", "payload": []}`, errBadJSON},
+		{stream("1", "cat", "/etc/passwd"), errUnknownFile},
+		{stream("1", "rm", "testdata/ex1/var/log/1.log"), errUnknownCommand},
+	}
+	for _, test := range errors {
+		client.send(test.request)
+		if msg, payload := client.recvMessage(); msg.Type != "error" || msg.ID != "1" || payload["code"] != test.code {
+			t.Fatalf("%s: unexpected reply %v %v", test.request, msg, payload)
+		}
+	}
+
+	client.send(`{"bad`)
+	if msg, payload := client.recvMessage(); msg.Type != "error" || payload["code"] != errBadJSON {
+		t.Fatalf("unexpected reply %v %v", msg, payload)
+	}
+
+	client.send(`{"v": 1, "type": "list", "id": "2"}`)
+	if msg, payload := client.recvMessage(); msg.Type != "listing" || msg.ID != "2" || payload["__default__"] == nil {
+		t.Fatalf("unexpected reply %v %v", msg, payload)
+	}
+
+	// A command that finishes on its own.
+	client.send(stream("3", "cat", "testdata/ex1/var/log/1.log"))
+	var types []string
+	for len(types) == 0 || types[len(types)-1] != "ended" {
+		msg, payload := client.recvMessage()
+		if msg.ID != "3" {
+			t.Fatalf("unexpected reply %v", msg)
+		}
+		types = append(types, msg.Type)
+		if msg.Type == "exited" && (payload["code"] != 0.0 || payload["stopped"] != false) {
+			t.Fatalf("unexpected exit status %v", payload)
+		}
+	}
+	if types[0] != "started" || types[1] != "stdout" || types[len(types)-2] != "exited" {
+		t.Fatalf("unexpected events %q", types)
+	}
+
+	client.send(stream("4", "exit", "testdata/ex1/var/log/1.log"))
+	if payload := client.recvUntil("4", "exited"); payload["code"] != 3.0 {
+		t.Fatalf("unexpected exit status %v", payload)
+	}
+
+	// A command that is stopped when the next one starts.
+	client.send(stream("5", "sleep", "testdata/ex1/var/log/1.log"))
+	client.recvUntil("5", "started")
+	client.send(stream("6", "exit", "testdata/ex1/var/log/1.log"))
+	if payload := client.recvUntil("5", "killed"); payload["signal"] != "terminated" || payload["stopped"] != true {
+		t.Fatalf("unexpected kill status %v", payload)
+	}
+	client.recvUntil("6", "ended")
+
+	// The legacy protocol.
+	legacy := dialWS(t, server)
+	legacy.send("list")
+	if msg := legacy.recv(); !strings.HasPrefix(msg, `{"__default__":`) {
+		t.Fatalf("unexpected reply %s", msg)
+	}
+	legacy.send(`{"command": "cat", "entry": {"path": "testdata/ex1/var/log/1.log"}}`)
+	if msg := legacy.recv(); !strings.HasPrefix(msg, `["o",`) {
+		t.Fatalf("unexpected reply %s", msg)
+	}
+	legacy.send(`{"command": "cat", "entry": {"path": "/etc/passwd"}}`)
+	for msg := legacy.recv(); msg != `["e","unknown file: /etc/passwd"]`; msg = legacy.recv() {
+		if !strings.HasPrefix(msg, `["o",`) {
+			t.Fatalf("unexpected reply %s", msg)
+		}
+	}
+}
+
+func TestProtocolStreams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	os.WriteFile(path, []byte("1\n2\n"), 0644)
+
+	server := startTestServer(t, map[string]CommandSpec{
+		"cat":  {Action: []string{"cat", "$path"}},
+		"tail": {Action: []string{"tail", "-n", "$lines", "-F", "$path"}},
+	})
+	registry = newFileRegistry([]FileSpec{{Path: path, Type: "file"}})
+	client := dialWS(t, server)
+	request := func(typ, id, stream, command string) string {
+		return `{"v": 1, "type": "` + typ + `", "id": "` + id + `", "stream": "` + stream + `",
+			"payload": {"command": "` + command + `", "entry": {"path": "` + path + `"}, "nlines": 10}}`
+	}
+
+	// Two streams of the same file, one of which is paused.
+	client.send(request("stream", "1", "left", "tail"))
+	client.recvUntil("1", "started")
+	client.send(request("stream", "2", "right", "tail"))
+	client.recvUntil("2", "started")
+	client.send(request("pause", "3", "left", ""))
+	client.recvUntil("3", "paused")
+
+	client.waitLines("right", 2)
+
+	// Output that arrives while a stream is paused is sent after it resumes.
+	file, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	file.WriteString("3\n")
+	file.Close()
+	client.waitLines("right", 3)
+	time.Sleep(100 * time.Millisecond)
+	if len(client.lines["left"]) > 2 {
+		t.Fatalf("output of a paused stream: %q", client.lines["left"])
+	}
+
+	client.send(request("resume", "4", "left", ""))
+	client.waitLines("left", 3)
+	if strings.Join(client.lines["left"], " ") != "1 2 3" || strings.Join(client.lines["right"], " ") != "1 2 3" {
+		t.Fatalf("unexpected output %q", client.lines)
+	}
+
+	// Closing one stream does not affect the other.
+	client.send(request("close", "5", "left", ""))
+	if payload := client.recvUntil("1", "killed"); payload["stopped"] != true {
+		t.Fatalf("unexpected kill status %v", payload)
+	}
+	client.recvUntil("1", "ended")
+
+	client.send(request("pause", "6", "left", ""))
+	if msg, payload := client.recvMessage(); msg.Type != "error" || payload["code"] != errUnknownStream {
+		t.Fatalf("unexpected reply %v %v", msg, payload)
+	}
+
+	// Streams of finished commands count until they are closed.
+	for n := 1; n < maxStreams; n++ {
+		client.send(request("stream", "7", strconv.Itoa(n), "cat"))
+		client.recvUntil("7", "ended")
+	}
+	client.send(request("stream", "8", "extra", "cat"))
+	if payload := client.recvUntil("8", "error"); payload["code"] != errTooManyStreams {
+		t.Fatalf("unexpected error %v", payload)
+	}
+	client.send(request("stream", "9", "right", "cat"))
+	client.recvUntil("9", "ended")
+}
+
+func TestProtocolMerged(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.log"), []byte("2024-05-01T00:00:01Z a1\n2024-05-01T00:00:03Z a2\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "b.log"), []byte("2024-05-01T00:00:02Z b1\n  b1 continued\n"), 0644)
+
+	server := startTestServer(t, map[string]CommandSpec{"cat": {Action: []string{"cat", "$path"}}})
+	spec, _ := parseFileSpec("group=app,alias=app," + filepath.Join(dir, "*.log"))
+	registry = newFileRegistry([]FileSpec{spec})
+	client := dialWS(t, server)
+
+	client.send(`{"v": 1, "type": "stream", "id": "1", "stream": "all",
+		"payload": {"command": "cat", "group": "app", "interleave": true, "window": 50}}`)
+	if payload := client.recvUntil("1", "started"); len(payload["sources"].([]any)) != 2 {
+		t.Fatalf("unexpected sources %v", payload)
+	}
+
+	var res []string
+	for {
+		n := len(client.lines["all"])
+		msg, _ := client.recvMessage()
+		if msg.Type == "ended" {
+			break
+		}
+		for _, line := range client.lines["all"][n:] {
+			res = append(res, msg.Source+" "+line)
+		}
+	}
+	expect := []string{
+		"app/a.log 2024-05-01T00:00:01Z a1",
+		"app/b.log 2024-05-01T00:00:02Z b1",
+		"app/b.log   b1 continued",
+		"app/a.log 2024-05-01T00:00:03Z a2",
+	}
+	if strings.Join(res, "\n") != strings.Join(expect, "\n") {
+		t.Fatalf("%q != %q", res, expect)
+	}
+
+	client.send(`{"v": 1, "type": "stream", "id": "2", "stream": "all",
+		"payload": {"command": "cat", "entries": [{"path": "` + filepath.Join(dir, "a.log") + `"}, {"path": "/etc/passwd"}]}}`)
+	if payload := client.recvUntil("2", "error"); payload["code"] != errUnknownFile {
+		t.Fatalf("unexpected error %v", payload)
+	}
+	client.send(`{"v": 1, "type": "stream", "id": "3", "payload": {"command": "cat", "group": "missing"}}`)
+	if payload := client.recvUntil("3", "error"); payload["code"] != errStartFailed {
+		t.Fatalf("unexpected error %v", payload)
+	}
+}
+
+// The number of child processes of the test.
+func countChildren(t *testing.T) int {
+	stats, _ := filepath.Glob("/proc/[0-9]*/stat")
+	count := 0
+	for _, stat := range stats {
+		data, err := os.ReadFile(stat)
+		if err != nil {
+			continue
+		}
+		// The parent pid is the second field after the command in parentheses.
+		fields := strings.Fields(string(data[strings.LastIndexByte(string(data), ')')+1:]))
+		if len(fields) > 1 && fields[1] == strconv.Itoa(os.Getpid()) {
+			count++
+		}
+	}
+	return count
+}
+
+// Wait until cond holds.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for start := time.Now(); !cond(); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 10*time.Second {
+			t.Fatal("timeout waiting for ", what)
+		}
+	}
+}
+
+func TestStreamLeaks(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("no /proc")
+	}
+
+	path := filepath.Join(t.TempDir(), "1.log")
+	os.WriteFile(path, []byte("a1\nb2\na3\n"), 0644)
+	server := startTestServer(t, map[string]CommandSpec{
+		"tail":  {Action: []string{"tail", "-n", "$lines", "-F", "$path"}},
+		"grep":  {Stdin: "tail", Action: []string{"grep", "--line-buffered", "-e", "$script"}},
+		"head":  {Stdin: "tail", Action: []string{"head", "-n", "1"}},
+		"sleep": {Action: []string{"sleep", "60"}},
+	})
+	spec, _ := parseFileSpec(path)
+	registry = newFileRegistry([]FileSpec{spec})
+
+	goroutines := runtime.NumGoroutine()
+	children := countChildren(t)
+
+	client := dialWS(t, server)
+	stream := func(id, name, command string) string {
+		return `{"v": 1, "type": "stream", "id": "` + id + `", "stream": "` + name + `", "payload": {"command": "` +
+			command + `", "script": "a", "nlines": 10, "entry": {"path": "` + path + `"}}}`
+	}
+
+	// Head exits after the first line, after which tail must be stopped.
+	client.send(stream("1", "a", "grep"), stream("2", "b", "head"), stream("3", "c", "sleep"))
+	client.recvUntil("2", "ended")
+	client.waitLines("a", 2)
+
+	// Switching the command of a stream stops the previous one.
+	client.send(stream("4", "c", "grep"))
+	client.recvUntil("3", "ended")
+	client.waitLines("c", 2)
+	eventually(t, "stopped commands to be reaped", func() bool { return countChildren(t) == children+4 })
+
+	// Closing the session stops all commands and their goroutines.
+	client.conn.Close()
+	eventually(t, "all commands to be reaped", func() bool { return countChildren(t) == children })
+	eventually(t, "goroutines to return", func() bool { return runtime.NumGoroutine() <= goroutines })
+}
+
+func TestProtocolLimits(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("no /proc")
+	}
+
+	specs := map[string]CommandSpec{
+		"timeout":  {Action: []string{"sleep", "60"}, Timeout: "100ms"},
+		"stubborn": {Action: []string{"sh", "-c", "trap '' TERM; while :; do sleep 0.1; done"}, Timeout: "100ms"},
+		"cpu":      {Action: []string{"sh", "-c", "while :; do :; done"}, CPUTime: "1s"},
+		"rate":     {Action: []string{"seq", "10"}, MaxLinesPerSecond: 5},
+		// The limits are applied before the command runs. "$$$$" expands
+		// to the "$$" of the shell.
+		"nice":   {Action: []string{"sh", "-c", "cut -d ' ' -f 19 /proc/$$$$/stat"}, Nice: 5},
+		"memory": {Action: []string{"sh", "-c", "ulimit -v"}, Memory: "64M"},
+	}
+	for name, spec := range specs {
+		if err := spec.parseLimits(); err != nil {
+			t.Fatal(err)
+		}
+		specs[name] = spec
+	}
+	server := startTestServer(t, specs)
+	client := dialWS(t, server)
+	stream := func(id, command string) string {
+		return `{"v": 1, "type": "stream", "id": "` + id + `", "stream": "` + command + `", "payload": {"command": "` +
+			command + `", "entry": {"path": "testdata/ex1/var/log/1.log"}}}`
+	}
+
+	client.send(stream("1", "timeout"))
+	if limit := client.recvUntil("1", "limit"); limit["limit"] != "timeout" {
+		t.Fatalf("%v", limit)
+	}
+	if killed := client.recvUntil("1", "killed"); killed["signal"] != "terminated" || killed["stopped"] != false {
+		t.Fatalf("%v", killed)
+	}
+
+	// Commands that ignore SIGTERM are killed.
+	client.send(stream("6", "stubborn"))
+	if killed := client.recvUntil("6", "killed"); killed["signal"] != "killed" {
+		t.Fatalf("%v", killed)
+	}
+
+	// The output beyond the rate is held back until the next second.
+	start := time.Now()
+	client.send(stream("2", "rate"))
+	if limit := client.recvUntil("2", "limit"); limit["message"] != "seq: output limited to 5 lines per second" {
+		t.Fatalf("%v", limit)
+	}
+	client.recvUntil("2", "ended")
+	if strings.Join(client.lines["rate"], " ") != "1 2 3 4 5 6 7 8 9 10" || time.Since(start) < 500*time.Millisecond {
+		t.Fatalf("%q after %s", client.lines["rate"], time.Since(start))
+	}
+
+	client.send(stream("3", "nice"))
+	client.recvUntil("3", "ended")
+	if strings.Join(client.lines["nice"], " ") != "5" {
+		t.Fatalf("%q", client.lines["nice"])
+	}
+
+	client.send(stream("5", "memory"))
+	client.recvUntil("5", "ended")
+	if strings.Join(client.lines["memory"], " ") != "65536" {
+		t.Fatalf("%q", client.lines["memory"])
+	}
+
+	client.send(stream("4", "cpu"))
+	if limit := client.recvUntil("4", "limit"); limit["limit"] != "cpu-time" {
+		t.Fatalf("%v", limit)
+	}
+}
+
+func TestProtocolAdmission(t *testing.T) {
+	server := startTestServer(t, map[string]CommandSpec{
+		"sleep": {Action: []string{"sleep", "60"}},
+		"yes":   {Action: []string{"yes", "a line that nobody reads"}},
+	}, func(config *Config) {
+		config.MaxSessionsPerClient = 2
+		config.MaxCommandsPerClient = 1
+		config.IdleTimeout = 200 * time.Millisecond
+	})
+	eventually(t, "the sessions of other tests to end", func() bool {
+		admissions.mu.Lock()
+		defer admissions.mu.Unlock()
+		return admissions.totalSessions == 0
+	})
+	stream := func(id, name, command string) string {
+		return `{"v": 1, "type": "stream", "id": "` + id + `", "stream": "` + name + `", "payload": {"command": "` +
+			command + `", "entry": {"path": "testdata/ex1/var/log/1.log"}}}`
+	}
+
+	client := dialWS(t, server)
+	client.send(`{"v": 1, "type": "list", "id": "1"}`)
+	client.recvUntil("1", "listing")
+	other := dialWS(t, server)
+	other.send("list")
+	other.recv()
+
+	// A third session is told why it is rejected in the protocol that it uses.
+	rejected := dialWS(t, server)
+	rejected.send(`{"v": 1, "type": "list", "id": "1"}`)
+	if msg, payload := rejected.recvMessage(); msg.Type != "error" || payload["code"] != errTooManySessions {
+		t.Fatalf("%v %v", msg, payload)
+	}
+	rejected = dialWS(t, server)
+	rejected.send("list")
+	if msg := rejected.recv(); !strings.Contains(msg, "too many sessions from 127.0.0.1 (at most 2)") {
+		t.Fatal(msg)
+	}
+
+	// Commands count against the limit until they finish.
+	client.send(stream("2", "a", "sleep"), stream("3", "b", "sleep"))
+	client.recvUntil("2", "started")
+	if payload := client.recvUntil("3", "error"); payload["code"] != errTooManyStreams {
+		t.Fatalf("%v", payload)
+	}
+	client.send(`{"v": 1, "type": "close", "id": "4", "stream": "a"}`, stream("5", "b", "sleep"))
+	client.recvUntil("5", "started")
+	client.send(`{"v": 1, "type": "close", "id": "6", "stream": "b"}`)
+	client.recvUntil("5", "ended")
+
+	// The commands of a client that stops reading are stopped.
+	children := countChildren(t)
+	other.send(stream("1", "", "yes"))
+	other.recvUntil("1", "started")
+	eventually(t, "yes to be stopped", func() bool {
+		admissions.mu.Lock()
+		defer admissions.mu.Unlock()
+		return admissions.commands["127.0.0.1"] == 0 && countChildren(t) == children
+	})
+}
+
+func TestProtocolReload(t *testing.T) {
+	dir := t.TempDir()
+	pathA, pathB := filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log")
+	os.WriteFile(pathA, []byte("a\n"), 0644)
+	os.WriteFile(pathB, []byte("b\n"), 0644)
+	specA, specB := FileSpec{Path: pathA, Type: "file"}, FileSpec{Path: pathB, Type: "file"}
+
+	server := startTestServer(t, map[string]CommandSpec{
+		"sleep": {Action: []string{"sleep", "60"}},
+		"wait":  {Action: []string{"sleep", "60"}},
+	})
+	registry = newFileRegistry([]FileSpec{specA, specB})
+	client := dialWS(t, server)
+	request := func(id, stream, command, path string) string {
+		return `{"v": 1, "type": "stream", "id": "` + id + `", "stream": "` + stream + `",
+			"payload": {"command": "` + command + `", "entry": {"path": "` + path + `"}}}`
+	}
+
+	client.send(`{"v": 1, "type": "watch", "id": "1"}`)
+	client.send(request("2", "a", "sleep", pathA))
+	client.recvUntil("2", "started")
+	client.send(request("3", "b", "sleep", pathB))
+	client.recvUntil("3", "started")
+	client.send(request("4", "c", "wait", pathA))
+	client.recvUntil("4", "started")
+
+	// Removing a command stops its streams and tells the client.
+	config := *currentConfig()
+	config.AllowCommandNames = []string{"sleep"}
+	config.FileSpecs = []FileSpec{specA, specB}
+	applyConfig(&config)
+	if payload := client.recvUntil("4", "error"); payload["code"] != errUnknownCommand {
+		t.Fatalf("unexpected error %v", payload)
+	}
+	client.recvUntil("4", "ended")
+	if payload := client.recvUntil("", "commands-changed"); !reflect.DeepEqual(payload["commands"], []any{"sleep"}) {
+		t.Fatalf("unexpected commands %v", payload)
+	}
+
+	// Removing a file stops its streams, but not the others.
+	config.FileSpecs = []FileSpec{specA}
+	applyConfig(&config)
+	var stopped, changed bool
+	for !stopped || !changed {
+		msg, payload := client.recvMessage()
+		switch {
+		case msg.ID == "2":
+			t.Fatalf("unexpected event %v", msg)
+		case msg.ID == "3" && msg.Type == "error" && payload["code"] != errUnknownFile:
+			t.Fatalf("unexpected error %v", payload)
+		case msg.ID == "3" && msg.Type == "ended":
+			stopped = true
+		case msg.ID == "1" && msg.Type == "listing-changed":
+			changed = true
+		}
+	}
+
+	client.send(`{"v": 1, "type": "close", "id": "5", "stream": "a"}`)
+	if payload := client.recvUntil("2", "killed"); payload["stopped"] != true {
+		t.Fatalf("unexpected kill status %v", payload)
+	}
+}
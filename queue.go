@@ -0,0 +1,295 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// What happens to the output of a command when the send queue of a client is
+// full: either the oldest queued lines are dropped, or the command is paused
+// until the client catches up.
+const (
+	policyDropOldest = "drop-oldest"
+	policyPause      = "pause"
+)
+
+var sendQueuePolicies = []string{policyDropOldest, policyPause}
+
+// Counters of the send queues of all sessions.
+var sendQueueMetrics struct {
+	// Lines that were dropped because a client could not keep up.
+	DroppedLines atomic.Int64
+	// Times a command was paused because a client could not keep up.
+	Pauses atomic.Int64
+	// Messages that were sent to clients.
+	SentMessages atomic.Int64
+}
+
+// messageSender is the part of a sockjs.Session that sends messages.
+type messageSender interface {
+	Send(msg string) error
+}
+
+// queuedLine is a line of output of a stream that is waiting to be sent.
+type queuedLine struct {
+	typed  bool
+	id     string
+	stream string
+	source string
+	kind   string // "stdout" or "stderr"
+	text   string
+}
+
+// Lines can be sent in the same message if they have the same key.
+func (l *queuedLine) sameKey(other *queuedLine) bool {
+	return l.typed && other.typed && l.id == other.id && l.stream == other.stream &&
+		l.source == other.source && l.kind == other.kind
+}
+
+// queueItem is either a complete message, a line or a marker for lines that
+// were dropped from a stream.
+type queueItem struct {
+	msg     string
+	line    *queuedLine
+	skipped int
+}
+
+// sendQueue is the bounded queue of messages to a websocket client. A single
+// goroutine sends the queued messages, so that a slow client holds up neither
+// the session nor, with the drop-oldest policy, the commands that it streams.
+// Only lines of output count against the limit; replies and events are never
+// dropped.
+type sendQueue struct {
+	session messageSender
+	policy  string
+	limit   int
+	batch   int
+
+	mu      sync.Mutex
+	wake    *sync.Cond
+	items   []queueItem
+	lines   int
+	closed  bool
+	dropped int64
+
+	// Closed and replaced whenever lines are taken out of the queue.
+	drained chan struct{}
+
+	// Whether a message is being sent and when the last message was sent or,
+	// if there was nothing to send, when the queue stopped being empty.
+	sending  bool
+	progress time.Time
+}
+
+// Create the send queue of a session. A limit or batch size of 0 means the
+// default.
+func newSendQueue(session messageSender, policy string, limit, batch int) *sendQueue {
+	q := &sendQueue{
+		session: session,
+		policy:  policy,
+		limit:   cmp.Or(limit, defaultSendQueueSize),
+		batch:   cmp.Or(batch, defaultSendBatchSize),
+		drained: make(chan struct{}),
+	}
+	q.progress = time.Now()
+	q.wake = sync.NewCond(&q.mu)
+	go q.run()
+	return q
+}
+
+// Queue a message that must not be dropped.
+func (q *sendQueue) send(msg string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.waiting()
+	q.items = append(q.items, queueItem{msg: msg})
+	q.wake.Signal()
+}
+
+// Queue a line of output. If the queue is full, the oldest line is dropped or,
+// with the pause policy, the call blocks until there is room, the queue is
+// closed or abort is closed.
+func (q *sendQueue) sendLine(line queuedLine, abort <-chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.policy == policyPause && q.lines >= q.limit {
+		sendQueueMetrics.Pauses.Add(1)
+	}
+	for q.policy == policyPause && q.lines >= q.limit && !q.closed {
+		drained := q.drained
+		q.mu.Unlock()
+		select {
+		case <-drained:
+		case <-abort:
+			q.mu.Lock()
+			return
+		}
+		q.mu.Lock()
+	}
+
+	if q.closed {
+		return
+	}
+	if q.lines >= q.limit {
+		q.dropOldest()
+	}
+	q.waiting()
+	q.items = append(q.items, queueItem{line: &line})
+	q.lines++
+	q.wake.Signal()
+}
+
+// Note that the queue is about to stop being empty.
+func (q *sendQueue) waiting() {
+	if len(q.items) == 0 && !q.sending {
+		q.progress = time.Now()
+	}
+}
+
+// How long messages have been waiting without the client receiving any. A
+// client that does not read the websocket blocks the sending of the current
+// message.
+func (q *sendQueue) stalled() time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 && !q.sending {
+		return 0
+	}
+	return time.Since(q.progress)
+}
+
+// Drop the oldest line and leave a marker in its place. Consecutive drops from
+// the same stream share one marker.
+func (q *sendQueue) dropOldest() {
+	for n, item := range q.items {
+		if item.line == nil || item.skipped > 0 {
+			continue
+		}
+		q.lines--
+		q.dropped++
+		sendQueueMetrics.DroppedLines.Add(1)
+
+		if n > 0 && q.items[n-1].skipped > 0 && q.items[n-1].line.stream == item.line.stream {
+			q.items[n-1].skipped++
+			q.items = append(q.items[:n], q.items[n+1:]...)
+		} else {
+			q.items[n] = queueItem{line: item.line, skipped: 1}
+		}
+		return
+	}
+}
+
+// Stop sending and release all blocked callers. Queued messages are discarded.
+func (q *sendQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.items = nil
+	close(q.drained)
+	q.drained = make(chan struct{})
+	q.wake.Signal()
+}
+
+// The number of lines that were dropped so far.
+func (q *sendQueue) droppedLines() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// Goroutine that sends the queued messages until the queue is closed.
+func (q *sendQueue) run() {
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.wake.Wait()
+		}
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		msg := q.take()
+		q.sending = true
+		q.mu.Unlock()
+
+		q.session.Send(msg)
+		sendQueueMetrics.SentMessages.Add(1)
+
+		q.mu.Lock()
+		q.sending = false
+		q.progress = time.Now()
+		q.mu.Unlock()
+	}
+}
+
+// Take the next message out of the queue. Consecutive lines of the typed
+// protocol with the same key are sent together, up to the batch size.
+func (q *sendQueue) take() string {
+	item := q.items[0]
+	q.items = q.items[1:]
+
+	var msg string
+	switch {
+	case item.skipped > 0:
+		msg = item.line.skippedMessage(item.skipped)
+	case item.line != nil:
+		lines := []string{item.line.text}
+		for len(lines) < q.batch && len(q.items) > 0 && q.items[0].skipped == 0 &&
+			q.items[0].line != nil && q.items[0].line.sameKey(item.line) {
+			lines = append(lines, q.items[0].line.text)
+			q.items = q.items[1:]
+		}
+		q.lines -= len(lines)
+		msg = item.line.message(lines)
+
+		close(q.drained)
+		q.drained = make(chan struct{})
+	default:
+		msg = item.msg
+	}
+
+	if len(q.items) == 0 {
+		q.items = nil
+	}
+	return msg
+}
+
+// Format one or more lines as a message of the line's protocol. Legacy lines
+// are never batched.
+func (l *queuedLine) message(lines []string) string {
+	if !l.typed {
+		stream := "o"
+		if l.kind == "stderr" {
+			stream = "e"
+		}
+		text := lines[0]
+		if l.source != "" {
+			text = l.source + ": " + text
+		}
+		data, _ := json.Marshal([]string{stream, text})
+		return string(data)
+	}
+
+	var payload any = lines[0]
+	if len(lines) > 1 {
+		payload = lines
+	}
+	return formatMessage(Message{Type: l.kind, ID: l.id, Stream: l.stream, Source: l.source}, payload)
+}
+
+// Format the marker for lines that were dropped from a stream.
+func (l *queuedLine) skippedMessage(count int) string {
+	if !l.typed {
+		data, _ := json.Marshal([]string{"e", fmt.Sprintf("tailon: %d lines skipped", count)})
+		return string(data)
+	}
+	return formatMessage(Message{Type: "skipped", ID: l.id, Stream: l.stream}, map[string]int{"count": count})
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A client that receives messages only when told to.
+type slowSender struct {
+	release  chan struct{}
+	messages chan string
+}
+
+func newSlowSender() *slowSender {
+	return &slowSender{release: make(chan struct{}), messages: make(chan string, 100)}
+}
+
+func (s *slowSender) Send(msg string) error {
+	<-s.release
+	s.messages <- msg
+	return nil
+}
+
+func (s *slowSender) receive(t *testing.T) string {
+	s.release <- struct{}{}
+	select {
+	case msg := <-s.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message")
+		return ""
+	}
+}
+
+func TestSendQueueDropOldest(t *testing.T) {
+	sender := newSlowSender()
+	q := newSendQueue(sender, policyDropOldest, 3, 10)
+	defer q.close()
+
+	// Wait until the client is busy with the first message.
+	q.send("hello")
+	for {
+		q.mu.Lock()
+		empty := len(q.items) == 0
+		q.mu.Unlock()
+		if empty {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	dropped := sendQueueMetrics.DroppedLines.Load()
+	for n := 1; n <= 10; n++ {
+		q.sendLine(queuedLine{typed: true, id: "1", stream: "a", kind: "stdout", text: fmt.Sprint(n)}, nil)
+	}
+	q.sendLine(queuedLine{id: "2", kind: "stdout", text: "legacy"}, nil)
+
+	expect := []string{
+		`hello`,
+		`{"v":1,"type":"skipped","id":"1","stream":"a","payload":{"count":8}}`,
+		`{"v":1,"type":"stdout","id":"1","stream":"a","payload":["9","10"]}`,
+		`["o","legacy"]`,
+	}
+	for _, e := range expect {
+		if msg := sender.receive(t); msg != e {
+			t.Fatalf("%s != %s", msg, e)
+		}
+	}
+	if q.droppedLines() != 8 || sendQueueMetrics.DroppedLines.Load()-dropped != 8 {
+		t.Fatalf("unexpected drop count %d", q.droppedLines())
+	}
+}
+
+func TestSendQueuePause(t *testing.T) {
+	sender := newSlowSender()
+	q := newSendQueue(sender, policyPause, 2, 1)
+	defer q.close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for n := 1; n <= 5; n++ {
+			q.sendLine(queuedLine{typed: true, kind: "stdout", text: fmt.Sprint(n)}, nil)
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("the producer was not paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	var lines []string
+	for len(lines) < 5 {
+		msg := Message{}
+		json.Unmarshal([]byte(sender.receive(t)), &msg)
+		var line string
+		json.Unmarshal(msg.Payload, &line)
+		lines = append(lines, line)
+	}
+	<-done
+	if strings.Join(lines, " ") != "1 2 3 4 5" || q.droppedLines() != 0 {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+
+	// A paused producer is released when its stream is closed.
+	abort := make(chan struct{})
+	for n := 0; n < 3; n++ {
+		go q.sendLine(queuedLine{typed: true, kind: "stdout", text: "x"}, abort)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(abort)
+	released := make(chan struct{})
+	go func() {
+		q.sendLine(queuedLine{typed: true, kind: "stdout", text: "y"}, abort)
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the producer was not released")
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	flag "github.com/spf13/pflag"
+)
+
+// reloadNotifier tells the websocket sessions that the config was reloaded.
+// Like changes of the file listing, reloads that happen before a session reads
+// its channel are coalesced.
+type reloadNotifier struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]bool
+}
+
+var reloads = &reloadNotifier{subscribers: make(map[chan struct{}]bool)}
+
+// Subscribe to reloads. Call the returned function to unsubscribe.
+func (n *reloadNotifier) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	n.subscribers[ch] = true
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		delete(n.subscribers, ch)
+		n.mu.Unlock()
+	}
+}
+
+func (n *reloadNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Load the config files again, with the environment and flags, and apply the
+// new config. The current config is kept if the new one has errors. Return
+// whether the config was reloaded.
+func reloadConfig(flags *flag.FlagSet, filespecs []FileSpec) bool {
+	old := currentConfig()
+	config, err := loadConfig(old.ConfigPaths, os.Environ(), flags)
+	if err == nil {
+		finishConfig(config, filespecs)
+		if len(config.FileSpecs) == 0 {
+			err = errors.New("no files specified")
+		}
+	}
+	if err != nil {
+		slog.Error("Cannot reload config", "error", err)
+		return false
+	}
+
+	for _, warning := range config.warnings {
+		slog.Warn("Config: " + warning)
+	}
+	for _, key := range keepRestartOptions(old, config) {
+		slog.Warn("Config: " + key + " cannot be changed without a restart")
+	}
+	applyConfig(config)
+	slog.Info("Reloaded config", "files", len(config.FileSpecs), "commands", len(config.AllowCommandNames))
+	return true
+}
+
+// Swap in a reloaded config. The file listing is refreshed for the new
+// filespecs before the sessions are notified, so that they can stop the
+// streams of files and commands that are gone.
+func applyConfig(config *Config) {
+	setConfig(config)
+	registry.setFileSpecs(config.FileSpecs)
+	reloads.notify()
+}
+
+// The options that are only read at startup.
+var restartOptions = []string{"relative-root", "refresh-interval", "watch-config", "log-level", "log-format", "log-file"}
+
+// Reset the options of a reloaded config that only take effect on restart to
+// their current values. Return the options that were changed.
+func keepRestartOptions(old, config *Config) []string {
+	var res []string
+	oldOptions, newOptions := old.options(), config.options()
+	for _, key := range restartOptions {
+		oldValue, newValue := reflect.ValueOf(oldOptions[key].value).Elem(), reflect.ValueOf(newOptions[key].value).Elem()
+		if !reflect.DeepEqual(oldValue.Interface(), newValue.Interface()) {
+			newValue.Set(oldValue)
+			res = append(res, key)
+		}
+	}
+	if !reflect.DeepEqual(old.Audit, config.Audit) {
+		config.Audit = old.Audit
+		res = append(res, "[audit]")
+	}
+	return res
+}
+
+// Goroutine that sends on changed shortly after one of the config files
+// changes. As with certificates, the parent directories are watched, since
+// config files are often replaced by renaming. The files are replaced by those
+// received on rewatch, e.g. after a reload changed the included files.
+func watchConfigFiles(files []string, rewatch <-chan []string, changed chan<- struct{}) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("Cannot watch config files", "error", err)
+		return
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool)
+	watch := func() {
+		dirs := make(map[string]bool)
+		for _, file := range files {
+			dirs[filepath.Dir(file)] = true
+		}
+		for dir := range watched {
+			if !dirs[dir] {
+				watcher.Remove(dir)
+				delete(watched, dir)
+			}
+		}
+		for dir := range dirs {
+			if !watched[dir] && watcher.Add(dir) == nil {
+				watched[dir] = true
+			}
+		}
+	}
+	watch()
+
+	var pending <-chan time.Time
+	for {
+		select {
+		case files = <-rewatch:
+			watch()
+		case event := <-watcher.Events:
+			if pending == nil && !event.Has(fsnotify.Chmod) && isWatchedFile(event.Name, files) {
+				pending = time.After(watchDelay)
+			}
+		case err := <-watcher.Errors:
+			slog.Warn("Error watching config files", "error", err)
+		case <-pending:
+			pending = nil
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
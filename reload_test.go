@@ -0,0 +1,90 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tailon.toml")
+	write := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("title = 'a'\nrelative-root = '/a/'\n[[files]]\npath = '/var/log/a'")
+
+	config, err := loadConfig([]string{path}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finishConfig(config, nil)
+	setConfig(config)
+	registry = newFileRegistry(config.FileSpecs)
+	t.Cleanup(func() {
+		setConfig(&Config{})
+		registry = newFileRegistry(nil)
+	})
+
+	// Options that are only read at startup keep their values.
+	write("title = 'b'\nrelative-root = '/b/'\nrefresh-interval = '1m'\n[[files]]\npath = '/var/log/b'")
+	if !reloadConfig(nil, nil) {
+		t.Fatal("config not reloaded")
+	}
+	config = currentConfig()
+	if config.Title != "b" || config.RelativeRoot != "/a/" || config.RefreshInterval != 10*time.Second {
+		t.Fatalf("%+v", config)
+	}
+	if !registry.fileAllowed("/var/log/b", nil) || registry.fileAllowed("/var/log/a", nil) {
+		t.Fatal("file listing not updated")
+	}
+
+	// A config with errors is not applied.
+	for _, content := range []string{"title = 'c'\ntail-lines = 'many'", "title = 'c'"} {
+		write(content)
+		if reloadConfig(nil, nil) || currentConfig().Title != "b" {
+			t.Fatalf("%q: invalid config reloaded", content)
+		}
+	}
+}
+
+func TestWatchConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	main, extra := filepath.Join(dir, "tailon.toml"), filepath.Join(dir, "conf.d", "extra.toml")
+	os.Mkdir(filepath.Dir(extra), 0755)
+
+	rewatch := make(chan []string)
+	changed := make(chan struct{}, 1)
+	go watchConfigFiles([]string{main}, rewatch, changed)
+	expect := func(want bool) {
+		t.Helper()
+		select {
+		case <-changed:
+			if !want {
+				t.Fatal("unexpected change")
+			}
+		case <-time.After(4 * watchDelay):
+			if want {
+				t.Fatal("change not noticed")
+			}
+		}
+	}
+
+	// Files that a reload included are watched, and files that are no
+	// longer included are not.
+	time.Sleep(watchDelay)
+	os.WriteFile(extra, []byte("title = 'a'"), 0644)
+	expect(false)
+	rewatch <- []string{main, extra}
+	time.Sleep(watchDelay)
+	os.WriteFile(extra, []byte("title = 'b'"), 0644)
+	expect(true)
+	rewatch <- []string{main}
+	time.Sleep(watchDelay)
+	os.WriteFile(extra, []byte("title = 'c'"), 0644)
+	expect(false)
+	os.WriteFile(main, []byte("title = 'd'"), 0644)
+	expect(true)
+}
@@ -3,18 +3,12 @@
 package main
 
 import (
-	"encoding/json"
-	"github.com/gorilla/handlers"
-	"github.com/gvalkov/tailon/cmd"
 	"github.com/igm/sockjs-go/v3/sockjs"
 	"github.com/shurcooL/httpfs/html/vfstemplate"
 	"github.com/shurcooL/httpgzip"
 	"html/template"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
-	"os/exec"
-	"strconv"
 	"time"
 )
 
@@ -30,19 +24,25 @@ func setupRoutes(relativeroot string) *http.ServeMux {
 	router.Handle(relativeroot+"vfs/", staticHandler)
 	router.Handle(relativeroot+"ws/", sockjsHandler)
 	router.HandleFunc(relativeroot+"files/", downloadHandler)
+	router.HandleFunc(relativeroot+"metrics", metricsHandler)
 	router.HandleFunc(relativeroot+"", indexHandler)
 
 	return router
 }
 
-func setupServer(config *Config, addr string, logger *log.Logger) *http.Server {
+func setupServer(config *Config, addr string) *http.Server {
 	router := setupRoutes(config.RelativeRoot)
-	loggingRouter := handlers.LoggingHandler(os.Stderr, router)
+	// The authenticators are looked up for every request, so that a reloaded
+	// config applies to new requests.
+	authRouter := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHandler(currentConfig().Authenticators, router).ServeHTTP(w, r)
+	})
+	loggingRouter := logHandler(authRouter)
 
 	server := http.Server{
 		Addr:         addr,
 		Handler:      loggingRouter,
-		ErrorLog:     logger,
+		ErrorLog:     slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
@@ -53,22 +53,38 @@ func setupServer(config *Config, addr string, logger *log.Logger) *http.Server {
 
 func indexHandler(w http.ResponseWriter, r *http.Request) {
 	t := template.Must(vfstemplate.ParseFiles(FrontendAssets, nil, "/templates/base.html", "/templates/tailon.html"))
-	t.Execute(w, config)
+
+	// Show only the commands that the user is allowed to run.
+	data := *currentConfig()
+	data.AllowCommandNames = allowedCommands(requestIdentity(r))
+	t.Execute(w, &data)
 }
 
 func downloadHandler(w http.ResponseWriter, r *http.Request) {
-	if !config.AllowDownload {
+	if !currentConfig().AllowDownload {
 		http.Error(w, "downloads forbidden by server", http.StatusForbidden)
 		return
 	}
 
-	path := r.URL.Query()["path"][0]
-	if !fileAllowed(path) {
-		log.Printf("warn: attempt to access unknown file: %s", path)
+	path := r.URL.Query().Get("path")
+	if !registry.fileAllowed(path, requestIdentity(r)) {
+		requestLogger(r).Warn("Attempt to download unknown file", "path", path)
 		http.Error(w, "unknown file", http.StatusNotFound)
 		return
 	}
-	http.ServeFile(w, r, path)
+	counter := &countingWriter{ResponseWriter: w, code: http.StatusOK}
+	http.ServeFile(counter, r, path)
+	metrics.Downloads.Add(1)
+	metrics.DownloadBytes.Add(counter.n)
+
+	auditLog.log(AuditEvent{
+		Event:  auditDownload,
+		Remote: r.RemoteAddr,
+		User:   identityUser(requestIdentity(r)),
+		Path:   path,
+		Code:   counter.code,
+		Bytes:  counter.n,
+	})
 }
 
 func noCacheControl(h http.Handler) http.Handler {
@@ -86,139 +102,55 @@ type FrontendCommand struct {
 	Script  string
 	Entry   ListEntry
 	Nlines  int
+
+	// Merged streams follow the given entries, or all files of a group,
+	// instead of Entry. With Interleave, lines are ordered by their timestamp
+	// within a reorder window of Window milliseconds.
+	Entries    []ListEntry
+	Group      string
+	Interleave bool
+	Window     int
+
+	// The values of the params of the command, keyed by name.
+	Params map[string]any
+
+	// The identity of the client, which is set by the server.
+	identity *Identity
 }
 
 // The main sockjs handler.
 func wsHandler(session sockjs.Session) {
+	client := clientAddr(session.Request())
+	if err := admissions.admitSession(client); err != nil {
+		sessionLogger(session).Warn("Rejected session", "error", err)
+		rejectSession(session, err.Error())
+		return
+	}
+	defer admissions.releaseSession(client)
+	metrics.Sessions.Add(1)
+	defer metrics.Sessions.Add(-1)
+
 	messages := make(chan string)
 	done := make(chan struct{})
 	defer close(done)
 
-	go wsWriter(session, messages, done)
+	// The writer returns early if the session is closed for being idle.
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		wsWriter(session, messages, done)
+	}()
 
 	for {
-		if msg, err := session.Recv(); err == nil {
-			messages <- msg
-			continue
-		} else {
-			log.Print(err)
-		}
-		break
-	}
-}
-
-// Goroutine handling received messages and streaming of file contents.
-func wsWriter(session sockjs.Session, messages chan string, done <-chan struct{}) {
-	// The processes that make up the pipeline. The stdout of procA is connected to the stdin of procB.
-	var procA *exec.Cmd
-	var procB *cmd.Cmd
-
-	cmdOptions := cmd.Options{Buffered: false, Streaming: true}
-
-	for {
-		select {
-		case msg := <-messages:
-			if msg == "list" {
-				lst := createListing(config.FileSpecs)
-				b, err := json.Marshal(lst)
-				if err != nil {
-					log.Println("error: ", err)
-				}
-				session.Send(string(b))
-			} else if msg[0] == '{' {
-				msgJSON := FrontendCommand{}
-				json.Unmarshal([]byte(msg), &msgJSON)
-
-				if !fileAllowed(msgJSON.Entry.Path) {
-					log.Print("Unknown file: ", msgJSON.Entry.Path)
-					continue
-				}
-
-				killProcs(procA, procB)
-
-				// Check if the command is using another command for stdin.
-				stdinSource := config.CommandSpecs[msgJSON.Command].Stdin
-				if stdinSource != "" {
-					actionA := config.CommandSpecs[stdinSource].Action
-					actionA = expandCommandArgs(actionA, msgJSON)
-					procA = exec.Command(actionA[0], actionA[1:]...)
-					log.Print("Running command: ", actionA)
-				}
-
-				actionB := config.CommandSpecs[msgJSON.Command].Action
-				actionB = expandCommandArgs(actionB, msgJSON)
-				procB = cmd.NewCmdOptions(cmdOptions, actionB[0], actionB[1:]...)
-				log.Print("Running command: ", actionB)
-
-				// Start streaming procB's stdout and stderr to the client.
-				go streamOutput(procA, procB, session)
-			}
-		case <-done:
-			killProcs(procA, procB)
+		msg, err := session.Recv()
+		if err != nil {
+			sessionLogger(session).Debug("Session closed", "error", err)
 			return
 		}
-	}
-}
-
-// Expands the variables in main.CommandSpec.Action with the values in the
-// frontend command. For example:
-//
-//	["tail", "-n", "$lines", "-F", "$path"] -> ["tail", "-n", "10", "-F", "f1.txt"]
-func expandCommandArgs(action []string, cmd FrontendCommand) []string {
-	var res = make([]string, 0)
-
-	for _, arg := range action {
-		switch arg {
-		case "$lines":
-			res = append(res, strconv.Itoa(cmd.Nlines))
-		case "$path":
-			res = append(res, cmd.Entry.Path)
-		case "$script":
-			res = append(res, cmd.Script)
-		default:
-			res = append(res, arg)
-		}
-	}
-
-	return res
-}
-
-// Goroutine that streams command stdout and stderr to the client.
-func streamOutput(procA *exec.Cmd, procB *cmd.Cmd, session sockjs.Session) {
-	if procA != nil {
-		procB.Stdin, _ = procA.StdoutPipe()
-		procA.Start()
-	}
-
-	statusChan := procB.Start()
-
-	for {
 		select {
-		case line := <-procB.Stdout:
-			msg := []string{"o", line}
-			data, _ := json.Marshal(msg)
-			session.Send(string(data))
-		case line := <-procB.Stderr:
-			msg := []string{"e", line}
-			data, _ := json.Marshal(msg)
-			session.Send(string(data))
-		case <-statusChan:
-		}
-	}
-}
-
-func killProcs(procA *exec.Cmd, procB *cmd.Cmd) {
-	if procA != nil {
-		log.Printf("Stopping pid %d", procA.Process.Pid)
-		procA.Process.Kill()
-		procA.Wait()
-	}
-
-	if procB != nil {
-		log.Printf("Stopping pid %d", procB.Status().PID)
-		if procB.Stdin != nil {
-			procB.Stdin.Close()
+		case messages <- msg:
+		case <-writerDone:
+			return
 		}
-		procB.Stop()
 	}
 }
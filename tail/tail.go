@@ -0,0 +1,446 @@
+// Package tail follows files as they grow, similar to "tail -n N -F". A Hub
+// keeps a single reader per followed path and fans out every new line to all
+// subscriptions on that path. Changes are detected with inotify (through
+// fsnotify), with periodic polling as a fallback for filesystems and platforms
+// on which inotify is not available.
+//
+// Like "tail -F", a follower keeps retrying a file that does not exist or has
+// become inaccessible and detects rotation (the path now refers to a different
+// file) and truncation (the file shrank).
+//
+//	hub := tail.NewHub(tail.DefaultOptions)
+//	sub := hub.Follow("/var/log/messages", 10)
+//	defer sub.Close()
+//
+//	for line := range sub.Stdout {
+//	    fmt.Println(line)
+//	}
+package tail
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Options represents customizations for NewHub.
+type Options struct {
+	// Interval at which followed files are checked for changes. Files are
+	// checked immediately on inotify events, so this only matters when
+	// inotify is not available or misses an event (e.g. on network
+	// filesystems).
+	PollInterval time.Duration
+
+	// Maximum number of lines that are queued for a subscription that is
+	// not being read. When the queue is full, the oldest lines are dropped
+	// and a notice with the number of skipped lines is sent on Stderr.
+	MaxPending int
+}
+
+// DefaultOptions are the options used by tailon.
+var DefaultOptions = Options{
+	PollInterval: time.Second,
+	MaxPending:   10000,
+}
+
+const (
+	// Size of the blocks in which files are read backwards when seeking to
+	// the last N lines.
+	seekBlockSize = 8192
+
+	// Lines longer than this are split.
+	maxLineSize = 65536
+)
+
+// Hub shares followers between subscriptions to the same path. All methods
+// are safe to call from multiple goroutines.
+type Hub struct {
+	options   Options
+	mu        sync.Mutex
+	followers map[string]*follower
+}
+
+// NewHub creates a new hub with the given options.
+func NewHub(options Options) *Hub {
+	return &Hub{
+		options:   options,
+		followers: make(map[string]*follower),
+	}
+}
+
+// Follow subscribes to the lines appended to path, starting with the last
+// nlines lines of the file. The file does not need to exist. The returned
+// subscription must be closed when no longer needed.
+func (h *Hub) Follow(path string, nlines int) *Subscription {
+	path = filepath.Clean(path)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, ok := h.followers[path]
+	if !ok {
+		f = newFollower(path, h.options)
+		h.followers[path] = f
+		f.check()
+		go f.run()
+	}
+
+	return f.subscribe(h, nlines)
+}
+
+// Followers returns the number of paths that are currently being followed.
+func (h *Hub) Followers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.followers)
+}
+
+func (h *Hub) unsubscribe(f *follower, sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f.mu.Lock()
+	delete(f.subs, sub)
+	empty := len(f.subs) == 0
+	f.mu.Unlock()
+
+	if empty {
+		delete(h.followers, f.path)
+		close(f.stop)
+	}
+}
+
+// --------------------------------------------------------------------------
+
+// A follower reads a single file and delivers new lines to its subscriptions.
+type follower struct {
+	path    string
+	options Options
+	stop    chan struct{}
+
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	file    *os.File
+	info    os.FileInfo
+	offset  int64  // offset up to which the file has been read
+	partial []byte // incomplete last line
+	opened  bool   // the file has been checked at least once
+}
+
+func newFollower(path string, options Options) *follower {
+	return &follower{
+		path:    path,
+		options: options,
+		stop:    make(chan struct{}),
+		subs:    make(map[*Subscription]struct{}),
+	}
+}
+
+func (f *follower) run() {
+	defer func() {
+		f.mu.Lock()
+		if f.file != nil {
+			f.file.Close()
+			f.file = nil
+		}
+		f.mu.Unlock()
+	}()
+
+	// Watch the parent directory rather than the file itself, so that
+	// rotation, removal and creation of the file are also noticed.
+	var events chan fsnotify.Event
+	if watcher, err := fsnotify.NewWatcher(); err == nil {
+		defer watcher.Close()
+		if err := watcher.Add(filepath.Dir(f.path)); err == nil {
+			events = watcher.Events
+		}
+	}
+
+	ticker := time.NewTicker(f.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if filepath.Clean(event.Name) == f.path {
+				f.check()
+			}
+		case <-ticker.C:
+			f.check()
+		case <-f.stop:
+			return
+		}
+	}
+}
+
+// Check the followed path for rotation, truncation and new data.
+func (f *follower) check() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	defer func() { f.opened = true }()
+
+	if f.file != nil && (err != nil || !os.SameFile(info, f.info)) {
+		// Deliver whatever was written to the old file before letting go of it.
+		f.read()
+		f.file.Close()
+		f.file = nil
+		f.flushPartial()
+
+		if err != nil {
+			f.notice("tail: '%s' has become inaccessible", f.path)
+		}
+	}
+
+	if f.file == nil {
+		if err != nil {
+			return
+		}
+
+		file, err := os.Open(f.path)
+		if err != nil {
+			return
+		}
+		f.info, _ = file.Stat()
+		f.file = file
+
+		// If the file exists when following starts, reading starts at its
+		// end. The last lines are read separately for every new subscription.
+		// Files that appear later are followed from their beginning.
+		if f.opened {
+			f.offset = 0
+			f.notice("tail: '%s' has appeared;  following new file", f.path)
+		} else {
+			f.offset = f.info.Size()
+		}
+	}
+
+	if info, err := f.file.Stat(); err == nil && info.Size() < f.offset {
+		f.offset = 0
+		f.partial = nil
+		f.notice("tail: %s: file truncated", f.path)
+	}
+
+	f.read()
+}
+
+// Read from the current offset to the end of the file and deliver all
+// complete lines.
+func (f *follower) read() {
+	buf := make([]byte, 32768)
+	for {
+		n, err := f.file.ReadAt(buf, f.offset)
+		if n > 0 {
+			f.offset += int64(n)
+			f.deliver(buf[:n])
+		}
+		if err != nil || n < len(buf) {
+			return
+		}
+	}
+}
+
+func (f *follower) deliver(data []byte) {
+	var lines []string
+
+	for {
+		idx := bytes.IndexByte(data, '\n')
+		if idx < 0 {
+			break
+		}
+		f.partial = append(f.partial, data[:idx]...)
+		lines = append(lines, strings.TrimSuffix(string(f.partial), "\r"))
+		f.partial = f.partial[:0]
+		data = data[idx+1:]
+	}
+
+	f.partial = append(f.partial, data...)
+	if len(f.partial) >= maxLineSize {
+		lines = append(lines, string(f.partial))
+		f.partial = f.partial[:0]
+	}
+
+	if len(lines) > 0 {
+		for sub := range f.subs {
+			sub.push(lines)
+		}
+	}
+}
+
+// Deliver an unterminated last line, e.g. before switching to a new file.
+func (f *follower) flushPartial() {
+	if len(f.partial) > 0 {
+		line := string(f.partial)
+		f.partial = f.partial[:0]
+		for sub := range f.subs {
+			sub.push([]string{line})
+		}
+	}
+}
+
+func (f *follower) notice(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	for sub := range f.subs {
+		sub.notice(msg)
+	}
+}
+
+func (f *follower) subscribe(hub *Hub, nlines int) *Subscription {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub := newSubscription(hub, f)
+
+	// The incomplete last line has not been delivered yet, so it is not part
+	// of the initial lines either.
+	if f.file != nil {
+		end := f.offset - int64(len(f.partial))
+		sub.push(lastLines(f.file, end, nlines))
+	}
+
+	f.subs[sub] = struct{}{}
+	go sub.pump()
+	return sub
+}
+
+// Return the last n lines of the file that end before offset end.
+func lastLines(file *os.File, end int64, n int) []string {
+	if n <= 0 || end <= 0 {
+		return nil
+	}
+
+	var buf []byte
+	pos := end
+	for pos > 0 && bytes.Count(buf, []byte{'\n'}) <= n {
+		size := min(seekBlockSize, pos)
+		pos -= size
+
+		block := make([]byte, size, int(size)+len(buf))
+		if _, err := file.ReadAt(block, pos); err != nil {
+			return nil
+		}
+		buf = append(block, buf...)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// --------------------------------------------------------------------------
+
+// Subscription receives the lines of a followed file. Lines are sent on
+// Stdout and notices about the state of the file (such as truncation or
+// rotation) are sent on Stderr, in the same format as "tail -F". Both channels
+// are closed after Close is called.
+type Subscription struct {
+	Stdout <-chan string
+	Stderr <-chan string
+
+	hub      *Hub
+	follower *follower
+	stdout   chan string
+	stderr   chan string
+
+	mu       sync.Mutex
+	pending  []string
+	skipped  int
+	wake     chan struct{}
+	done     chan struct{}
+	pumpDone chan struct{}
+	once     sync.Once
+}
+
+func newSubscription(hub *Hub, f *follower) *Subscription {
+	sub := &Subscription{
+		hub:      hub,
+		follower: f,
+		stdout:   make(chan string),
+		stderr:   make(chan string, 16),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		pumpDone: make(chan struct{}),
+	}
+	sub.Stdout = sub.stdout
+	sub.Stderr = sub.stderr
+	return sub
+}
+
+// Close stops the subscription. The follower of the path is stopped when its
+// last subscription is closed. Close is idempotent.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.unsubscribe(s.follower, s)
+		close(s.done)
+		<-s.pumpDone
+		close(s.stderr)
+	})
+}
+
+// Queue lines for delivery, dropping the oldest ones if the queue is full.
+func (s *Subscription) push(lines []string) {
+	s.mu.Lock()
+	s.pending = append(s.pending, lines...)
+	if max := s.follower.options.MaxPending; max > 0 && len(s.pending) > max {
+		drop := len(s.pending) - max
+		s.skipped += drop
+		s.pending = s.pending[drop:]
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Send a notice without blocking. Notices are dropped if nobody reads them.
+func (s *Subscription) notice(msg string) {
+	select {
+	case s.stderr <- msg:
+	default:
+	}
+}
+
+// Goroutine that moves queued lines to the Stdout channel.
+func (s *Subscription) pump() {
+	defer close(s.pumpDone)
+	defer close(s.stdout)
+
+	for {
+		s.mu.Lock()
+		lines, skipped := s.pending, s.skipped
+		s.pending, s.skipped = nil, 0
+		s.mu.Unlock()
+
+		if skipped > 0 {
+			s.notice(fmt.Sprintf("tail: %d lines skipped", skipped))
+		}
+
+		for _, line := range lines {
+			select {
+			case s.stdout <- line:
+			case <-s.done:
+				return
+			}
+		}
+
+		select {
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+	}
+}
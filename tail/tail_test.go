@@ -0,0 +1,114 @@
+package tail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testOptions = Options{PollInterval: 10 * time.Millisecond, MaxPending: 100}
+
+func appendFile(t *testing.T, path, data string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(data)
+	f.Close()
+}
+
+func expectLines(t *testing.T, ch <-chan string, expect ...string) {
+	t.Helper()
+	var lines []string
+	for range expect {
+		select {
+		case line := <-ch:
+			lines = append(lines, line)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout: got %q, expected %q", lines, expect)
+		}
+	}
+	if strings.Join(lines, "\n") != strings.Join(expect, "\n") {
+		t.Fatalf("%q != %q", lines, expect)
+	}
+}
+
+func TestFollowLastLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	appendFile(t, path, "1\n2\n3\n4\n5\n")
+
+	hub := NewHub(testOptions)
+	sub := hub.Follow(path, 2)
+	defer sub.Close()
+	expectLines(t, sub.Stdout, "4", "5")
+
+	appendFile(t, path, "6\n7")
+	expectLines(t, sub.Stdout, "6")
+	appendFile(t, path, "\n")
+	expectLines(t, sub.Stdout, "7")
+
+	// A second subscription shares the follower, but gets its own initial lines.
+	sub2 := hub.Follow(path, 3)
+	expectLines(t, sub2.Stdout, "5", "6", "7")
+	if hub.Followers() != 1 {
+		t.Fatalf("%d followers != 1", hub.Followers())
+	}
+
+	appendFile(t, path, "8\n")
+	expectLines(t, sub.Stdout, "8")
+	expectLines(t, sub2.Stdout, "8")
+
+	sub2.Close()
+	sub.Close()
+	if hub.Followers() != 0 {
+		t.Fatalf("%d followers != 0", hub.Followers())
+	}
+	if _, ok := <-sub.Stdout; ok {
+		t.Fatal("stdout not closed")
+	}
+}
+
+func TestFollowTruncateAndRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.log")
+	appendFile(t, path, "1\n")
+
+	hub := NewHub(testOptions)
+	sub := hub.Follow(path, 10)
+	defer sub.Close()
+	expectLines(t, sub.Stdout, "1")
+
+	os.Truncate(path, 0)
+	time.Sleep(50 * time.Millisecond)
+	appendFile(t, path, "2\n")
+	expectLines(t, sub.Stdout, "2")
+	expectLines(t, sub.Stderr, "tail: "+path+": file truncated")
+
+	os.Rename(path, path+".1")
+	appendFile(t, path, "3\n")
+	expectLines(t, sub.Stdout, "3")
+}
+
+func TestFollowMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+
+	hub := NewHub(testOptions)
+	sub := hub.Follow(path, 10)
+	defer sub.Close()
+
+	appendFile(t, path, "1\n2\n")
+	expectLines(t, sub.Stdout, "1", "2")
+}
+
+func TestFollowSkipped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.log")
+	appendFile(t, path, strings.Repeat("x\n", 150))
+
+	hub := NewHub(testOptions)
+	sub := hub.Follow(path, 150)
+	defer sub.Close()
+
+	expectLines(t, sub.Stderr, "tail: 50 lines skipped")
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pelletier/go-toml"
+)
+
+// ListenSpec is an address to listen on, with optional TLS settings. These are
+// mapped to the listen-addr and --bind addresses or the [[listen]] elements in
+// the config file.
+type ListenSpec struct {
+	Addr         string
+	CertFile     string `mapstructure:"cert-file"`
+	KeyFile      string `mapstructure:"key-file"`
+	MinVersion   string `mapstructure:"min-version"`
+	ClientCAFile string `mapstructure:"client-ca-file"`
+	ClientAuth   string `mapstructure:"client-auth"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"require": tls.RequireAndVerifyClientCert,
+	"request": tls.VerifyClientCertIfGiven,
+}
+
+// Parse the [[listen]] array of tables of a config file. For example:
+//
+//	[[listen]]
+//	addr = ":8443"
+//	cert-file = "/etc/tailon/cert.pem"
+//	key-file = "/etc/tailon/key.pem"
+func parseListenTables(cfg *toml.Tree) ([]ListenSpec, error) {
+	var specs []ListenSpec
+
+	switch tables := cfg.Get("listen").(type) {
+	case nil:
+	case []*toml.Tree:
+		for n, table := range tables {
+			spec := ListenSpec{}
+			decoder, _ := mapstructure.NewDecoder(&mapstructure.DecoderConfig{ErrorUnused: true, Result: &spec})
+			err := decoder.Decode(table.ToMap())
+			if err == nil {
+				err = spec.validate()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("[[listen]] entry %d at line %d: %s", n+1, table.Position().Line, err)
+			}
+			specs = append(specs, spec)
+		}
+	default:
+		return nil, fmt.Errorf("listen at line %d: expected an array of tables", cfg.GetPosition("listen").Line)
+	}
+
+	return specs, nil
+}
+
+func (spec ListenSpec) validate() error {
+	if spec.Addr == "" {
+		return fmt.Errorf("missing addr")
+	}
+	if (spec.CertFile == "") != (spec.KeyFile == "") {
+		return fmt.Errorf("cert-file and key-file must be set together")
+	}
+	if !spec.isTLS() && (spec.MinVersion != "" || spec.ClientCAFile != "" || spec.ClientAuth != "") {
+		return fmt.Errorf("min-version, client-ca-file and client-auth require cert-file and key-file")
+	}
+	if _, ok := tlsVersions[spec.MinVersion]; spec.MinVersion != "" && !ok {
+		return fmt.Errorf("invalid min-version %q (expected one of 1.0, 1.1, 1.2, 1.3)", spec.MinVersion)
+	}
+	if _, ok := clientAuthTypes[spec.ClientAuth]; spec.ClientAuth != "" && !ok {
+		return fmt.Errorf("invalid client-auth %q (expected require or request)", spec.ClientAuth)
+	}
+	if spec.ClientAuth != "" && spec.ClientCAFile == "" {
+		return fmt.Errorf("client-auth requires client-ca-file")
+	}
+	return nil
+}
+
+func (spec ListenSpec) isTLS() bool {
+	return spec.CertFile != ""
+}
+
+// --------------------------------------------------------------------------
+
+// tlsReloader holds the certificate and client CA bundle of a listener and
+// reloads them when they change on disk or on SIGHUP. Connections that are
+// already established keep using the old certificate.
+type tlsReloader struct {
+	spec ListenSpec
+
+	mu     sync.RWMutex
+	config *tls.Config
+}
+
+// Create the TLS config of a listener. The returned config picks up the
+// latest certificate and client CA bundle for every new connection, until stop
+// is closed.
+func newTLSConfig(spec ListenSpec, stop <-chan struct{}) (*tls.Config, error) {
+	reloader := &tlsReloader{spec: spec}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	go reloader.watch(stop)
+
+	return &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			reloader.mu.RLock()
+			defer reloader.mu.RUnlock()
+			return reloader.config, nil
+		},
+	}, nil
+}
+
+func (r *tlsReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.spec.CertFile, r.spec.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		// The websocket upgrade needs HTTP/1.1.
+		NextProtos: []string{"http/1.1"},
+	}
+	if r.spec.MinVersion != "" {
+		config.MinVersion = tlsVersions[r.spec.MinVersion]
+	}
+
+	if r.spec.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.spec.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%s: no certificates found", r.spec.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		if r.spec.ClientAuth != "" {
+			config.ClientAuth = clientAuthTypes[r.spec.ClientAuth]
+		}
+	}
+
+	r.mu.Lock()
+	r.config = config
+	r.mu.Unlock()
+	return nil
+}
+
+// Goroutine that reloads the certificates on SIGHUP or when one of the files
+// changes. The parent directories are watched, since certificates are often
+// replaced by renaming or by swapping symlinks (e.g. Kubernetes secrets).
+// Returns when stop is closed.
+func (r *tlsReloader) watch(stop <-chan struct{}) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	files := []string{r.spec.CertFile, r.spec.KeyFile, r.spec.ClientCAFile}
+
+	var events chan fsnotify.Event
+	if watcher, err := fsnotify.NewWatcher(); err == nil {
+		defer watcher.Close()
+		for _, file := range files {
+			if file != "" {
+				watcher.Add(filepath.Dir(file))
+			}
+		}
+		events = watcher.Events
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-hup:
+		case event := <-events:
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Chmod) || !isWatchedFile(event.Name, files) {
+				continue
+			}
+		}
+
+		if err := r.reload(); err != nil {
+			slog.Error("Cannot reload certificate", "addr", r.spec.Addr, "error", err)
+		} else {
+			slog.Info("Reloaded certificate", "addr", r.spec.Addr)
+		}
+	}
+}
+
+func isWatchedFile(name string, files []string) bool {
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		if filepath.Clean(name) == filepath.Clean(file) || strings.HasPrefix(filepath.Base(name), "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// --------------------------------------------------------------------------
+
+// clientCertAuth identifies users by the verified client certificate of a
+// mutual TLS connection. The common name of the subject is used as the user
+// name and the organizational units as groups.
+type clientCertAuth struct{}
+
+func (a *clientCertAuth) Authenticate(r *http.Request) *Identity {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return nil
+	}
+
+	cert := r.TLS.VerifiedChains[0][0]
+	if cert.Subject.CommonName == "" {
+		return nil
+	}
+
+	return &Identity{
+		User:    cert.Subject.CommonName,
+		Groups:  cert.Subject.OrganizationalUnit,
+		Subject: cert.Subject.String(),
+		Method:  "client-cert",
+	}
+}
+
+func (a *clientCertAuth) Challenge() string {
+	return ""
+}
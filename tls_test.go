@@ -0,0 +1,158 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// Create a certificate signed by parent (or self-signed if parent is nil) and
+// write it and its key to dir.
+func writeCert(t *testing.T, dir, name string, template *x509.Certificate, parent *tls.Certificate) tls.Certificate {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := template, any(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+	os.WriteFile(filepath.Join(dir, name+".pem"), certPEM, 0644)
+	os.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0600)
+
+	cert, _ := tls.X509KeyPair(certPEM, keyPEM)
+	cert.Leaf, _ = x509.ParseCertificate(der)
+	return cert
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := writeCert(t, dir, "ca", &x509.Certificate{
+		Subject: pkix.Name{CommonName: "ca"}, IsCA: true, BasicConstraintsValid: true,
+		KeyUsage: x509.KeyUsageCertSign,
+	}, nil)
+	writeCert(t, dir, "server", &x509.Certificate{
+		Subject: pkix.Name{CommonName: "server"}, IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, &ca)
+	client := writeCert(t, dir, "client", &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "alice", OrganizationalUnit: []string{"ops"}},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &ca)
+
+	cfg, _ := toml.Load(`
+	[[listen]]
+	addr = "127.0.0.1:0"
+	cert-file = "` + dir + `/server.pem"
+	key-file = "` + dir + `/server.key"
+	client-ca-file = "` + dir + `/ca.pem"
+	min-version = "1.3"
+	`)
+	specs, err := parseListenTables(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	tlsConfig, err := newTLSConfig(specs[0], stop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, _ := net.Listen("tcp", specs[0].Addr)
+	defer listener.Close()
+
+	auth := &clientCertAuth{}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity := auth.Authenticate(r)
+		w.Write([]byte(identity.User + ":" + strings.Join(identity.Groups, ",") + ":" + identity.Subject))
+	})}
+	go server.Serve(tls.NewListener(listener, tlsConfig))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	url := "https://" + listener.Addr().String() + "/"
+
+	// Clients without a certificate are rejected.
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	if _, err := httpClient.Get(url); err == nil {
+		t.Fatal("expected handshake error without client certificate")
+	}
+
+	httpClient = &http.Client{Transport: &http.Transport{ForceAttemptHTTP2: true, TLSClientConfig: &tls.Config{
+		RootCAs: pool, Certificates: []tls.Certificate{client},
+	}}}
+	res, err := httpClient.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := make([]byte, 100)
+	n, _ := res.Body.Read(body)
+	if string(body[:n]) != "alice:ops:CN=alice,OU=ops" {
+		t.Fatalf("unexpected identity: %q", body[:n])
+	}
+	if res.TLS.Version != tls.VersionTLS13 {
+		t.Fatalf("unexpected version: %x", res.TLS.Version)
+	}
+	// HTTP/2 is not offered, since websockets cannot be upgraded over it.
+	if res.ProtoMajor != 1 {
+		t.Fatalf("unexpected protocol: %s", res.Proto)
+	}
+
+	errors := map[string]string{
+		"[[listen]]\nport = 1":                                                  "[[listen]] entry 1 at line 1: 1 error(s) decoding",
+		"[[listen]]\naddr = ':1'\ncert-file = 'a'":                              "[[listen]] entry 1 at line 1: cert-file and key-file",
+		"[[listen]]\naddr = ':1'\nmin-version = '1.2'":                          "[[listen]] entry 1 at line 1: min-version, client-ca-file",
+		"[[listen]]\naddr = ':1'\ncert-file='a'\nkey-file='b'\nmin-version='2'": "[[listen]] entry 1 at line 1: invalid min-version",
+		"listen = ':1'": "listen at line 1: expected an array of tables",
+	}
+	for content, prefix := range errors {
+		cfg, _ := toml.Load(content)
+		if _, err := parseListenTables(cfg); err == nil || !strings.HasPrefix(err.Error(), prefix) {
+			t.Fatalf("%q: %v does not start with %q", content, err, prefix)
+		}
+	}
+}
+
+func TestTLSReloaderStop(t *testing.T) {
+	dir := t.TempDir()
+	writeCert(t, dir, "server", &x509.Certificate{Subject: pkix.Name{CommonName: "server"}}, nil)
+	spec := ListenSpec{Addr: "127.0.0.1:0", CertFile: dir + "/server.pem", KeyFile: dir + "/server.key"}
+	config := &Config{RelativeRoot: "/", ListenSpecs: []ListenSpec{spec}}
+
+	// Every restart of the listener stops the reloader of the previous one.
+	before := runtime.NumGoroutine()
+	servers := make(map[ListenSpec]*runningServer)
+	for range 5 {
+		if !updateServers(servers, config) || len(servers) != 1 {
+			t.Fatal("cannot start server")
+		}
+		updateServers(servers, &Config{RelativeRoot: "/"})
+	}
+	eventually(t, "reloaders to stop", func() bool { return runtime.NumGoroutine() <= before })
+}